@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bhatti/api-mock-service/internal/repository"
+	"github.com/bhatti/api-mock-service/internal/types"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var consolidateMinSize int
+var consolidateApply bool
+
+// consolidateCmd collapses recorded scenarios into parameterized templates
+var consolidateCmd = &cobra.Command{
+	Use:   "consolidate",
+	Short: "Consolidates recorded scenarios into parameterized templates",
+	Long: `Clusters recorded scenarios by method, status and path shape, infers path params
+(numeric ids, UUIDs, slugs) and merges bodies into a single template per cluster.
+Fields that vary are generated and fields that are constant are asserted.
+
+Without --apply the command only reports the templates it would create.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		serverConfig, err := types.NewConfiguration(httpPort, proxyPort, dataDir, types.NewVersion(Version, Commit, Date))
+		if err != nil {
+			log.Errorf("failed to create config: %s", err)
+			os.Exit(1)
+		}
+		scenarioRepo, _, _, _, err := buildRepos(serverConfig)
+		if err != nil {
+			log.Errorf("failed to setup scenario repository %s", err)
+			os.Exit(2)
+		}
+		report, err := repository.ConsolidateScenarios(scenarioRepo, group, consolidateMinSize, consolidateApply)
+		if err != nil {
+			log.Errorf("failed to consolidate scenarios: %s", err)
+			os.Exit(3)
+		}
+		if outputJSON {
+			b, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(b))
+		} else {
+			printConsolidationReport(report)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(consolidateCmd)
+
+	consolidateCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file")
+	consolidateCmd.Flags().StringVar(&dataDir, "dataDir", "", "data dir to store API contracts and fixtures")
+	consolidateCmd.Flags().StringVar(&group, "group", "", "group of scenarios to consolidate (default all groups)")
+	consolidateCmd.Flags().IntVar(&consolidateMinSize, "min-size", types.DefaultConsolidationMinSize, "minimum number of scenarios to build a template")
+	consolidateCmd.Flags().BoolVar(&consolidateApply, "apply", false, "save templates and remove consolidated scenarios")
+	consolidateCmd.Flags().BoolVar(&outputJSON, "json", false, "output report as JSON instead of human-readable table")
+}
+
+// printConsolidationReport prints a human-friendly consolidation report.
+func printConsolidationReport(report *types.ConsolidationReport) {
+	sep := "──────────────────────────────────────────────────────────────"
+	fmt.Printf("\n%s\n", colorize("CONSOLIDATION REPORT", ansiBold))
+	fmt.Println(colorize(sep, ansiBold))
+	consolidated := 0
+	for _, cluster := range report.Clusters {
+		consolidated += len(cluster.Sources)
+		fmt.Printf("\n%s %s (%d) ← %d scenarios\n",
+			colorize(string(cluster.Method), ansiGreen), cluster.Path, cluster.StatusCode, len(cluster.Sources))
+		if len(cluster.GeneratedFields) > 0 {
+			fmt.Printf("  generated: %s\n", strings.Join(cluster.GeneratedFields, ", "))
+		}
+		if len(cluster.AssertedFields) > 0 {
+			fmt.Printf("  asserted:  %s\n", strings.Join(cluster.AssertedFields, ", "))
+		}
+	}
+	fmt.Println(colorize(sep, ansiBold))
+	summary := fmt.Sprintf("%d scenarios scanned, %d consolidated into %d templates",
+		report.Scanned, consolidated, len(report.Clusters))
+	if report.Applied {
+		fmt.Println(colorize(summary+" (applied)", ansiGreen))
+	} else {
+		fmt.Println(colorize(summary+" (dry run, use --apply to save)", ansiYellow))
+	}
+	fmt.Println()
+}
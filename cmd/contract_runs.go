@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/bhatti/api-mock-service/internal/contract"
+	"github.com/bhatti/api-mock-service/internal/repository"
+	"github.com/bhatti/api-mock-service/internal/types"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var runsLimit int
+var baseRunID string
+var headRunID string
+var latencyThreshold float64
+var failOnRegression bool
+var statsScenario string
+
+// contractRunsCmd groups commands for contract runs recorded with --record
+var contractRunsCmd = &cobra.Command{
+	Use:   "contract-runs",
+	Short: "Lists, compares and analyzes recorded producer contract runs",
+	Long: `Lists, compares and analyzes producer contract runs recorded with
+producer-contract --record (or record_results:true) under the data dir.`,
+}
+
+// contractRunsListCmd lists recorded runs
+var contractRunsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists most recent recorded contract runs",
+	Run: func(cmd *cobra.Command, args []string) {
+		runRepo := buildContractRunRepository()
+		runs, err := runRepo.List(group, runsLimit)
+		if err != nil {
+			log.Errorf("failed to list contract runs %s", err)
+			os.Exit(1)
+		}
+		summaries := make([]*types.ContractRunSummary, len(runs))
+		for i, run := range runs {
+			summaries[i] = run.Summary()
+		}
+		if outputJSON {
+			printJSON(summaries)
+			return
+		}
+		sep := "──────────────────────────────────────────────────────────────"
+		fmt.Printf("\n%s\n", colorize("CONTRACT RUNS", ansiBold))
+		fmt.Println(colorize(sep, ansiBold))
+		fmt.Printf("%-28s %-20s %-12s %-10s %-20s %6s %6s %6s\n",
+			"ID", "GROUP", "LABEL", "ENV", "STARTED", "PASS", "FAIL", "FLAKY")
+		for _, s := range summaries {
+			fmt.Printf("%-28s %-20s %-12s %-10s %-20s %6d %6d %6d\n", s.ID, truncate(s.Group, 20),
+				truncate(s.Label, 12), truncate(s.Environment, 10), s.StartedAt.Format("2006-01-02 15:04:05"),
+				s.Succeeded, s.Failed, s.Flaky)
+		}
+	},
+}
+
+// contractRunsCompareCmd compares two recorded runs
+var contractRunsCompareCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "Compares head run against base run for new failures, fixes and latency regressions",
+	Long: `Compares head run against base run and reports new failures, fixed scenarios,
+flaky scenarios and average latencies that increased above --latency-threshold percent.
+
+Exit code 2 is returned when head regressed and --fail-on-regression is set.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if baseRunID == "" || headRunID == "" {
+			return fmt.Errorf("--base and --head are required")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		runRepo := buildContractRunRepository()
+		base, err := runRepo.Load(baseRunID)
+		if err != nil {
+			log.Errorf("failed to load base run %s: %s", baseRunID, err)
+			os.Exit(1)
+		}
+		head, err := runRepo.Load(headRunID)
+		if err != nil {
+			log.Errorf("failed to load head run %s: %s", headRunID, err)
+			os.Exit(1)
+		}
+		comparison := contract.CompareContractRuns(base, head, latencyThreshold)
+		if outputJSON {
+			printJSON(comparison)
+		} else {
+			printRunComparison(comparison)
+		}
+		if failOnRegression && comparison.Regressed() {
+			os.Exit(2)
+		}
+	},
+}
+
+// contractRunsStatsCmd computes stats of a scenario over recorded runs
+var contractRunsStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Shows success rate, latency, flakiness and top failures of a scenario over recorded runs",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if statsScenario == "" {
+			return fmt.Errorf("--scenario is required")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		runRepo := buildContractRunRepository()
+		runs, err := runRepo.List(group, runsLimit)
+		if err != nil {
+			log.Errorf("failed to list contract runs %s", err)
+			os.Exit(1)
+		}
+		stats := contract.BuildContractStats(statsScenario, runs)
+		if outputJSON {
+			printJSON(stats)
+			return
+		}
+		sep := "──────────────────────────────────────────────────────────────"
+		fmt.Printf("\n%s\n", colorize("SCENARIO STATS "+stats.ScenarioName, ansiBold))
+		fmt.Println(colorize(sep, ansiBold))
+		fmt.Printf("Runs: %d  Executions: %d  Success rate: %.1f%%  Avg latency: %.1f ms  Flaky runs: %d\n",
+			stats.Runs, stats.TotalExecutions, stats.SuccessRate, stats.AverageLatency, stats.FlakyRuns)
+		for _, point := range stats.Trend {
+			status := colorize(point.Status, ansiGreen)
+			if point.Status == types.OutcomeFailed {
+				status = colorize(point.Status, ansiRed)
+			} else if point.Status == types.OutcomeFlaky {
+				status = colorize(point.Status, ansiYellow)
+			}
+			fmt.Printf("  %-28s %-12s %-20s %s %.1f ms\n", point.RunID, truncate(point.Label, 12),
+				point.StartedAt.Format("2006-01-02 15:04:05"), status, point.LatencyMillis)
+		}
+		if len(stats.Top5Failures) > 0 {
+			fmt.Printf("\n%s\n", colorize("Top failures:", ansiYellow))
+			for _, failure := range stats.Top5Failures {
+				fmt.Printf("  ✗ %s\n", truncate(failure, 120))
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(contractRunsCmd)
+	contractRunsCmd.AddCommand(contractRunsListCmd, contractRunsCompareCmd, contractRunsStatsCmd)
+
+	contractRunsCmd.PersistentFlags().StringVar(&dataDir, "dataDir", "", "data dir of recorded contract runs")
+	contractRunsCmd.PersistentFlags().BoolVar(&outputJSON, "json", false, "output as JSON instead of human-readable table")
+	contractRunsListCmd.Flags().StringVar(&group, "group", "", "group of recorded runs")
+	contractRunsListCmd.Flags().IntVar(&runsLimit, "limit", 20, "max runs to list (0 is unlimited)")
+	contractRunsCompareCmd.Flags().StringVar(&baseRunID, "base", "", "id of base run")
+	contractRunsCompareCmd.Flags().StringVar(&headRunID, "head", "", "id of head run to compare against base")
+	contractRunsCompareCmd.Flags().Float64Var(&latencyThreshold, "latency-threshold", contract.DefaultLatencyRegressionPercent,
+		"increase of average latency in percent reported as regression")
+	contractRunsCompareCmd.Flags().BoolVar(&failOnRegression, "fail-on-regression", false, "exit with code 2 on new failures or latency regressions")
+	contractRunsStatsCmd.Flags().StringVar(&statsScenario, "scenario", "", "name of scenario")
+	contractRunsStatsCmd.Flags().StringVar(&group, "group", "", "group of recorded runs")
+	contractRunsStatsCmd.Flags().IntVar(&runsLimit, "limit", 100, "max recent runs to analyze (0 is unlimited)")
+}
+
+// buildContractRunRepository opens contract runs under data dir
+func buildContractRunRepository() repository.ContractRunRepository {
+	serverConfig, err := types.NewConfiguration(httpPort, proxyPort, dataDir, types.NewVersion(Version, Commit, Date))
+	if err != nil {
+		log.Errorf("failed to parse config %s", err)
+		os.Exit(1)
+	}
+	runRepo, err := repository.NewFileContractRunRepository(serverConfig)
+	if err != nil {
+		log.Errorf("failed to setup contract run repository %s", err)
+		os.Exit(1)
+	}
+	return runRepo
+}
+
+// printRunComparison prints a human-friendly comparison of contract runs.
+func printRunComparison(c *types.ContractRunComparison) {
+	sep := "──────────────────────────────────────────────────────────────"
+	fmt.Printf("\n%s\n", colorize("CONTRACT RUN COMPARISON", ansiBold))
+	fmt.Println(colorize(sep, ansiBold))
+	fmt.Printf("Base: %s %s  Head: %s %s\n", c.Base.ID, c.Base.Label, c.Head.ID, c.Head.Label)
+	for _, section := range []struct {
+		title  string
+		color  string
+		prefix string
+		names  []string
+	}{
+		{"New failures:", ansiRed, "✗", c.NewFailures},
+		{"Fixed:", ansiGreen, "✓", c.Fixed},
+		{"Still failing:", ansiRed, "✗", c.StillFailing},
+		{"Flaky:", ansiYellow, "~", c.Flaky},
+		{"Added:", ansiCyan, "+", c.Added},
+		{"Removed:", ansiCyan, "-", c.Removed},
+	} {
+		if len(section.names) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s\n", colorize(section.title, section.color))
+		for _, name := range section.names {
+			fmt.Printf("  %s %s\n", section.prefix, name)
+		}
+	}
+	if len(c.LatencyRegressions) > 0 {
+		fmt.Printf("\n%s\n", colorize("Latency regressions:", ansiRed))
+		for _, r := range c.LatencyRegressions {
+			fmt.Printf("  ✗ %-40s %.1f → %.1f ms (+%.1f%%)\n", truncate(r.Scenario, 40), r.BaseMillis, r.HeadMillis, r.Increase)
+		}
+	}
+	fmt.Println(colorize(sep, ansiBold))
+	if c.Regressed() {
+		fmt.Println(colorize(fmt.Sprintf("REGRESSED: %d new failures, %d latency regressions",
+			len(c.NewFailures), len(c.LatencyRegressions)), ansiRed))
+	} else {
+		fmt.Println(colorize(fmt.Sprintf("OK: %d fixed, %d flaky", len(c.Fixed), len(c.Flaky)), ansiGreen))
+	}
+}
+
+// defaultRunLabel returns git sha of CI environment or of the working directory
+func defaultRunLabel() string {
+	for _, name := range []string{"GITHUB_SHA", "CI_COMMIT_SHA", "GIT_COMMIT"} {
+		if sha := os.Getenv(name); sha != "" {
+			return sha
+		}
+	}
+	if out, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output(); err == nil {
+		return strings.TrimSpace(string(out))
+	}
+	return ""
+}
+
+func printJSON(v any) {
+	b, _ := json.MarshalIndent(v, "", "  ")
+	fmt.Println(string(b))
+}
@@ -2,14 +2,22 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"gopkg.in/yaml.v3"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/bhatti/api-mock-service/internal/contract"
 	"github.com/bhatti/api-mock-service/internal/fuzz"
 	"github.com/bhatti/api-mock-service/internal/oapi"
+	"github.com/bhatti/api-mock-service/internal/report"
+	"github.com/bhatti/api-mock-service/internal/repository"
 	"github.com/bhatti/api-mock-service/internal/shrink"
 	"github.com/bhatti/api-mock-service/internal/types"
 	"github.com/bhatti/api-mock-service/internal/web"
@@ -27,6 +35,33 @@ var trackCoverage bool
 var runMutations bool
 var dryRun bool
 var runShrink bool
+var saveRegression bool
+var hostOverrides map[string]string
+var concurrency int
+var maxRPS float64
+var loadDuration time.Duration
+var loadRampUp time.Duration
+var loadRPS float64
+var loadUsers int
+var loadWeights map[string]int
+var loadSLO map[string]string
+var loadReportFile string
+var reportFormat string
+var reportFile string
+var recordRun bool
+var runLabel string
+var runEnvironment string
+var retryAttempts int
+var retryBackoff time.Duration
+var retryOnStatus []int
+var maxFlakyRate float64
+var workflowFile string
+var runModel bool
+var modelSequences int
+var modelSteps int
+var modelSeed int64
+var modelInvariants []string
+var minMutationScore float64
 
 // producerContractCmd represents the contract command
 var producerContractCmd = &cobra.Command{
@@ -34,9 +69,12 @@ var producerContractCmd = &cobra.Command{
 	Short: "Executes producer contracts",
 	Long:  "Executes producer contracts",
 	PreRunE: func(cmd *cobra.Command, args []string) error {
-		// If scenario file is not provided, group is required
-		if scenarioFile == "" && group == "" {
-			return fmt.Errorf("either group or scenario file must be specified")
+		// If scenario or workflow file is not provided, group is required
+		if scenarioFile == "" && workflowFile == "" && group == "" {
+			return fmt.Errorf("either group, scenario file or workflow file must be specified")
+		}
+		if runModel && (group == "" || scenarioFile != "" || workflowFile != "") {
+			return fmt.Errorf("--model requires --group without scenario or workflow file")
 		}
 		return nil
 	},
@@ -47,11 +85,16 @@ var producerContractCmd = &cobra.Command{
 			"ExecTimes":    executionTimes,
 			"Verbose":      verbose,
 			"ScenarioFile": scenarioFile,
+			"WorkflowFile": workflowFile,
 			"SpecFile":     specFile,
 			"Mutations":    runMutations,
 			"Coverage":     trackCoverage,
 			"DryRun":       dryRun,
 			"Shrink":       runShrink,
+			"Concurrency":  concurrency,
+			"MaxRPS":       maxRPS,
+			"LoadDuration": loadDuration,
+			"Model":        runModel,
 		}).Debugf("executing producer contracts...")
 
 		serverConfig, err := types.NewConfiguration(
@@ -75,12 +118,50 @@ var producerContractCmd = &cobra.Command{
 		contractReq.Verbose = verbose
 		contractReq.TrackCoverage = trackCoverage
 		contractReq.DryRun = dryRun
+		contractReq.HostOverrides = hostOverrides
+		contractReq.Concurrency = concurrency
+		contractReq.MaxRPS = maxRPS
+		contractReq.RecordResults = recordRun
+		contractReq.RunLabel = runLabel
+		contractReq.Environment = runEnvironment
+		if retryAttempts > 1 {
+			contractReq.Retry = &types.RetryPolicy{
+				MaxAttempts:   retryAttempts,
+				BackoffMillis: int(retryBackoff.Milliseconds()),
+				RetryOnStatus: retryOnStatus,
+			}
+		}
+		if recordRun && runLabel == "" {
+			contractReq.RunLabel = defaultRunLabel()
+		}
+		if runModel {
+			contractReq.Model = &types.ModelConfig{
+				Sequences:  modelSequences,
+				MaxSteps:   modelSteps,
+				Seed:       modelSeed,
+				Invariants: modelInvariants,
+			}
+		}
+		if loadDuration > 0 {
+			if contractReq.Load, err = buildLoadConfig(); err != nil {
+				log.Errorf("failed to parse load options %s", err)
+				os.Exit(8)
+			}
+		}
 
 		executor := contract.NewProducerExecutor(
 			scenarioRepo,
 			groupConfigRepo,
 			web.NewHTTPClient(serverConfig, web.NewAuthAdapter(serverConfig)),
 		)
+		if recordRun {
+			runRepo, err := repository.NewFileContractRunRepository(serverConfig)
+			if err != nil {
+				log.Errorf("failed to setup contract run repository %s", err)
+				os.Exit(2)
+			}
+			executor = executor.WithRunRepository(runRepo)
+		}
 
 		// If an OpenAPI spec is provided, wire it up for response schema validation.
 		if specFile != "" {
@@ -112,6 +193,13 @@ var producerContractCmd = &cobra.Command{
 				os.Exit(7)
 			}
 			contractRes = executor.ExecuteMutationsByGroup(context.Background(), &http.Request{}, group, dataTemplate, contractReq)
+		} else if workflowFile != "" {
+			workflow, err := loadWorkflow(workflowFile)
+			if err != nil {
+				log.Errorf("failed to load workflow file: %s", err)
+				os.Exit(3)
+			}
+			contractRes = executor.ExecuteWorkflow(context.Background(), &http.Request{}, workflow, dataTemplate, contractReq)
 		} else if scenarioFile != "" {
 			// Load scenario file and create key data
 			keyData, err := loadScenarioKeyData(scenarioFile)
@@ -127,9 +215,44 @@ var producerContractCmd = &cobra.Command{
 			contractRes = executor.ExecuteByGroup(context.Background(), &http.Request{}, group, dataTemplate, contractReq)
 		}
 
-		printContractResultsTable(contractRes)
-		if contractRes.Coverage != nil {
-			printCoverageReport(contractRes.Coverage)
+		// report printed to stdout replaces the human-friendly tables
+		printTables := reportFormat == "" || reportFile != ""
+		if reportFormat != "" {
+			if err = writeContractReport(contractRes); err != nil {
+				log.Errorf("failed to write %s report %s", reportFormat, err)
+				os.Exit(11)
+			}
+		}
+		if contractRes.Load != nil {
+			if printTables {
+				printLoadReport(contractRes.Load)
+			}
+			if err = saveLoadReport(contractRes.Load, loadReportFile); err != nil {
+				log.Errorf("failed to save load report %s", err)
+				os.Exit(9)
+			}
+			if contractRes.RunID != "" && printTables {
+				fmt.Printf("\nRecorded run %s\n", contractRes.RunID)
+			}
+			if !contractRes.Load.Passed {
+				os.Exit(10)
+			}
+			return
+		}
+		if printTables {
+			if len(contractRes.Workflow) > 0 {
+				printWorkflowSteps(contractRes.Workflow)
+			}
+			printContractResultsTable(contractRes)
+			if contractRes.Coverage != nil {
+				printCoverageReport(contractRes.Coverage)
+			}
+			if contractRes.Model != nil {
+				printModelReport(contractRes.Model)
+			}
+			if contractRes.Mutations != nil {
+				printMutationReport(contractRes.Mutations)
+			}
 		}
 
 		// If shrinking is requested, find the minimal failing payload for each failure.
@@ -148,27 +271,67 @@ var producerContractCmd = &cobra.Command{
 				if lookupErr != nil {
 					continue
 				}
+				failure := contractRes.Errors[sk.Name+"_0"]
+				if failure == "" {
+					failure = contractRes.Errors[sk.Name]
+				}
 				fmt.Printf("Shrinking %s ...\n", sk.Name)
 				shrinkResult, shrinkErr := shrink.Shrink(context.Background(), detector, scenario, shrink.ShrinkOptions{})
 				if shrinkErr != nil {
 					fmt.Printf("  error: %s\n", shrinkErr)
 					continue
 				}
+				if !shrinkResult.Reproduced {
+					fmt.Printf("  %s failure did not reproduce, nothing to shrink\n", colorize("~", ansiYellow))
+					continue
+				}
 				if shrinkResult.Reduced {
 					fmt.Printf("  %s reduced in %d attempts\n", colorize("✓", ansiGreen), shrinkResult.Attempts)
 					fmt.Printf("  Minimal body: %s\n", shrinkResult.Minimal.Request.Contents)
+					if len(shrinkResult.Minimal.Request.QueryParams) > 0 {
+						fmt.Printf("  Minimal query: %v\n", shrinkResult.Minimal.Request.QueryParams)
+					}
+					if len(shrinkResult.Minimal.Request.Headers) > 0 {
+						fmt.Printf("  Minimal headers: %v\n", shrinkResult.Minimal.Request.Headers)
+					}
 				} else {
 					fmt.Printf("  %s no reduction possible (%d attempts)\n", colorize("~", ansiYellow), shrinkResult.Attempts)
 				}
+				if saveRegression {
+					regression := shrink.RegressionScenario(shrinkResult.Minimal, failure)
+					if saveErr := scenarioRepo.Save(regression); saveErr != nil {
+						fmt.Printf("  failed to save regression scenario: %s\n", saveErr)
+					} else {
+						fmt.Printf("  Saved regression scenario %s\n", regression.Name)
+					}
+				}
 			}
 		}
 
+		if contractRes.RunID != "" && printTables {
+			fmt.Printf("\nRecorded run %s\n", contractRes.RunID)
+		}
+
 		log.WithFields(log.Fields{
 			"Errors":     len(contractRes.Errors),
 			"Succeeded":  contractRes.Succeeded,
 			"Failed":     contractRes.Failed,
+			"Flaky":      contractRes.Flaky,
 			"Mismatched": contractRes.Mismatched,
 		}).Infof("completed all executions")
+
+		if cmd.Flags().Changed("max-flaky-rate") && contractRes.FlakyRate() > maxFlakyRate {
+			log.Errorf("flaky rate %.3f is above max flaky rate %.3f", contractRes.FlakyRate(), maxFlakyRate)
+			os.Exit(12)
+		}
+		if contractRes.Model != nil && !contractRes.Model.Passed() {
+			os.Exit(13)
+		}
+		if contractRes.Mutations != nil && cmd.Flags().Changed("min-mutation-score") &&
+			contractRes.Mutations.Score < minMutationScore {
+			log.Errorf("mutation score %.1f is below min mutation score %.1f", contractRes.Mutations.Score, minMutationScore)
+			os.Exit(14)
+		}
 	},
 }
 
@@ -182,11 +345,38 @@ func init() {
 	producerContractCmd.Flags().IntVar(&executionTimes, "times", 10, "execution times")
 	producerContractCmd.Flags().BoolVar(&verbose, "verbose", false, "verbose logging")
 	producerContractCmd.Flags().StringVar(&scenarioFile, "scenario", "", "path to scenario file (YAML)")
+	producerContractCmd.Flags().StringVar(&workflowFile, "workflow", "", "path to workflow file (YAML) with steps of the group executed as a dependency graph")
 	producerContractCmd.Flags().StringVar(&specFile, "spec", "", "path to OpenAPI spec file (YAML/JSON) for response schema validation")
 	producerContractCmd.Flags().BoolVar(&trackCoverage, "track-coverage", false, "include OpenAPI coverage report in output (requires --spec)")
 	producerContractCmd.Flags().BoolVar(&runMutations, "mutations", false, "run mutation testing instead of normal contract execution")
+	producerContractCmd.Flags().Float64Var(&minMutationScore, "min-mutation-score", 0, "exit with code 14 when percent of killed mutations is below the score")
 	producerContractCmd.Flags().BoolVar(&dryRun, "dry-run", false, "list scenarios that would run without executing them")
+	producerContractCmd.Flags().StringToStringVar(&hostOverrides, "host-override", nil, "host to dial instead, e.g. api.example.com=127.0.0.1:8443 (repeatable)")
+	producerContractCmd.Flags().IntVar(&concurrency, "concurrency", 1, "workers executing independent scenarios of group")
+	producerContractCmd.Flags().Float64Var(&maxRPS, "max-rps", 0, "max requests per second sent to the producer (0 is unlimited)")
+	producerContractCmd.Flags().DurationVar(&loadDuration, "load-duration", 0, "run group as load test for the duration, e.g. 1m")
+	producerContractCmd.Flags().Float64Var(&loadRPS, "load-rps", 0, "target requests per second of load test (0 is unlimited)")
+	producerContractCmd.Flags().IntVar(&loadUsers, "load-vus", 10, "virtual users of load test")
+	producerContractCmd.Flags().DurationVar(&loadRampUp, "load-ramp-up", 0, "ramp-up of virtual users and rps of load test")
+	producerContractCmd.Flags().StringToIntVar(&loadWeights, "load-weight", nil, "weight of scenario in load test, e.g. get_todo=3 (repeatable)")
+	producerContractCmd.Flags().StringToStringVar(&loadSLO, "slo", nil, "SLO threshold of load test, e.g. p99_millis=300,max_error_rate=0.01")
+	producerContractCmd.Flags().StringVar(&loadReportFile, "load-report", "", "file to save load report as JSON or Prometheus text if it ends with .prom")
+	producerContractCmd.Flags().StringVar(&reportFormat, "report", "", "report format of results: junit, html, markdown or sarif")
+	producerContractCmd.Flags().StringVar(&reportFile, "report-file", "", "file to save report, printed to stdout if not set")
+	producerContractCmd.Flags().BoolVar(&recordRun, "record", false, "record outcome of the run under data dir for contract-runs")
+	producerContractCmd.Flags().StringVar(&runLabel, "run-label", "", "label of recorded run, defaults to git sha")
+	producerContractCmd.Flags().StringVar(&runEnvironment, "env", "", "environment of recorded run such as staging")
+	producerContractCmd.Flags().IntVar(&retryAttempts, "retries", 1, "max attempts of an execution that failed with a retryable status or transport error")
+	producerContractCmd.Flags().DurationVar(&retryBackoff, "retry-backoff", 200*time.Millisecond, "backoff before first retry, doubled for each later retry")
+	producerContractCmd.Flags().IntSliceVar(&retryOnStatus, "retry-on-status", nil, "status codes that are retried (default 502,503,504)")
+	producerContractCmd.Flags().Float64Var(&maxFlakyRate, "max-flaky-rate", 0, "exit with code 12 when ratio of flaky executions is above the rate")
+	producerContractCmd.Flags().BoolVar(&runModel, "model", false, "generate random call sequences of group from scenario state machines")
+	producerContractCmd.Flags().IntVar(&modelSequences, "model-sequences", 20, "call sequences generated in model mode")
+	producerContractCmd.Flags().IntVar(&modelSteps, "model-steps", 10, "max calls of a sequence in model mode")
+	producerContractCmd.Flags().Int64Var(&modelSeed, "model-seed", 0, "seed of generated sequences to replay a model run (0 is random)")
+	producerContractCmd.Flags().StringArrayVar(&modelInvariants, "invariant", nil, "assertion template checked after each call in model mode (repeatable)")
 	producerContractCmd.Flags().BoolVar(&runShrink, "shrink", false, "shrink failing mutation payloads to minimal reproducing inputs (requires failures)")
+	producerContractCmd.Flags().BoolVar(&saveRegression, "save-regression", false, "save minimal failing scenarios found by --shrink to the group, tagged regression")
 }
 
 // isTTY returns true when stdout is a terminal (ANSI colors are safe to use).
@@ -226,10 +416,17 @@ func printContractResultsTable(res *types.ProducerContractResponse) {
 
 	// Print successes
 	for name := range res.Results {
-		if _, failed := res.Errors[name]; !failed {
+		_, failed := res.Errors[name]
+		_, flaky := res.FlakyErrors[name]
+		if !failed && !flaky {
 			fmt.Printf("%-40s %s\n", truncate(name, 40), colorize("✓ PASS", ansiGreen))
 		}
 	}
+	// Print executions that passed on retry
+	for name, errMsg := range res.FlakyErrors {
+		fmt.Printf("%-40s %s\n", truncate(name, 40), colorize(fmt.Sprintf("~ FLAKY (%d attempts)", res.Attempts[name]), ansiYellow))
+		fmt.Printf("  %s\n", colorize(truncate(errMsg, 100), ansiYellow))
+	}
 	// Print failures with detail
 	for name, errMsg := range res.Errors {
 		fmt.Printf("%-40s %s\n", truncate(name, 40), colorize("✗ FAIL", ansiRed))
@@ -254,9 +451,9 @@ func printContractResultsTable(res *types.ProducerContractResponse) {
 	}
 
 	fmt.Println(colorize(sep, ansiBold))
-	total := res.Succeeded + res.Failed + res.Mismatched
-	summary := fmt.Sprintf("TOTAL %d  Passed: %d  Failed: %d  Mismatched: %d",
-		total, res.Succeeded, res.Failed, res.Mismatched)
+	total := res.Succeeded + res.Failed + res.Flaky + res.Mismatched
+	summary := fmt.Sprintf("TOTAL %d  Passed: %d  Failed: %d  Flaky: %d  Mismatched: %d",
+		total, res.Succeeded, res.Failed, res.Flaky, res.Mismatched)
 	if res.Failed > 0 {
 		summary = colorize(summary, ansiRed)
 	} else {
@@ -273,6 +470,104 @@ func printContractResultsTable(res *types.ProducerContractResponse) {
 	}
 }
 
+// printWorkflowSteps prints status of each workflow step in execution phases.
+func printWorkflowSteps(steps []*types.WorkflowStepResult) {
+	sep := "──────────────────────────────────────────────────────────────"
+	fmt.Printf("\n%s\n", colorize("WORKFLOW", ansiBold))
+	fmt.Println(colorize(sep, ansiBold))
+	fmt.Printf("%-30s %-10s %-10s %s\n", colorize("STEP", ansiBold), colorize("PHASE", ansiBold),
+		colorize("STATUS", ansiBold), colorize("LATENCY", ansiBold))
+	for _, step := range steps {
+		status := colorize("✓ PASS", ansiGreen)
+		switch step.Status {
+		case types.OutcomeFailed:
+			status = colorize("✗ FAIL", ansiRed)
+		case types.OutcomeFlaky:
+			status = colorize("~ FLAKY", ansiYellow)
+		case types.OutcomeSkipped:
+			status = colorize("- SKIP", ansiCyan)
+		}
+		fmt.Printf("%-30s %-10s %-10s %d ms\n", truncate(step.Name, 30), step.Phase, status, step.LatencyMillis)
+		if step.Error != "" {
+			fmt.Printf("  %s\n", truncate(step.Error, 100))
+		}
+	}
+}
+
+// loadWorkflow loads workflow from YAML or JSON file; --group overrides group of the workflow.
+func loadWorkflow(path string) (*types.Workflow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	workflow := &types.Workflow{}
+	if err = yaml.Unmarshal(data, workflow); err != nil {
+		return nil, err
+	}
+	if group != "" {
+		workflow.Group = group
+	}
+	if workflow.Group == "" {
+		return nil, fmt.Errorf("group of workflow %s is not specified", path)
+	}
+	return workflow, workflow.Validate()
+}
+
+// printModelReport prints states visited by model run and minimal failing sequence.
+func printModelReport(r *types.ModelReport) {
+	sep := "──────────────────────────────────────────────────────────────"
+	fmt.Printf("\n%s\n", colorize("MODEL", ansiBold))
+	fmt.Println(colorize(sep, ansiBold))
+	fmt.Printf("Seed: %d  Sequences: %d  Steps: %d\n", r.Seed, r.Sequences, r.Steps)
+	fmt.Printf("States visited: %v\n", r.StatesVisited)
+	if len(r.UnvisitedStates) > 0 {
+		fmt.Printf("%s %v\n", colorize("States not visited:", ansiYellow), r.UnvisitedStates)
+	}
+	if r.Failure == nil {
+		fmt.Println(colorize("OK: all sequences passed", ansiGreen))
+		return
+	}
+	fmt.Printf("%s %s\n", colorize("Failing sequence:", ansiRed), strings.Join(r.Failure.Sequence, " → "))
+	fmt.Printf("%s %s (%d shrink attempts)\n", colorize("Minimal sequence:", ansiRed),
+		strings.Join(r.Failure.Minimal, " → "), r.Failure.ShrinkAttempts)
+	fmt.Printf("  %s\n", colorize(truncate(r.Failure.Error, 120), ansiRed))
+	fmt.Printf("Replay with --model-seed %d\n", r.Seed)
+}
+
+// printMutationReport prints mutation score of each endpoint with the mutations that survived or crashed.
+func printMutationReport(r *types.MutationReport) {
+	sep := "──────────────────────────────────────────────────────────────"
+	fmt.Printf("\n%s\n", colorize("MUTATION SCORE", ansiBold))
+	fmt.Println(colorize(sep, ansiBold))
+	fmt.Printf("%-40s %6s %6s %8s %7s %7s\n", "ENDPOINT", "TOTAL", "KILLED", "SURVIVED", "CRASHED", "SCORE")
+	for _, e := range r.Endpoints {
+		fmt.Printf("%-40s %6d %6d %8d %7d %6.1f%%\n", truncate(e.Endpoint, 40), e.Total, e.Killed, e.Survived, e.Crashed, e.Score)
+	}
+	for _, section := range []struct {
+		title   string
+		results []*types.MutationResult
+	}{
+		{"Survived:", r.Survivors},
+		{"Crashed:", r.Crashes},
+	} {
+		if len(section.results) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s\n", colorize(section.title, ansiRed))
+		for _, res := range section.results {
+			fmt.Printf("  ✗ %s %s %s (expected %s, got %d)\n", res.Endpoint, res.Kind, res.Field, res.Expected, res.Status)
+			fmt.Printf("    %s\n", truncate(res.Payload, 200))
+		}
+	}
+	fmt.Println(colorize(sep, ansiBold))
+	summary := fmt.Sprintf("SCORE %.1f%%  Killed: %d  Survived: %d  Crashed: %d", r.Score, r.Killed, r.Survived, r.Crashed)
+	if r.Survived > 0 || r.Crashed > 0 {
+		fmt.Println(colorize(summary, ansiRed))
+	} else {
+		fmt.Println(colorize(summary, ansiGreen))
+	}
+}
+
 // printCoverageReport prints the coverage summary.
 func printCoverageReport(c *types.CoverageSummary) {
 	sep := "──────────────────────────────────────────────────────────────"
@@ -306,6 +601,114 @@ func printCoverageReport(c *types.CoverageSummary) {
 	}
 }
 
+// buildLoadConfig builds load config from load flags
+func buildLoadConfig() (*types.LoadConfig, error) {
+	durationSecs, err := wholeSeconds("load-duration", loadDuration)
+	if err != nil {
+		return nil, err
+	}
+	rampUpSecs, err := wholeSeconds("load-ramp-up", loadRampUp)
+	if err != nil {
+		return nil, err
+	}
+	load := &types.LoadConfig{
+		DurationSecs: durationSecs,
+		TargetRPS:    loadRPS,
+		VirtualUsers: loadUsers,
+		RampUpSecs:   rampUpSecs,
+		Weights:      loadWeights,
+	}
+	for name, val := range loadSLO {
+		threshold, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slo %s=%s", name, val)
+		}
+		switch name {
+		case "p50_millis":
+			load.Thresholds.P50Millis = threshold
+		case "p90_millis":
+			load.Thresholds.P90Millis = threshold
+		case "p99_millis":
+			load.Thresholds.P99Millis = threshold
+		case "max_error_rate":
+			load.Thresholds.MaxErrorRate = threshold
+		case "min_throughput":
+			load.Thresholds.MinThroughput = threshold
+		default:
+			return nil, fmt.Errorf("unsupported slo %s", name)
+		}
+	}
+	return load, load.Validate()
+}
+
+// wholeSeconds converts duration flag to seconds of load config, which doesn't support fractions of a second
+func wholeSeconds(flag string, d time.Duration) (int, error) {
+	if d%time.Second != 0 {
+		return 0, fmt.Errorf("--%s %s must be a whole number of seconds such as 1s or 2m", flag, d)
+	}
+	return int(d / time.Second), nil
+}
+
+// printLoadReport prints latency, throughput, errors and SLO results of load test.
+func printLoadReport(r *types.LoadReport) {
+	sep := "──────────────────────────────────────────────────────────────"
+	fmt.Printf("\n%s\n", colorize("LOAD REPORT", ansiBold))
+	fmt.Println(colorize(sep, ansiBold))
+	fmt.Printf("%-40s %8s %8s %8s %8s %8s\n", colorize("SCENARIO", ansiBold), "REQS", "ERR%", "P50", "P90", "P99")
+	names := make([]string, 0, len(r.Scenarios))
+	for name := range r.Scenarios {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := r.Scenarios[name]
+		fmt.Printf("%-40s %8d %7.1f%% %8.1f %8.1f %8.1f\n", truncate(name, 40), s.Requests,
+			s.ErrorRate*100, s.Latency.P50, s.Latency.P90, s.Latency.P99)
+	}
+	fmt.Println(colorize(sep, ansiBold))
+	fmt.Printf("Requests: %d  Throughput: %.1f rps  Errors: %.1f%% %v  p50/p90/p99: %.1f/%.1f/%.1f ms\n",
+		r.Requests, r.Throughput, r.ErrorRate*100, r.ErrorsByStatus, r.Latency.P50, r.Latency.P90, r.Latency.P99)
+	for _, slo := range r.SLO {
+		if slo.Passed {
+			fmt.Printf("  %s %s %.3f (threshold %.3f)\n", colorize("✓", ansiGreen), slo.Name, slo.Actual, slo.Threshold)
+		} else {
+			fmt.Printf("  %s %s %.3f (threshold %.3f)\n", colorize("✗", ansiRed), slo.Name, slo.Actual, slo.Threshold)
+		}
+	}
+}
+
+// writeContractReport renders results in report format and saves them to report file or prints them to stdout.
+func writeContractReport(res *types.ProducerContractResponse) error {
+	suite := group
+	if suite == "" {
+		suite = strings.TrimSuffix(filepath.Base(scenarioFile), filepath.Ext(scenarioFile))
+	}
+	b, _, err := report.Render(reportFormat, suite, res)
+	if err != nil {
+		return err
+	}
+	if reportFile == "" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	return os.WriteFile(reportFile, b, 0644)
+}
+
+// saveLoadReport saves load report as Prometheus text if file ends with .prom or else as JSON.
+func saveLoadReport(r *types.LoadReport, fileName string) error {
+	if fileName == "" {
+		return nil
+	}
+	if strings.HasSuffix(fileName, ".prom") {
+		return os.WriteFile(fileName, []byte(r.PrometheusText()), 0644)
+	}
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fileName, b, 0644)
+}
+
 func truncate(s string, n int) string {
 	if len(s) <= n {
 		return s
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"crypto/tls"
 	"embed"
 	"fmt"
 	"github.com/bhatti/api-mock-service/internal/contract"
@@ -85,9 +86,36 @@ func RunServer(_ *cobra.Command, args []string) {
 			Errorf("failed to setup repositories...")
 		os.Exit(2)
 	}
+	runRepo, err := repository.NewFileContractRunRepository(serverConfig)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).
+			Errorf("failed to setup contract run repository...")
+		os.Exit(2)
+	}
+	workflowRepo, err := repository.NewFileWorkflowRepository(serverConfig)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).
+			Errorf("failed to setup workflow repository...")
+		os.Exit(2)
+	}
 	webServer := web.NewDefaultWebServer(serverConfig)
+	if serverConfig.TLS.Enabled {
+		tlsConfig, err := web.BuildServerTLSConfig(&serverConfig.TLS, func(hosts []string) (*tls.Certificate, error) {
+			ca, err := proxy.LoadOrCreateCertificateAuthority(serverConfig)
+			if err != nil {
+				return nil, err
+			}
+			return ca.IssueServerCertificate(hosts)
+		})
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err}).
+				Errorf("failed to setup tls...")
+			os.Exit(4)
+		}
+		webServer.EnableTLS(tlsConfig)
+	}
 	httpClient := web.NewHTTPClient(serverConfig, web.NewAuthAdapter(serverConfig))
-	if err = buildControllers(serverConfig, scenarioRepo, fixturesRepo, oapiRepo, groupConfigRepo, httpClient, webServer); err != nil {
+	if err = buildControllers(serverConfig, scenarioRepo, fixturesRepo, oapiRepo, groupConfigRepo, runRepo, workflowRepo, httpClient, webServer); err != nil {
 		log.WithFields(log.Fields{"Error": err}).
 			Errorf("failed to setup controller...")
 		os.Exit(3)
@@ -96,7 +124,7 @@ func RunServer(_ *cobra.Command, args []string) {
 		fmt.Printf("⇨ http proxy started on \x1b[32m[::]:%d\033[0m\n", serverConfig.ProxyPort)
 		adapter := web.NewWebServerAdapter()
 		recorder := proxy.NewRecorder(serverConfig, httpClient, scenarioRepo, groupConfigRepo)
-		executor := contract.NewProducerExecutor(scenarioRepo, groupConfigRepo, httpClient)
+		executor := contract.NewProducerExecutor(scenarioRepo, groupConfigRepo, httpClient).WithRunRepository(runRepo)
 		_ = controller.NewOAPIController(serverConfig, InternalOAPI, scenarioRepo, oapiRepo, adapter)
 		_ = controller.NewGroupConfigController(groupConfigRepo, adapter)
 		_ = controller.NewAPIScenarioController(scenarioRepo, oapiRepo, adapter)
@@ -104,6 +132,7 @@ func RunServer(_ *cobra.Command, args []string) {
 		_ = controller.NewAPIFixtureController(fixturesRepo, adapter)
 		_ = controller.NewAPIProxyController(recorder, adapter)
 		_ = controller.NewProducerContractController(executor, adapter)
+		_ = controller.NewWorkflowController(workflowRepo, executor, adapter)
 		webServer.Embed(SwaggerContent, "/swagger-ui/*", "swagger-ui")
 		log.Fatal(proxy.NewProxyHandler(serverConfig,
 			web.NewAuthAdapter(serverConfig), scenarioRepo, fixturesRepo, groupConfigRepo, adapter).Start())
@@ -182,12 +211,16 @@ func buildControllers(
 	fixtureRepo repository.APIFixtureRepository,
 	oapiRepo repository.OAPIRepository,
 	groupConfigRepo repository.GroupConfigRepository,
+	runRepo repository.ContractRunRepository,
+	workflowRepo repository.WorkflowRepository,
 	httpClient web.HTTPClient,
 	webServer web.Server,
 ) (err error) {
 	recorder := proxy.NewRecorder(serverConfig, httpClient, scenarioRepo, groupConfigRepo)
 	player := contract.NewConsumerExecutor(serverConfig, scenarioRepo, fixtureRepo, groupConfigRepo)
-	executor := contract.NewProducerExecutor(scenarioRepo, groupConfigRepo, httpClient)
+	driftStore := contract.NewDriftStore()
+	player.EnableShadow(httpClient, driftStore)
+	executor := contract.NewProducerExecutor(scenarioRepo, groupConfigRepo, httpClient).WithRunRepository(runRepo)
 	_ = controller.NewOAPIController(serverConfig, InternalOAPI, scenarioRepo, oapiRepo, webServer)
 	_ = controller.NewGroupConfigController(groupConfigRepo, webServer)
 	_ = controller.NewAPIScenarioController(scenarioRepo, oapiRepo, webServer)
@@ -195,6 +228,8 @@ func buildControllers(
 	_ = controller.NewAPIFixtureController(fixtureRepo, webServer)
 	_ = controller.NewAPIProxyController(recorder, webServer)
 	_ = controller.NewProducerContractController(executor, webServer)
+	_ = controller.NewWorkflowController(workflowRepo, executor, webServer)
+	_ = controller.NewDriftController(driftStore, webServer)
 	_ = controller.NewRootController(player, webServer)
 	assetDir := filepath.Join(serverConfig.DataDir, "assets")
 	webServer.Static("/_assets", assetDir)
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bhatti/api-mock-service/internal/proxy"
+	"github.com/bhatti/api-mock-service/internal/types"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var caValidityDays int
+
+// rotateCACmd replaces the CA that signs MITM certificates of the proxy
+var rotateCACmd = &cobra.Command{
+	Use:   "rotate-ca",
+	Short: "Rotates the CA that signs certificates of the HTTPS proxy",
+	Long: `Generates a new certificate authority under <dataDir>/ca for the MITM proxy.
+Restart the service after rotation and re-import the certificate from /_proxy/ca.pem
+into browsers and clients that trust the proxy.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		serverConfig, err := types.NewConfiguration(httpPort, proxyPort, dataDir, types.NewVersion(Version, Commit, Date))
+		if err != nil {
+			log.Errorf("failed to create config: %s", err)
+			os.Exit(1)
+		}
+		if caValidityDays > 0 {
+			serverConfig.ProxyCA.ValidityDays = caValidityDays
+		}
+		ca, err := proxy.RotateCertificateAuthority(serverConfig)
+		if err != nil {
+			log.Errorf("failed to rotate proxy CA: %s", err)
+			os.Exit(2)
+		}
+		fmt.Printf("%s %s (expires %s)\n",
+			colorize("rotated proxy CA", ansiGreen),
+			filepath.Join(proxy.CADir(serverConfig), proxy.CACertFile),
+			ca.NotAfter().Format("2006-01-02"))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rotateCACmd)
+
+	rotateCACmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file")
+	rotateCACmd.Flags().StringVar(&dataDir, "dataDir", "", "data dir to store API contracts and CA")
+	rotateCACmd.Flags().IntVar(&caValidityDays, "validity-days", 0, "validity of the new CA in days (default proxy_ca.validity_days or 365)")
+}
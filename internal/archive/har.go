@@ -427,7 +427,7 @@ func toScenario(config *types.Configuration, entry HarEntry) (*types.APIScenario
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse %s due to %w", entry.Request.URL, err)
 	}
-	started, _ := time.Parse(entry.StartedDateTime, time.RFC3339)
+	started, _ := time.Parse(time.RFC3339, entry.StartedDateTime)
 	if started.IsZero() {
 		started = time.Now()
 	}
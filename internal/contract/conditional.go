@@ -0,0 +1,158 @@
+package contract
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/fuzz"
+	"github.com/bhatti/api-mock-service/internal/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// serverStarted is used as Last-Modified of scenarios that were not recorded
+var serverStarted = time.Now()
+
+// maxETagsPerGroup bounds resources whose ETag is kept for each group
+const maxETagsPerGroup = 10000
+
+// ETagStore keeps last ETag of each resource by group so that If-Match of writes can detect lost updates.
+// ETags of a group are dropped when its conditional config changes, and a nil store doesn't track ETags.
+type ETagStore struct {
+	groups map[string]*groupETags
+	lock   sync.Mutex
+}
+
+// groupETags keeps ETags of resources of a group along with conditional config they were generated with
+type groupETags struct {
+	conditional types.ConditionalConfig
+	etags       map[string]string
+}
+
+// NewETagStore constructor
+func NewETagStore() *ETagStore {
+	return &ETagStore{groups: make(map[string]*groupETags)}
+}
+
+// group returns ETags of group, resetting them if conditional config of the group has changed
+func (s *ETagStore) group(name string, conditional *types.ConditionalConfig) *groupETags {
+	group := s.groups[name]
+	if group == nil || group.conditional != *conditional {
+		group = &groupETags{conditional: *conditional, etags: make(map[string]string)}
+		s.groups[name] = group
+	}
+	return group
+}
+
+func (s *ETagStore) load(name string, conditional *types.ConditionalConfig, path string) string {
+	if s == nil {
+		return ""
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.group(name, conditional).etags[path]
+}
+
+func (s *ETagStore) store(name string, conditional *types.ConditionalConfig, path string, etag string) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	group := s.group(name, conditional)
+	if _, ok := group.etags[path]; !ok && len(group.etags) >= maxETagsPerGroup {
+		for evicted := range group.etags {
+			delete(group.etags, evicted)
+			break
+		}
+	}
+	group.etags[path] = etag
+}
+
+func (s *ETagStore) delete(name string, conditional *types.ConditionalConfig, path string) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.group(name, conditional).etags, path)
+}
+
+// applyConditional adds ETag and Last-Modified validators to the response and evaluates preconditions of the request.
+// It returns 304 or 412 if the response must be replaced, otherwise 0.
+func applyConditional(
+	req *http.Request,
+	reqHeaders http.Header,
+	respHeaders http.Header,
+	scenario *types.APIScenario,
+	conditional *types.ConditionalConfig,
+	respBody []byte,
+	stream *FixtureStream,
+	templateParams map[string]any,
+	etags *ETagStore) int {
+	etag := respHeaders.Get(types.ETagHeader)
+	if etag == "" {
+		if conditional.ETagTemplate != "" {
+			if b, err := fuzz.ParseTemplate("", []byte(conditional.ETagTemplate), templateParams); err == nil {
+				etag = conditional.FormatETag(string(b))
+			} else {
+				log.WithFields(log.Fields{
+					"Component": "ConsumerExecutor-Conditional",
+					"Scenario":  scenario.Name,
+					"Group":     scenario.Group,
+					"Error":     err,
+				}).Warnf("failed to render etag template")
+			}
+		}
+		if etag == "" && stream != nil {
+			etag = conditional.FormatETag(fmt.Sprintf("%x-%x", stream.Size, stream.ModTime.UnixNano()))
+		} else if etag == "" {
+			etag = conditional.BodyETag(respBody)
+		}
+		respHeaders.Set(types.ETagHeader, etag)
+	}
+
+	modified := serverStarted
+	if stream != nil {
+		modified = stream.ModTime
+	} else if !scenario.EndTime.IsZero() {
+		modified = scenario.EndTime
+	}
+	lastModified := conditional.LastModifiedTime(modified)
+	if t, err := http.ParseTime(respHeaders.Get(types.LastModifiedHeader)); err == nil {
+		lastModified = t
+	} else {
+		respHeaders.Set(types.LastModifiedHeader, lastModified.Format(http.TimeFormat))
+	}
+
+	switch scenario.Method {
+	case types.Get, types.Head:
+		etags.store(scenario.Group, conditional, req.URL.Path, etag)
+		if ifNoneMatch := reqHeaders.Get("If-None-Match"); ifNoneMatch != "" {
+			if types.ETagMatches(ifNoneMatch, etag, true) {
+				return http.StatusNotModified
+			}
+		} else if t, err := http.ParseTime(reqHeaders.Get("If-Modified-Since")); err == nil && !lastModified.After(t) {
+			return http.StatusNotModified
+		}
+	default:
+		current := etags.load(scenario.Group, conditional, req.URL.Path)
+		if ifMatch := reqHeaders.Get("If-Match"); ifMatch != "" && !types.ETagMatches(ifMatch, current, false) {
+			return http.StatusPreconditionFailed
+		}
+		if ifNoneMatch := reqHeaders.Get("If-None-Match"); ifNoneMatch != "" &&
+			types.ETagMatches(ifNoneMatch, current, true) {
+			return http.StatusPreconditionFailed
+		}
+		if t, err := http.ParseTime(reqHeaders.Get("If-Unmodified-Since")); err == nil && lastModified.After(t) {
+			return http.StatusPreconditionFailed
+		}
+		if scenario.Method == types.Delete {
+			etags.delete(scenario.Group, conditional, req.URL.Path)
+		} else {
+			etags.store(scenario.Group, conditional, req.URL.Path, etag)
+		}
+	}
+	return 0
+}
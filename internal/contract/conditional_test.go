@@ -0,0 +1,146 @@
+package contract
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/repository"
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldEmulateConditionalRequestsOfGroup(t *testing.T) {
+	// GIVEN scenarios of a group with conditional requests enabled
+	config := types.BuildTestConfig()
+	config.DataDir = t.TempDir()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	fixtureRepository, err := repository.NewFileFixtureRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	require.NoError(t, groupConfigRepository.Save("accounts", &types.GroupConfig{
+		Conditional: &types.ConditionalConfig{Enabled: true},
+	}))
+	recorded := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, scenario := range []*types.APIScenario{
+		{
+			Method:   types.Get,
+			Name:     "get-account",
+			Path:     "/accounts/:id",
+			Group:    "accounts",
+			EndTime:  recorded,
+			Response: types.APIResponse{StatusCode: 200, Contents: `{"id":1,"balance":100}`},
+		},
+		{
+			Method:   types.Put,
+			Name:     "put-account",
+			Path:     "/accounts/:id",
+			Group:    "accounts",
+			Response: types.APIResponse{StatusCode: 200, Contents: `{"id":1,"balance":200}`},
+		},
+	} {
+		scenario.Response.Headers = http.Header{types.ContentTypeHeader: {"application/json"}}
+		require.NoError(t, scenarioRepository.Save(scenario))
+	}
+	player := NewConsumerExecutor(config, scenarioRepository, fixtureRepository, groupConfigRepository)
+	execute := func(method string, headers map[string]string) *httptest.ResponseRecorder {
+		var body *strings.Reader
+		if method == http.MethodPut {
+			body = strings.NewReader(`{"balance":200}`)
+		} else {
+			body = strings.NewReader("")
+		}
+		req := httptest.NewRequest(method, "http://localhost/accounts/1", body)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		require.NoError(t, player.Execute(echo.New().NewContext(req, rec)))
+		return rec
+	}
+
+	// WHEN fetching the account
+	rec := execute(http.MethodGet, nil)
+	// THEN validators should be added
+	require.Equal(t, 200, rec.Code)
+	etag := rec.Header().Get(types.ETagHeader)
+	require.Len(t, etag, 18)
+	require.Equal(t, recorded.Format(http.TimeFormat), rec.Header().Get(types.LastModifiedHeader))
+
+	// WHEN revalidating with matching ETag
+	rec = execute(http.MethodGet, map[string]string{"If-None-Match": etag})
+	// THEN not-modified should be returned without body
+	require.Equal(t, 304, rec.Code)
+	require.Empty(t, rec.Body.Bytes())
+
+	// WHEN revalidating with modification time
+	rec = execute(http.MethodGet, map[string]string{"If-Modified-Since": recorded.Format(http.TimeFormat)})
+	require.Equal(t, 304, rec.Code)
+	rec = execute(http.MethodGet, map[string]string{"If-Modified-Since": recorded.Add(-time.Hour).Format(http.TimeFormat)})
+	require.Equal(t, 200, rec.Code)
+
+	// WHEN updating with stale ETag
+	rec = execute(http.MethodPut, map[string]string{"If-Match": `"stale"`})
+	// THEN precondition should fail
+	require.Equal(t, 412, rec.Code)
+
+	// WHEN updating with current ETag
+	rec = execute(http.MethodPut, map[string]string{"If-Match": etag})
+	// THEN update should succeed with new ETag
+	require.Equal(t, 200, rec.Code)
+	require.NotEqual(t, etag, rec.Header().Get(types.ETagHeader))
+
+	// WHEN another client updates with the ETag it fetched before the update
+	rec = execute(http.MethodPut, map[string]string{"If-Match": etag})
+	// THEN lost update should be rejected
+	require.Equal(t, 412, rec.Code)
+}
+
+func Test_ShouldRenderConditionalETagFromTemplate(t *testing.T) {
+	// GIVEN conditional config with etag template
+	conditional := &types.ConditionalConfig{Enabled: true, ETagTemplate: "account-{{.id}}", WeakETag: true}
+	scenario := &types.APIScenario{Method: types.Get, Name: "get-account", Path: "/accounts/:id", Group: "template"}
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/accounts/7", nil)
+	respHeaders := http.Header{}
+	// WHEN applying conditional
+	status := applyConditional(req, http.Header{"If-None-Match": {`"account-7"`}}, respHeaders, scenario,
+		conditional, []byte("body"), nil, map[string]any{"id": "7"}, NewETagStore())
+	// THEN weak etag should be rendered and matched with weak comparison
+	require.Equal(t, `W/"account-7"`, respHeaders.Get(types.ETagHeader))
+	require.Equal(t, http.StatusNotModified, status)
+}
+
+func Test_ShouldResetETagsWhenConditionalConfigChanges(t *testing.T) {
+	// GIVEN etags of resources of two groups
+	etags := NewETagStore()
+	conditional := &types.ConditionalConfig{Enabled: true}
+	etags.store("accounts", conditional, "/accounts/1", `"v1"`)
+	etags.store("orders", conditional, "/accounts/1", `"o1"`)
+	// THEN etags should be kept by group
+	require.Equal(t, `"v1"`, etags.load("accounts", conditional, "/accounts/1"))
+	require.Equal(t, `"o1"`, etags.load("orders", conditional, "/accounts/1"))
+
+	// WHEN conditional config of a group changes
+	changed := &types.ConditionalConfig{Enabled: true, WeakETag: true}
+	// THEN its etags should be dropped without affecting other groups
+	require.Equal(t, "", etags.load("accounts", changed, "/accounts/1"))
+	require.Equal(t, `"o1"`, etags.load("orders", conditional, "/accounts/1"))
+
+	// WHEN storing more resources than the limit
+	for i := 0; i <= maxETagsPerGroup; i++ {
+		etags.store("orders", conditional, fmt.Sprintf("/orders/%d", i), `"o"`)
+	}
+	// THEN etags of the group should be bounded
+	require.Equal(t, maxETagsPerGroup, len(etags.groups["orders"].etags))
+
+	// AND nil store should not track etags
+	var none *ETagStore
+	none.store("accounts", conditional, "/accounts/1", `"v1"`)
+	require.Equal(t, "", none.load("accounts", conditional, "/accounts/1"))
+}
@@ -1,6 +1,7 @@
 package contract
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/bhatti/api-mock-service/internal/fuzz"
 	"github.com/bhatti/api-mock-service/internal/state"
@@ -26,6 +27,9 @@ type ConsumerExecutor struct {
 	fixtureRepository     repository.APIFixtureRepository
 	groupConfigRepository repository.GroupConfigRepository
 	stateStore            state.StateStore
+	upstreamClient        web.HTTPClient
+	driftStore            *DriftStore
+	etags                 *ETagStore
 }
 
 // NewConsumerExecutor instantiates controller for updating api-scenarios
@@ -41,11 +45,77 @@ func NewConsumerExecutor(
 		fixtureRepository:     fixtureRepository,
 		groupConfigRepository: groupConfigRepository,
 		stateStore:            state.NewInMemoryStateStore(),
+		etags:                 NewETagStore(),
 	}
 }
 
+// EnableShadow allows shadow mode so that requests are also sent to upstream and compared against mock
+func (cx *ConsumerExecutor) EnableShadow(upstreamClient web.HTTPClient, driftStore *DriftStore) {
+	cx.upstreamClient = upstreamClient
+	cx.driftStore = driftStore
+}
+
 // Execute request and replays stubbed response
 func (cx *ConsumerExecutor) Execute(c web.APIContext) (err error) {
+	if err = cx.rewriteRequest(c.Request()); err != nil {
+		return web.HandleError(c, err)
+	}
+	if cx.shadowEnabled(c.Request()) {
+		return cx.executeShadow(c)
+	}
+	overrides := buildOverrides(c)
+	key, err := web.BuildMockScenarioKeyData(c.Request())
+	if err != nil {
+		return web.HandleError(c, err)
+	}
+	matchedScenario, respBody, stream, _, err := cx.executeWithKey(c.Request(), c.Response().Header(), key, overrides, true)
+	if err != nil {
+		return web.HandleError(c, err)
+	}
+	latency := ScenarioLatency(cx.groupConfigRepository, matchedScenario)
+	if stream != nil {
+		return serveFixtureStream(c, matchedScenario, stream, latency)
+	}
+	if matchedScenario.Response.StatusCode == http.StatusNotModified {
+		c.Response().Header().Del(types.ContentLengthHeader)
+		return c.NoContent(http.StatusNotModified)
+	}
+	respBody = EncodeResponseBody(c.Request().Header, c.Response().Header(), matchedScenario.Response.StatusCode, respBody)
+	if latency != nil && latency.BytesPerSecond > 0 {
+		flush := func() {
+			if flusher, ok := c.Response().Writer.(http.Flusher); ok {
+				flusher.Flush()
+			}
+		}
+		return c.Stream(
+			matchedScenario.Response.StatusCode,
+			matchedScenario.Response.ContentType(""),
+			utils.NewThrottledReader(bytes.NewReader(respBody), latency.BytesPerSecond, flush))
+	}
+	return c.Blob(
+		matchedScenario.Response.StatusCode,
+		matchedScenario.Response.ContentType(""),
+		respBody)
+}
+
+// rewriteRequest applies request rewrite rules of the group before matching the scenario
+func (cx *ConsumerExecutor) rewriteRequest(req *http.Request) error {
+	groupConfig, err := cx.groupConfigRepository.Load(types.RequestGroup(req))
+	if err != nil || len(groupConfig.RewriteRules) == 0 {
+		return nil
+	}
+	var reqBody []byte
+	reqBody, req.Body, err = utils.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	reqBody = groupConfig.RewriteRequest(req, reqBody)
+	req.Body = utils.NopCloser(bytes.NewReader(reqBody))
+	return nil
+}
+
+// buildOverrides collects headers, query, form params and top-level body fields as template params
+func buildOverrides(c web.APIContext) map[string]any {
 	overrides := make(map[string]any)
 	for k, v := range c.Request().Header {
 		overrides[k] = v[0]
@@ -67,20 +137,7 @@ func (cx *ConsumerExecutor) Execute(c web.APIContext) (err error) {
 		bodyBytes, c.Request().Body, _ = utils.ReadAll(c.Request().Body)
 		types.InjectBodyFieldsAsTemplateParams(overrides, bodyBytes)
 	}
-
-	key, err := web.BuildMockScenarioKeyData(c.Request())
-	if err != nil {
-		return web.HandleError(c, err)
-	}
-	matchedScenario, respBody, _, err := cx.ExecuteWithKey(c.Request(), c.Response().Header(), key, overrides)
-	if err != nil {
-		return web.HandleError(c, err)
-	}
-	return c.Blob(
-		matchedScenario.Response.StatusCode,
-		matchedScenario.Response.ContentType(""),
-		respBody)
-	return err
+	return overrides
 }
 
 // ExecuteWithKey request and replays stubbed response
@@ -90,6 +147,18 @@ func (cx *ConsumerExecutor) ExecuteWithKey(
 	key *types.APIKeyData,
 	overrides map[string]any) (matchedScenario *types.APIScenario, respBytes []byte,
 	sharedVariables map[string]any, err error) {
+	matchedScenario, respBytes, _, sharedVariables, err = cx.executeWithKey(req, respHeaders, key, overrides, false)
+	return
+}
+
+// executeWithKey replays stubbed response, returning fixture as stream instead of bytes if streamFixture is set
+func (cx *ConsumerExecutor) executeWithKey(
+	req *http.Request,
+	respHeaders http.Header,
+	key *types.APIKeyData,
+	overrides map[string]any,
+	streamFixture bool) (matchedScenario *types.APIScenario, respBytes []byte, stream *FixtureStream,
+	sharedVariables map[string]any, err error) {
 	started := time.Now()
 
 	matchedScenario, err = cx.scenarioRepository.Lookup(key, overrides)
@@ -99,12 +168,12 @@ func (cx *ConsumerExecutor) ExecuteWithKey(
 	if matchedScenario.NextRequest != "" && len(matchedScenario.Response.AddSharedVariables) > 0 {
 		nextScenario, err := cx.scenarioRepository.LookupByName(matchedScenario.NextRequest, overrides)
 		if err != nil {
-			return nil, nil, nil,
+			return nil, nil, nil, nil,
 				fmt.Errorf("next request key: %s not found: %s", key.Name, err)
 		}
-		_, sharedVariables, err = cx.execute(req, respHeaders, nextScenario, started)
+		_, _, sharedVariables, err = cx.execute(req, respHeaders, nextScenario, started, false)
 		if err != nil {
-			return nil, nil, nil, err
+			return nil, nil, nil, nil, err
 		}
 		for k, v := range sharedVariables {
 			if strVal, ok := v.(string); ok && matchedScenario.Request.Variables[k] == "" {
@@ -114,10 +183,10 @@ func (cx *ConsumerExecutor) ExecuteWithKey(
 		}
 	}
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
-	respBytes, sharedVariables, err = cx.execute(req, respHeaders, matchedScenario, started)
+	respBytes, stream, sharedVariables, err = cx.execute(req, respHeaders, matchedScenario, started, streamFixture)
 	if err == nil {
 		cx.applyStateMachineTransitions(req, matchedScenario, respBytes)
 	}
@@ -169,8 +238,9 @@ func (cx *ConsumerExecutor) execute(
 	req *http.Request,
 	respHeaders http.Header,
 	matchedScenario *types.APIScenario,
-	started time.Time) ([]byte, map[string]any, error) {
-	return AddMockResponse(
+	started time.Time,
+	streamFixture bool) ([]byte, *FixtureStream, map[string]any, error) {
+	return addMockResponse(
 		req,
 		req.Header,
 		respHeaders,
@@ -181,10 +251,13 @@ func (cx *ConsumerExecutor) execute(
 		cx.scenarioRepository,
 		cx.fixtureRepository,
 		cx.groupConfigRepository,
+		cx.etags,
+		streamFixture,
 	)
 }
 
-// AddMockResponse method is shared so it cannot be instance method
+// AddMockResponse method is shared so it cannot be instance method; etags keeps ETags of conditional
+// requests of the caller and may be nil
 func AddMockResponse(
 	req *http.Request,
 	reqHeaders http.Header,
@@ -196,7 +269,27 @@ func AddMockResponse(
 	scenarioRepository repository.APIScenarioRepository,
 	fixtureRepository repository.APIFixtureRepository,
 	groupConfigRepository repository.GroupConfigRepository,
+	etags *ETagStore,
 ) (respBody []byte, sharedVariables map[string]any, err error) {
+	respBody, _, sharedVariables, err = addMockResponse(req, reqHeaders, respHeaders, scenario, started, ended,
+		config, scenarioRepository, fixtureRepository, groupConfigRepository, etags, false)
+	return
+}
+
+func addMockResponse(
+	req *http.Request,
+	reqHeaders http.Header,
+	respHeaders http.Header,
+	scenario *types.APIScenario,
+	started time.Time,
+	ended time.Time,
+	config *types.Configuration,
+	scenarioRepository repository.APIScenarioRepository,
+	fixtureRepository repository.APIFixtureRepository,
+	groupConfigRepository repository.GroupConfigRepository,
+	etags *ETagStore,
+	streamFixture bool,
+) (respBody []byte, stream *FixtureStream, sharedVariables map[string]any, err error) {
 	var inBody []byte
 	inBody, req.Body, err = utils.ReadAll(req.Body)
 	if err == nil && len(inBody) > 0 {
@@ -209,20 +302,22 @@ func AddMockResponse(
 	respHeaders.Add(types.MockScenarioPath, scenario.Path)
 	respHeaders.Add(types.MockRequestCount, fmt.Sprintf("%d", scenario.RequestCount))
 
+	var templateParams map[string]any
 	{
 		// check request assertions
-		templateParams, queryParams, postParams, reqHeaders := scenario.Request.BuildTemplateParams(
+		params, queryParams, postParams, reqHeaders := scenario.Request.BuildTemplateParams(
 			req,
 			scenario.ToKeyData().MatchGroups(scenario.Path),
 			reqHeaders,
 			make(map[string]any))
 		reqContents, err := fuzz.UnmarshalArrayOrObject(inBody)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to unmarshal request body for (%s) due to %w", scenario.Name, err)
+			return nil, nil, nil, fmt.Errorf("failed to unmarshal request body for (%s) due to %w", scenario.Name, err)
 		}
-		if err = scenario.Request.Assert(queryParams, postParams, reqHeaders, reqContents, templateParams); err != nil {
-			return nil, nil, err
+		if err = scenario.Request.Assert(queryParams, postParams, reqHeaders, reqContents, params); err != nil {
+			return nil, nil, nil, err
 		}
+		templateParams = params
 	}
 
 	for k, vals := range scenario.Response.Headers {
@@ -233,7 +328,7 @@ func AddMockResponse(
 
 	// Embedding this check to return chaos response based on group config
 	if b := CheckChaosForScenarioGroup(groupConfigRepository, scenario, respHeaders); b != nil {
-		return b, sharedVariables, nil
+		return b, nil, sharedVariables, nil
 	}
 
 	if config.RecordOnly || req.Header.Get(types.MockRecordMode) == types.MockRecordModeEnabled {
@@ -247,15 +342,42 @@ func AddMockResponse(
 			"Group":            scenario.Group,
 			//"Headers":          req.Header,
 		}).Infof("proxy server skipped local lookup due to record-mode")
-		return nil, nil, types.NewNotFoundError("proxy server skipping local lookup due to record-mode")
+		return nil, nil, nil, types.NewNotFoundError("proxy server skipping local lookup due to record-mode")
+	}
+
+	// Select representation by Accept header when scenario declares variants
+	if len(scenario.Response.Variants) > 0 {
+		respHeaders.Add(types.VaryHeader, types.AcceptHeader)
+		variant, ok := scenario.Response.NegotiateVariant(reqHeaders.Get(types.AcceptHeader))
+		if !ok {
+			scenario.Response.StatusCode = http.StatusNotAcceptable
+			setResponseContentType(scenario, respHeaders, "text/plain")
+			respBody = []byte(fmt.Sprintf("none of available content types %s is acceptable",
+				strings.Join(scenario.Response.ContentTypes(), ", ")))
+			respHeaders.Set(types.ContentLengthHeader, fmt.Sprintf("%d", len(respBody)))
+			err = saveResponseHistory(req, reqHeaders, respHeaders, scenario, respBody, scenarioRepository, started, ended)
+			return respBody, nil, sharedVariables, err
+		}
+		if variant != nil {
+			scenario.Response.Contents = variant.Contents
+			scenario.Response.ContentsFile = variant.ContentsFile
+			setResponseContentType(scenario, respHeaders, variant.ContentType)
+		}
 	}
 
 	// Override wait time from request header
 	if reqHeaders.Get(types.MockWaitBeforeReply) != "" {
 		scenario.WaitBeforeReply, _ = time.ParseDuration(reqHeaders.Get(types.MockWaitBeforeReply))
+	} else if scenario.WaitBeforeReply <= 0 {
+		groupConfig, _ := groupConfigRepository.Load(scenario.Group)
+		if latency := scenarioLatency(groupConfig, scenario); latency.HasDelay() {
+			scenario.WaitBeforeReply = latency.Sample()
+		} else {
+			scenario.WaitBeforeReply = replayLatency(config, scenarioRepository, groupConfig, scenario)
+		}
 	}
 
-	if scenario.WaitBeforeReply > 0 {
+	if scenario.WaitBeforeReply > 0 && req.Context().Value(skipMockDelayKey{}) == nil {
 		log.WithFields(log.Fields{
 			"Component": "ConsumerExecutor-AddMockResponse",
 			"Scenario":  scenario.Name,
@@ -263,6 +385,7 @@ func AddMockResponse(
 			"Delay":     scenario.WaitBeforeReply,
 		}).Infof("scenario sleep wait")
 		time.Sleep(scenario.WaitBeforeReply)
+		respHeaders.Set(types.MockLatency, scenario.WaitBeforeReply.String())
 	}
 	// Override response status from request header
 	if reqHeaders.Get(types.MockResponseStatus) != "" {
@@ -277,40 +400,91 @@ func AddMockResponse(
 
 	// Build output from contents-file or contents property
 	respBody = []byte(scenario.Response.Contents)
+	groupConfig, _ := groupConfigRepository.Load(scenario.Group)
 	if scenario.Response.ContentsFile != "" {
-		respBody, err = fixtureRepository.Get(
-			scenario.Method,
-			scenario.Response.ContentsFile,
-			scenario.Path)
+		if streamFixture && isStreamable(scenario, groupConfig) {
+			respBody = nil
+			stream, err = openFixtureStream(fixtureRepository, scenario)
+		} else {
+			respBody, err = fixtureRepository.Get(
+				scenario.Method,
+				scenario.Response.ContentsFile,
+				scenario.Path)
+		}
+	}
+	if err == nil && stream == nil {
+		respBody = groupConfig.RewriteResponse(req.URL, respHeaders, respBody)
+	}
+	if err == nil && groupConfig != nil && groupConfig.Conditional.IsEnabled() {
+		if status := applyConditional(req, reqHeaders, respHeaders, scenario,
+			groupConfig.Conditional, respBody, stream, templateParams, etags); status != 0 {
+			scenario.Response.StatusCode = status
+			respBody = nil
+			if stream != nil {
+				_ = stream.Close()
+				stream = nil
+			}
+		}
+	}
+	if stream != nil {
+		respHeaders.Set(types.ContentLengthHeader, fmt.Sprintf("%d", stream.Size))
+	} else {
+		respHeaders.Set(types.ContentLengthHeader, fmt.Sprintf("%d", len(respBody)))
 	}
-	respHeaders.Set(types.ContentLengthHeader, fmt.Sprintf("%d", len(respBody)))
 
 	_ = handleSharedVariables(scenario, respBody, map[string]any{},
 		groupConfigRepository.Variables(scenario.Group), sharedVariables, respHeaders)
 
 	if err == nil {
-		scenario.Response.Contents = string(respBody)
-		if scenario.Request.Headers == nil {
-			scenario.Request.Headers = make(map[string]string)
-		}
-		for k, vals := range reqHeaders {
-			for _, val := range vals {
-				scenario.Request.Headers[k] = val
-			}
-		}
-		if scenario.Response.Headers == nil {
-			scenario.Response.Headers = make(map[string][]string)
-		}
-		for k, vals := range respHeaders {
-			scenario.Response.Headers[k] = vals
-		}
-
-		err = scenarioRepository.SaveHistory(scenario, req.URL.String(), started, ended)
+		err = saveResponseHistory(req, reqHeaders, respHeaders, scenario, respBody, scenarioRepository, started, ended)
 	}
 
 	return
 }
 
+// saveResponseHistory records the served request and response in the execution history
+func saveResponseHistory(
+	req *http.Request,
+	reqHeaders http.Header,
+	respHeaders http.Header,
+	scenario *types.APIScenario,
+	respBody []byte,
+	scenarioRepository repository.APIScenarioRepository,
+	started time.Time,
+	ended time.Time,
+) error {
+	scenario.Response.Contents = string(respBody)
+	if scenario.Request.Headers == nil {
+		scenario.Request.Headers = make(map[string]string)
+	}
+	for k, vals := range reqHeaders {
+		for _, val := range vals {
+			scenario.Request.Headers[k] = val
+		}
+	}
+	if scenario.Response.Headers == nil {
+		scenario.Response.Headers = make(map[string][]string)
+	}
+	for k, vals := range respHeaders {
+		scenario.Response.Headers[k] = vals
+	}
+	return scenarioRepository.SaveHistory(scenario, req.URL.String(), started, ended)
+}
+
+// setResponseContentType replaces content type of the scenario response, regardless of its header case
+func setResponseContentType(scenario *types.APIScenario, respHeaders http.Header, contentType string) {
+	respHeaders.Set(types.ContentTypeHeader, contentType)
+	if scenario.Response.Headers == nil {
+		scenario.Response.Headers = make(http.Header)
+	}
+	for k := range scenario.Response.Headers {
+		if strings.EqualFold(k, types.ContentTypeHeader) {
+			delete(scenario.Response.Headers, k)
+		}
+	}
+	scenario.Response.Headers.Set(types.ContentTypeHeader, contentType)
+}
+
 // CheckChaosForScenarioGroup helper method
 func CheckChaosForScenarioGroup(
 	groupConfigRepository repository.GroupConfigRepository,
@@ -337,3 +511,45 @@ func CheckChaosForScenarioGroup(
 	}
 	return nil
 }
+
+// ScenarioLatency returns latency config of the scenario or else of its group
+func ScenarioLatency(
+	groupConfigRepository repository.GroupConfigRepository,
+	scenario *types.APIScenario) *types.LatencyConfig {
+	if scenario.Latency != nil {
+		return scenario.Latency
+	}
+	groupConfig, _ := groupConfigRepository.Load(scenario.Group)
+	return scenarioLatency(groupConfig, scenario)
+}
+
+func scenarioLatency(groupConfig *types.GroupConfig, scenario *types.APIScenario) *types.LatencyConfig {
+	if scenario.Latency != nil {
+		return scenario.Latency
+	}
+	if groupConfig != nil {
+		return groupConfig.Latency
+	}
+	return nil
+}
+
+// replayLatency returns recorded latency of scenario to reproduce based on group or global latency replay config,
+// where distribution mode uses percentiles cached by the scenario repository until scenarios are saved
+func replayLatency(
+	config *types.Configuration,
+	scenarioRepository repository.APIScenarioRepository,
+	groupConfig *types.GroupConfig,
+	scenario *types.APIScenario) time.Duration {
+	replay := &config.LatencyReplay
+	if groupConfig != nil && groupConfig.LatencyReplay != nil {
+		replay = groupConfig.LatencyReplay
+	}
+	if !replay.Enabled() {
+		return 0
+	}
+	var p50, p95 int64
+	if replay.Mode == types.LatencyReplayDistribution {
+		p50, p95 = scenarioRepository.LatencyPercentiles(scenario.ToKeyData())
+	}
+	return replay.DelayWithPercentiles(scenario.RecordedLatencyMillis(), p50, p95)
+}
@@ -490,6 +490,7 @@ func Test_ShouldAddMockResponseWithNilRequestWithoutQueryParams(t *testing.T) {
 		scenarioRepository,
 		fixtureRepository,
 		groupConfigRepository,
+		nil,
 	)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), `didn't match required request query param 'a' with regex '\d+'`)
@@ -524,6 +525,7 @@ func Test_ShouldAddMockResponseWithNilRequest(t *testing.T) {
 		scenarioRepository,
 		fixtureRepository,
 		groupConfigRepository,
+		nil,
 	)
 	require.NoError(t, err)
 }
@@ -557,6 +559,7 @@ func Test_ShouldNotAddMockResponseWithoutQueryParams(t *testing.T) {
 		scenarioRepository,
 		fixtureRepository,
 		groupConfigRepository,
+		nil,
 	)
 	require.Error(t, err)
 }
@@ -594,6 +597,7 @@ func Test_ShouldAddMockResponseWithRequest(t *testing.T) {
 		scenarioRepository,
 		fixtureRepository,
 		groupConfigRepository,
+		nil,
 	)
 	require.NoError(t, err)
 }
@@ -1608,3 +1612,103 @@ func Test_ShouldExecuteTransferAPISuite(t *testing.T) {
 		require.Contains(t, appliedRestrictions, "graylist")
 	})
 }
+
+func Test_ShouldReplayRecordedLatencyOfGroup(t *testing.T) {
+	config := types.BuildTestConfig()
+	config.DataDir = t.TempDir()
+	// GIVEN recorded scenarios with latencies
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	started := time.Now()
+	var scenario *types.APIScenario
+	for i, millis := range []int{100, 200, 300} {
+		scenario = types.BuildTestScenario(types.Get, fmt.Sprintf("latency_get_%d", i), "/api/latency/:id", i)
+		scenario.Group = "latency_replay"
+		scenario.StartTime = started
+		scenario.EndTime = started.Add(time.Duration(millis) * time.Millisecond)
+		require.NoError(t, scenarioRepository.Save(scenario))
+	}
+
+	// WHEN latency replay is not configured
+	// THEN no latency should be replayed
+	require.Equal(t, time.Duration(0), replayLatency(config, scenarioRepository, nil, scenario))
+
+	// WHEN latency replay is configured globally
+	config.LatencyReplay = types.LatencyReplayConfig{Mode: types.LatencyReplayScaled, Factor: 2}
+	// THEN scaled recorded latency should be replayed
+	require.Equal(t, 600*time.Millisecond, replayLatency(config, scenarioRepository, nil, scenario))
+
+	// WHEN group overrides latency replay with distribution capped at median
+	require.NoError(t, groupConfigRepository.Save("latency_replay", &types.GroupConfig{
+		LatencyReplay: &types.LatencyReplayConfig{Mode: types.LatencyReplayDistribution, MaxLatencyMillis: 200},
+	}))
+	groupConfig, err := groupConfigRepository.Load("latency_replay")
+	require.NoError(t, err)
+	// THEN latency should be sampled from all recordings of the scenario
+	delay := replayLatency(config, scenarioRepository, groupConfig, scenario)
+	require.True(t, delay > 0 && delay <= 200*time.Millisecond, delay)
+	// AND percentiles of the scenario should be cached
+	p50, p95 := scenarioRepository.LatencyPercentiles(scenario.ToKeyData())
+	require.Equal(t, int64(200), p50)
+	require.Equal(t, int64(300), p95)
+
+	// WHEN saving another recording of the scenario
+	slow := types.BuildTestScenario(types.Get, "latency_get_slow", "/api/latency/:id", 3)
+	slow.Group = "latency_replay"
+	slow.StartTime = started
+	slow.EndTime = started.Add(900 * time.Millisecond)
+	require.NoError(t, scenarioRepository.Save(slow))
+
+	// THEN cached percentiles should be refreshed
+	p50, p95 = scenarioRepository.LatencyPercentiles(scenario.ToKeyData())
+	require.Equal(t, int64(200), p50)
+	require.Equal(t, int64(900), p95)
+}
+
+func Test_ShouldAddMockResponseWithLatencyDistribution(t *testing.T) {
+	config := types.BuildTestConfig()
+	config.DataDir = t.TempDir()
+	// GIVEN repositories and a group with latency distribution
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	fixtureRepository, err := repository.NewFileFixtureRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	require.NoError(t, groupConfigRepository.Save("latency", &types.GroupConfig{
+		Latency: &types.LatencyConfig{Distribution: types.LatencyUniform, MinMillis: 10, MaxMillis: 30},
+	}))
+	u, err := url.Parse("https://localhost/api/todos/202?a=123&b=abc")
+	require.NoError(t, err)
+	reqHeader := http.Header{types.ContentTypeHeader: {"application/json"}, types.ETagHeader: {"123"}}
+
+	// WHEN adding mock response for scenario of the group without wait time
+	scenario := types.BuildTestScenario(types.Get, "latency_group", "/api/todos/:id", 1)
+	scenario.Group = "latency"
+	scenario.WaitBeforeReply = 0
+	resHeader := http.Header{}
+	_, _, err = AddMockResponse(&http.Request{URL: u}, reqHeader, resHeader, scenario, time.Now(), time.Now(),
+		config, scenarioRepository, fixtureRepository, groupConfigRepository, nil)
+
+	// THEN delay should be sampled from group distribution and reported in header and history
+	require.NoError(t, err)
+	require.True(t, scenario.WaitBeforeReply >= 10*time.Millisecond && scenario.WaitBeforeReply <= 30*time.Millisecond)
+	require.Equal(t, scenario.WaitBeforeReply.String(), resHeader.Get(types.MockLatency))
+	require.Equal(t, scenario.WaitBeforeReply.String(), scenario.Response.Headers.Get(types.MockLatency))
+
+	// WHEN scenario defines its own latency
+	scenario = types.BuildTestScenario(types.Get, "latency_scenario", "/api/todos/:id", 1)
+	scenario.Group = "latency"
+	scenario.WaitBeforeReply = 0
+	scenario.Latency = &types.LatencyConfig{Distribution: types.LatencyFixed, FixedMillis: 5, BytesPerSecond: 100}
+	resHeader = http.Header{}
+	_, _, err = AddMockResponse(&http.Request{URL: u}, reqHeader, resHeader, scenario, time.Now(), time.Now(),
+		config, scenarioRepository, fixtureRepository, groupConfigRepository, nil)
+
+	// THEN scenario latency should override group latency
+	require.NoError(t, err)
+	require.Equal(t, "5ms", resHeader.Get(types.MockLatency))
+	require.Equal(t, int64(100), ScenarioLatency(groupConfigRepository, scenario).BytesPerSecond)
+}
@@ -0,0 +1,40 @@
+package contract
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/bhatti/api-mock-service/internal/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// EncodeResponseBody compresses mock response with gzip, br or deflate if accepted by the client
+// unless the response is empty or already declares its content encoding.
+func EncodeResponseBody(
+	reqHeaders http.Header,
+	respHeaders http.Header,
+	status int,
+	respBody []byte) []byte {
+	if len(respBody) == 0 || status == http.StatusNoContent || status == http.StatusNotModified ||
+		respHeaders.Get(types.ContentEncodingHeader) != "" {
+		return respBody
+	}
+	encoding := types.NegotiateEncoding(reqHeaders.Get(types.AcceptEncodingHeader))
+	if encoding == "" {
+		return respBody
+	}
+	encoded, err := utils.EncodeBody(encoding, respBody)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Component": "ConsumerExecutor-EncodeResponseBody",
+			"Encoding":  encoding,
+			"Error":     err,
+		}).Warnf("failed to encode response body")
+		return respBody
+	}
+	respHeaders.Set(types.ContentEncodingHeader, encoding)
+	respHeaders.Add(types.VaryHeader, types.AcceptEncodingHeader)
+	respHeaders.Set(types.ContentLengthHeader, fmt.Sprintf("%d", len(encoded)))
+	return encoded
+}
@@ -0,0 +1,94 @@
+package contract
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bhatti/api-mock-service/internal/repository"
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/bhatti/api-mock-service/internal/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldNegotiateResponseVariantsAndEncoding(t *testing.T) {
+	// GIVEN a scenario with JSON, XML and CSV representations
+	config := types.BuildTestConfig()
+	config.DataDir = t.TempDir()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	fixtureRepository, err := repository.NewFileFixtureRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	require.NoError(t, fixtureRepository.Save(types.Get, "report.csv", "/reports/daily", []byte("id,total\n1,10\n")))
+	scenario := &types.APIScenario{
+		Method: types.Get,
+		Name:   "daily-report",
+		Path:   "/reports/daily",
+		Group:  "reports",
+		Response: types.APIResponse{
+			StatusCode: 200,
+			Headers:    http.Header{types.ContentTypeHeader: {"application/json"}},
+			Contents:   `{"id":1,"total":10}`,
+			Variants: []types.ResponseVariant{
+				{ContentType: "application/xml", Contents: "<report><id>1</id><total>10</total></report>"},
+				{ContentType: "text/csv", ContentsFile: "report.csv"},
+			},
+		},
+	}
+	require.NoError(t, scenarioRepository.Save(scenario))
+	player := NewConsumerExecutor(config, scenarioRepository, fixtureRepository, groupConfigRepository)
+	execute := func(headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/reports/daily", nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		require.NoError(t, player.Execute(echo.New().NewContext(req, rec)))
+		return rec
+	}
+
+	// WHEN requesting without Accept
+	rec := execute(nil)
+	// THEN default representation should be returned
+	require.Equal(t, 200, rec.Code)
+	require.Equal(t, `{"id":1,"total":10}`, rec.Body.String())
+	require.Equal(t, "Accept", rec.Header().Get(types.VaryHeader))
+
+	// WHEN requesting XML over JSON
+	rec = execute(map[string]string{types.AcceptHeader: "application/json;q=0.5, application/xml"})
+	// THEN XML variant should be returned
+	require.Equal(t, 200, rec.Code)
+	require.Contains(t, rec.Header().Get(types.ContentTypeHeader), "application/xml")
+	require.Contains(t, rec.Body.String(), "<report>")
+
+	// WHEN requesting CSV
+	rec = execute(map[string]string{types.AcceptHeader: "text/*"})
+	// THEN CSV fixture should be returned
+	require.Equal(t, 200, rec.Code)
+	require.Contains(t, rec.Header().Get(types.ContentTypeHeader), "text/csv")
+	require.Equal(t, "id,total\n1,10\n", rec.Body.String())
+
+	// WHEN requesting unsupported media type
+	rec = execute(map[string]string{types.AcceptHeader: "application/pdf"})
+	// THEN not-acceptable should be returned
+	require.Equal(t, 406, rec.Code)
+	require.Contains(t, rec.Header().Get(types.ContentTypeHeader), "text/plain")
+	require.Contains(t, rec.Body.String(), "text/csv")
+	// AND rejected negotiation should be recorded in history
+	history, err := scenarioRepository.LoadHistory("", "reports", 406, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Contains(t, history[0].Response.Contents, "is acceptable")
+
+	// WHEN client accepts compressed response
+	rec = execute(map[string]string{types.AcceptHeader: "text/csv", types.AcceptEncodingHeader: "gzip"})
+	// THEN body should be gzip encoded
+	require.Equal(t, 200, rec.Code)
+	require.Equal(t, "gzip", rec.Header().Get(types.ContentEncodingHeader))
+	decoded, err := utils.DecodeBody("gzip", rec.Body.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "id,total\n1,10\n", string(decoded))
+}
@@ -42,14 +42,29 @@ type ContractValidationResult struct {
 
 // ContractValidationStats summarizes validation results over time
 type ContractValidationStats struct {
-	ScenarioName    string
-	TotalExecutions int
-	SuccessCount    int
-	FailureCount    int
-	AverageLatency  float64
-	Top5Failures    []string
-	LastExecuted    time.Time
-	SuccessRate     float64
+	ScenarioName    string              `json:"scenario_name"`
+	Runs            int                 `json:"runs"`
+	TotalExecutions int                 `json:"total_executions"`
+	SuccessCount    int                 `json:"success_count"`
+	FailureCount    int                 `json:"failure_count"`
+	AverageLatency  float64             `json:"average_latency"`
+	Top5Failures    []string            `json:"top5_failures"`
+	LastExecuted    time.Time           `json:"last_executed"`
+	SuccessRate     float64             `json:"success_rate"`
+	Flaky           bool                `json:"flaky"`
+	FlakyRuns       int                 `json:"flaky_runs"`
+	Trend           []*ContractRunPoint `json:"trend"`
+}
+
+// ContractRunPoint is the outcome of a scenario in a recorded run
+type ContractRunPoint struct {
+	RunID         string    `json:"run_id"`
+	Label         string    `json:"label,omitempty"`
+	StartedAt     time.Time `json:"started_at"`
+	Status        string    `json:"status"`
+	Passed        int       `json:"passed"`
+	Failed        int       `json:"failed"`
+	LatencyMillis float64   `json:"latency_millis"`
 }
 
 // OpenAPISchemaViolation represents a single schema violation found during response validation.
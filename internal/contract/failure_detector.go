@@ -4,10 +4,11 @@ package contract
 
 import (
 	"context"
-	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/bhatti/api-mock-service/internal/fuzz"
+	"github.com/bhatti/api-mock-service/internal/metrics"
 	"github.com/bhatti/api-mock-service/internal/types"
 )
 
@@ -35,11 +36,12 @@ func NewProducerExecutorFailureDetector(
 	}
 }
 
-// TestScenario executes the scenario once and returns non-nil if it fails.
-// Implements shrink.FailureDetector.
+// TestScenario executes the candidate scenario once and returns non-nil if it fails.
+// The candidate is executed as is rather than looked up from the repository so that
+// shrunk bodies, headers and params are actually sent. Path params are substituted into
+// the URL for both {name} and :name placeholders because the executor would otherwise
+// match placeholders of the path with themselves. Implements shrink.FailureDetector.
 func (d *ProducerExecutorFailureDetector) TestScenario(ctx context.Context, scenario *types.APIScenario) error {
-	key := scenario.ToKeyData()
-	req := &http.Request{}
 	singleReq := &types.ProducerContractRequest{
 		BaseURL:        d.baseURL,
 		ExecutionTimes: 1,
@@ -47,11 +49,22 @@ func (d *ProducerExecutorFailureDetector) TestScenario(ctx context.Context, scen
 		Headers:        make(map[string][]string),
 		Params:         make(map[string]any),
 	}
-	res := d.executor.Execute(ctx, req, key, d.dataTemplate, singleReq)
-	if len(res.Errors) > 0 {
-		for _, msg := range res.Errors {
-			return fmt.Errorf("%s", msg)
+	url := scenario.BuildURL(d.baseURL)
+	for k, v := range scenario.Request.PathParams {
+		url = strings.ReplaceAll(url, "{"+k+"}", v)
+	}
+	segments := strings.Split(url, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		if v, ok := scenario.Request.PathParams[segment[1:]]; ok {
+			segments[i] = v
 		}
 	}
-	return nil
+	url = strings.Join(segments, "/")
+	res := types.NewProducerContractResponse()
+	_, err := d.executor.execute(ctx, &http.Request{}, url, scenario, singleReq,
+		res, d.dataTemplate, metrics.NewMetrics())
+	return err
 }
@@ -0,0 +1,147 @@
+package contract
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bhatti/api-mock-service/internal/fuzz"
+	"github.com/bhatti/api-mock-service/internal/repository"
+	"github.com/bhatti/api-mock-service/internal/shrink"
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/bhatti/api-mock-service/internal/web"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldDetectFailuresOfCandidateScenario(t *testing.T) {
+	// GIVEN a producer that crashes when debug query param is sent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("debug") != "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"error": "crash"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer server.Close()
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	executor := NewProducerExecutor(scenarioRepository, groupConfigRepository,
+		web.NewHTTPClient(config, web.NewAuthAdapter(config)))
+	detector := NewProducerExecutorFailureDetector(executor, server.URL,
+		fuzz.NewDataTemplateRequest(false, 1, 1), types.NewProducerContractRequest(server.URL, 1, 0))
+	scenario := &types.APIScenario{
+		Method: types.Get,
+		Name:   "get-order",
+		Path:   "/orders",
+		Group:  "failure_detector",
+		Request: types.APIRequest{
+			QueryParams: map[string]string{"debug": "true"},
+		},
+		Response: types.APIResponse{
+			StatusCode: 200,
+		},
+	}
+
+	// WHEN testing the scenario with the debug param
+	// THEN it should fail
+	require.Error(t, detector.TestScenario(context.Background(), scenario))
+
+	// AND WHEN testing a candidate without the debug param that was never saved
+	scenario.Request.QueryParams = map[string]string{}
+
+	// THEN it should pass
+	require.NoError(t, detector.TestScenario(context.Background(), scenario))
+}
+
+func Test_ShouldSendShrunkPathParamsOfCandidateScenario(t *testing.T) {
+	// GIVEN a producer that crashes for order ids longer than three characters
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if len(strings.TrimPrefix(r.URL.Path, "/orders/")) > 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"error": "crash"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer server.Close()
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	executor := NewProducerExecutor(scenarioRepository, groupConfigRepository,
+		web.NewHTTPClient(config, web.NewAuthAdapter(config)))
+	detector := NewProducerExecutorFailureDetector(executor, server.URL,
+		fuzz.NewDataTemplateRequest(false, 1, 1), types.NewProducerContractRequest(server.URL, 1, 0))
+	scenario := &types.APIScenario{
+		Method: types.Get,
+		Name:   "get-order-by-id",
+		Path:   "/orders/{id}",
+		Group:  "failure_detector",
+		Request: types.APIRequest{
+			PathParams: map[string]string{"id": "1234567890"},
+		},
+		Response: types.APIResponse{
+			StatusCode: 200,
+		},
+	}
+
+	// WHEN shrinking the failing scenario with the detector
+	result, err := shrink.Shrink(context.Background(), detector, scenario, shrink.ShrinkOptions{})
+
+	// THEN path param should be sent in the path and shortened to the minimal failing length
+	require.NoError(t, err)
+	require.True(t, result.Reduced)
+	require.Equal(t, "1234", result.Minimal.Request.PathParams["id"])
+	require.Contains(t, paths, "/orders/1234567890")
+	require.Contains(t, paths, "/orders/1234")
+	require.NotContains(t, paths, "/orders/{id}")
+}
+
+func Test_ShouldSendPathParamsOfColonPathOfCandidateScenario(t *testing.T) {
+	// GIVEN a producer that records paths of requests
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer server.Close()
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	executor := NewProducerExecutor(scenarioRepository, groupConfigRepository,
+		web.NewHTTPClient(config, web.NewAuthAdapter(config)))
+	detector := NewProducerExecutorFailureDetector(executor, server.URL,
+		fuzz.NewDataTemplateRequest(false, 1, 1), types.NewProducerContractRequest(server.URL, 1, 0))
+	scenario := &types.APIScenario{
+		Method: types.Get,
+		Name:   "get-order-item",
+		Path:   "/orders/:id/items/:idx",
+		Group:  "failure_detector",
+		Request: types.APIRequest{
+			PathParams: map[string]string{"id": "123", "idx": "7"},
+		},
+		Response: types.APIResponse{
+			StatusCode: 200,
+		},
+	}
+
+	// WHEN testing the scenario with a path of :name placeholders
+	require.NoError(t, detector.TestScenario(context.Background(), scenario))
+
+	// THEN path params should be substituted for whole segments of the path
+	require.Equal(t, []string{"/orders/123/items/7"}, paths)
+}
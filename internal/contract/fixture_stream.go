@@ -0,0 +1,175 @@
+package contract
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/repository"
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/bhatti/api-mock-service/internal/utils"
+	"github.com/bhatti/api-mock-service/internal/web"
+)
+
+// FixtureStream is a contents file of a scenario that is streamed from disk instead of loaded in memory
+type FixtureStream struct {
+	File    *os.File
+	Size    int64
+	ModTime time.Time
+}
+
+// Close closes the fixture file
+func (fs *FixtureStream) Close() error {
+	return fs.File.Close()
+}
+
+// isStreamable returns true if response body isn't needed for rewrite rules, shared variables or state transitions
+func isStreamable(scenario *types.APIScenario, groupConfig *types.GroupConfig) bool {
+	if len(scenario.Response.AddSharedVariables) > 0 || scenario.StateMachine != nil {
+		return false
+	}
+	if groupConfig != nil {
+		for _, rule := range groupConfig.RewriteRules {
+			if rule.Phase == types.RewriteResponsePhase {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func openFixtureStream(
+	fixtureRepository repository.APIFixtureRepository,
+	scenario *types.APIScenario) (*FixtureStream, error) {
+	file, err := fixtureRepository.Open(scenario.Method, scenario.Response.ContentsFile, scenario.Path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &FixtureStream{File: file, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// serveFixtureStream writes fixture with support for Range/If-Range, compression, chunked transfer and bandwidth limit
+func serveFixtureStream(
+	c web.APIContext,
+	scenario *types.APIScenario,
+	stream *FixtureStream,
+	latency *types.LatencyConfig) error {
+	defer func() {
+		_ = stream.Close()
+	}()
+	status := scenario.Response.StatusCode
+	headers := c.Response().Header()
+	headers.Set("Accept-Ranges", "bytes")
+	if headers.Get("Last-Modified") == "" {
+		headers.Set("Last-Modified", stream.ModTime.UTC().Format(http.TimeFormat))
+	}
+	var reader io.Reader = stream.File
+	length := stream.Size
+	req := c.Request()
+	// multiple ranges and other units are ignored and full contents is returned
+	if rangeHeader := req.Header.Get("Range"); status == http.StatusOK &&
+		strings.HasPrefix(rangeHeader, "bytes=") && !strings.Contains(rangeHeader, ",") &&
+		ifRangeMatches(req.Header.Get("If-Range"), headers, stream.ModTime) {
+		start, end, ok := parseByteRange(rangeHeader, stream.Size)
+		if !ok {
+			headers.Del(types.ContentLengthHeader)
+			headers.Set("Content-Range", fmt.Sprintf("bytes */%d", stream.Size))
+			return c.NoContent(http.StatusRequestedRangeNotSatisfiable)
+		}
+		if _, err := stream.File.Seek(start, io.SeekStart); err != nil {
+			return err
+		}
+		length = end - start + 1
+		reader = io.LimitReader(stream.File, length)
+		headers.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, stream.Size))
+		status = http.StatusPartialContent
+	}
+	// ranges apply to the fixture as-is so only full contents is compressed
+	encoding := ""
+	if status != http.StatusPartialContent && headers.Get(types.ContentEncodingHeader) == "" {
+		encoding = types.NegotiateEncoding(req.Header.Get(types.AcceptEncodingHeader))
+	}
+	if encoding != "" {
+		encoded, err := utils.NewEncodingReader(encoding, reader)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = encoded.Close()
+		}()
+		reader = encoded
+		headers.Set(types.ContentEncodingHeader, encoding)
+		headers.Add(types.VaryHeader, types.AcceptEncodingHeader)
+		headers.Del(types.ContentLengthHeader)
+	} else if scenario.Response.Chunked {
+		headers.Del(types.ContentLengthHeader)
+	} else {
+		headers.Set(types.ContentLengthHeader, strconv.FormatInt(length, 10))
+	}
+	if latency != nil && latency.BytesPerSecond > 0 {
+		reader = utils.NewThrottledReader(reader, latency.BytesPerSecond, func() {
+			if flusher, ok := c.Response().Writer.(http.Flusher); ok {
+				flusher.Flush()
+			}
+		})
+	}
+	return c.Stream(status, scenario.Response.ContentType(""), reader)
+}
+
+// ifRangeMatches checks If-Range against ETag of the response or modification time of the fixture
+func ifRangeMatches(ifRange string, headers http.Header, modTime time.Time) bool {
+	if ifRange == "" {
+		return true
+	}
+	if strings.HasPrefix(ifRange, `"`) || strings.HasPrefix(ifRange, "W/") {
+		etag := headers.Get(types.ETagHeader)
+		return etag != "" && !strings.HasPrefix(ifRange, "W/") && ifRange == etag
+	}
+	t, err := http.ParseTime(ifRange)
+	return err == nil && !modTime.Truncate(time.Second).After(t)
+}
+
+// parseByteRange parses single range such as bytes=0-99, bytes=100- or bytes=-100
+func parseByteRange(rangeHeader string, size int64) (start int64, end int64, ok bool) {
+	spec, found := strings.CutPrefix(strings.TrimSpace(rangeHeader), "bytes=")
+	if !found {
+		return 0, 0, false
+	}
+	from, to, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return 0, 0, false
+	}
+	var err error
+	if from == "" {
+		var suffix int64
+		if suffix, err = strconv.ParseInt(to, 10, 64); err != nil || suffix <= 0 {
+			return 0, 0, false
+		}
+		if suffix > size {
+			suffix = size
+		}
+		return size - suffix, size - 1, size > 0
+	}
+	if start, err = strconv.ParseInt(from, 10, 64); err != nil || start < 0 || start >= size {
+		return 0, 0, false
+	}
+	end = size - 1
+	if to != "" {
+		if end, err = strconv.ParseInt(to, 10, 64); err != nil || end < start {
+			return 0, 0, false
+		}
+		if end >= size {
+			end = size - 1
+		}
+	}
+	return start, end, true
+}
@@ -0,0 +1,127 @@
+package contract
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/repository"
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldStreamFixtureWithRanges(t *testing.T) {
+	// GIVEN a scenario with a large contents file
+	config := types.BuildTestConfig()
+	config.DataDir = t.TempDir()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	fixtureRepository, err := repository.NewFileFixtureRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte('a' + i%26)
+	}
+	require.NoError(t, fixtureRepository.Save(types.Get, "archive.bin", "/downloads/archive", data))
+	scenario := &types.APIScenario{
+		Method: types.Get,
+		Name:   "download-archive",
+		Path:   "/downloads/archive",
+		Group:  "downloads",
+		Response: types.APIResponse{
+			StatusCode:   200,
+			ContentsFile: "archive.bin",
+			Headers: http.Header{
+				types.ContentTypeHeader: {"application/octet-stream"},
+				types.ETagHeader:        {`"v1"`},
+			},
+		},
+	}
+	require.NoError(t, scenarioRepository.Save(scenario))
+	player := NewConsumerExecutor(config, scenarioRepository, fixtureRepository, groupConfigRepository)
+	execute := func(headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/downloads/archive", nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		require.NoError(t, player.Execute(echo.New().NewContext(req, rec)))
+		return rec
+	}
+
+	// WHEN downloading without range
+	rec := execute(nil)
+	// THEN full contents should be returned
+	require.Equal(t, 200, rec.Code)
+	require.Equal(t, data, rec.Body.Bytes())
+	require.Equal(t, "1000", rec.Header().Get(types.ContentLengthHeader))
+	require.Equal(t, "bytes", rec.Header().Get("Accept-Ranges"))
+
+	// WHEN resuming download with range and matching If-Range
+	rec = execute(map[string]string{"Range": "bytes=100-199", "If-Range": `"v1"`})
+	// THEN partial contents should be returned
+	require.Equal(t, 206, rec.Code)
+	require.Equal(t, data[100:200], rec.Body.Bytes())
+	require.Equal(t, "bytes 100-199/1000", rec.Header().Get("Content-Range"))
+	require.Equal(t, "100", rec.Header().Get(types.ContentLengthHeader))
+
+	// WHEN If-Range doesn't match
+	rec = execute(map[string]string{"Range": "bytes=100-199", "If-Range": `"v0"`})
+	// THEN full contents should be returned
+	require.Equal(t, 200, rec.Code)
+	require.Len(t, rec.Body.Bytes(), 1000)
+
+	// WHEN range is not satisfiable
+	rec = execute(map[string]string{"Range": "bytes=2000-"})
+	// THEN 416 should be returned
+	require.Equal(t, 416, rec.Code)
+	require.Equal(t, "bytes */1000", rec.Header().Get("Content-Range"))
+
+	// WHEN scenario uses chunked transfer with bandwidth limit
+	scenario.Response.Chunked = true
+	scenario.Latency = &types.LatencyConfig{BytesPerSecond: 4000}
+	require.NoError(t, scenarioRepository.Save(scenario))
+	started := time.Now()
+	rec = execute(map[string]string{"Range": "bytes=-500"})
+	// THEN last bytes should be dripped without content length
+	require.Equal(t, 206, rec.Code)
+	require.Equal(t, data[500:], rec.Body.Bytes())
+	require.Equal(t, "", rec.Header().Get(types.ContentLengthHeader))
+	require.True(t, time.Since(started) >= 100*time.Millisecond)
+}
+
+func Test_ShouldParseByteRange(t *testing.T) {
+	start, end, ok := parseByteRange("bytes=0-99", 1000)
+	require.True(t, ok)
+	require.Equal(t, []int64{0, 99}, []int64{start, end})
+	start, end, ok = parseByteRange("bytes=900-", 1000)
+	require.True(t, ok)
+	require.Equal(t, []int64{900, 999}, []int64{start, end})
+	start, end, ok = parseByteRange("bytes=-2000", 1000)
+	require.True(t, ok)
+	require.Equal(t, []int64{0, 999}, []int64{start, end})
+	start, end, ok = parseByteRange("bytes=990-2000", 1000)
+	require.True(t, ok)
+	require.Equal(t, []int64{990, 999}, []int64{start, end})
+	_, _, ok = parseByteRange("bytes=1000-", 1000)
+	require.False(t, ok)
+	_, _, ok = parseByteRange("bytes=5-1", 1000)
+	require.False(t, ok)
+	_, _, ok = parseByteRange("items=0-1", 1000)
+	require.False(t, ok)
+}
+
+func Test_ShouldMatchIfRange(t *testing.T) {
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	headers := http.Header{types.ETagHeader: {`"v1"`}}
+	require.True(t, ifRangeMatches("", headers, modTime))
+	require.True(t, ifRangeMatches(`"v1"`, headers, modTime))
+	require.False(t, ifRangeMatches(`W/"v1"`, headers, modTime))
+	require.False(t, ifRangeMatches(`"v2"`, headers, modTime))
+	require.True(t, ifRangeMatches(modTime.Format(http.TimeFormat), headers, modTime))
+	require.False(t, ifRangeMatches(modTime.Add(-time.Hour).Format(http.TimeFormat), headers, modTime))
+}
@@ -0,0 +1,319 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/fuzz"
+	"github.com/bhatti/api-mock-service/internal/metrics"
+	"github.com/bhatti/api-mock-service/internal/types"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+// loadSamples of a scenario
+type loadSamples struct {
+	latencies      []float64
+	errors         int
+	statusCounts   map[string]int
+	errorsByStatus map[string]int
+	lastErr        error
+}
+
+// loadRecorder collects samples of load test from all virtual users
+type loadRecorder struct {
+	lock      sync.Mutex
+	scenarios map[string]*loadSamples
+}
+
+func newLoadRecorder() *loadRecorder {
+	return &loadRecorder{scenarios: make(map[string]*loadSamples)}
+}
+
+func (r *loadRecorder) record(name string, obs *responseObservation, err error) {
+	status := "error"
+	if obs.status > 0 {
+		status = strconv.Itoa(obs.status)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	samples := r.scenarios[name]
+	if samples == nil {
+		samples = &loadSamples{statusCounts: make(map[string]int), errorsByStatus: make(map[string]int)}
+		r.scenarios[name] = samples
+	}
+	if obs.sent {
+		samples.latencies = append(samples.latencies, float64(obs.elapsed.Microseconds())/1000.0)
+	}
+	samples.statusCounts[status]++
+	if err != nil {
+		samples.errors++
+		samples.errorsByStatus[status]++
+		samples.lastErr = err
+	}
+}
+
+// scenarioError returns error of scenario if its error rate exceeds max error rate or SLO of the load test failed
+func (r *loadRecorder) scenarioError(
+	name string,
+	report *types.LoadReport,
+	thresholds types.LoadThresholds) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	stats := report.Scenarios[name]
+	if samples := r.scenarios[name]; stats.Errors > 0 && stats.ErrorRate > thresholds.MaxErrorRate {
+		return fmt.Errorf("%d of %d load requests failed (error rate %.4f), last error: %w",
+			stats.Errors, stats.Requests, stats.ErrorRate, samples.lastErr)
+	}
+	if !report.Passed {
+		var failed []string
+		for _, slo := range report.SLO {
+			if !slo.Passed {
+				failed = append(failed, fmt.Sprintf("%s %g (threshold %g)", slo.Name, slo.Actual, slo.Threshold))
+			}
+		}
+		return fmt.Errorf("load SLO failed: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func (r *loadRecorder) report(elapsed time.Duration, thresholds types.LoadThresholds) *types.LoadReport {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	secs := elapsed.Seconds()
+	report := &types.LoadReport{
+		DurationSecs:   secs,
+		StatusCounts:   make(map[string]int),
+		ErrorsByStatus: make(map[string]int),
+		Scenarios:      make(map[string]*types.ScenarioLoadStats),
+	}
+	var latencies []float64
+	for name, samples := range r.scenarios {
+		stats := &types.ScenarioLoadStats{
+			Errors:       samples.errors,
+			Latency:      types.NewLoadLatency(samples.latencies),
+			StatusCounts: samples.statusCounts,
+		}
+		for status, n := range samples.statusCounts {
+			stats.Requests += n
+			report.StatusCounts[status] += n
+		}
+		for status, n := range samples.errorsByStatus {
+			report.ErrorsByStatus[status] += n
+		}
+		if stats.Requests > 0 {
+			stats.ErrorRate = float64(stats.Errors) / float64(stats.Requests)
+		}
+		if secs > 0 {
+			stats.Throughput = float64(stats.Requests) / secs
+		}
+		report.Requests += stats.Requests
+		report.Errors += stats.Errors
+		report.Scenarios[name] = stats
+		latencies = append(latencies, samples.latencies...)
+	}
+	report.Latency = types.NewLoadLatency(latencies)
+	if report.Requests > 0 {
+		report.ErrorRate = float64(report.Errors) / float64(report.Requests)
+	}
+	if secs > 0 {
+		report.Throughput = float64(report.Requests) / secs
+	}
+	report.EvaluateSLO(thresholds)
+	return report
+}
+
+// weightedScenarios picks scenarios randomly by their weights
+type weightedScenarios struct {
+	keys       []*types.APIKeyData
+	cumulative []int
+}
+
+func (ws *weightedScenarios) add(scenario *types.APIKeyData, weight int) {
+	total := weight
+	if len(ws.cumulative) > 0 {
+		total += ws.cumulative[len(ws.cumulative)-1]
+	}
+	ws.keys = append(ws.keys, scenario)
+	ws.cumulative = append(ws.cumulative, total)
+}
+
+func (ws *weightedScenarios) pick(rnd *rand.Rand) *types.APIKeyData {
+	n := rnd.Intn(ws.cumulative[len(ws.cumulative)-1])
+	for i, c := range ws.cumulative {
+		if n < c {
+			return ws.keys[i]
+		}
+	}
+	return ws.keys[len(ws.keys)-1]
+}
+
+// ExecuteLoadByGroup runs scenarios of group by their weights from virtual users for the duration of load config
+// and reports latency percentiles, throughput, errors by status and SLO results.
+func (px *ProducerExecutor) ExecuteLoadByGroup(
+	ctx context.Context,
+	req *http.Request,
+	group string,
+	dataTemplate fuzz.DataTemplateRequest,
+	contractReq *types.ProducerContractRequest,
+) *types.ProducerContractResponse {
+	started := time.Now()
+	contractResponse := types.NewProducerContractResponse()
+	load := contractReq.Load
+	if load == nil {
+		contractResponse.Add(group+"_load", nil, fmt.Errorf("load config is not specified"))
+		return contractResponse
+	}
+	if err := load.Validate(); err != nil {
+		contractResponse.Add(group+"_load", nil, err)
+		return contractResponse
+	}
+
+	sli := metrics.NewMetrics()
+	scenarios := &weightedScenarios{}
+	for _, scenarioKey := range px.scenarioRepository.LookupAllByGroup(group) {
+		weight := load.Weight(scenarioKey.Name)
+		if weight <= 0 {
+			continue
+		}
+		if contractReq.MatchResponseCode > 0 {
+			scenarioKey.Response = types.APIResponseKey{StatusCode: contractReq.MatchResponseCode}
+		}
+		if _, err := px.scenarioRepository.Lookup(scenarioKey, contractReq.Overrides()); err != nil {
+			log.WithFields(log.Fields{
+				"Component":   "ProducerExecutor",
+				"Group":       group,
+				"ScenarioKey": scenarioKey.String(),
+				"Error":       err,
+			}).Warnf("failed to lookup for load")
+			contractResponse.AddMismatched()
+			continue
+		}
+		sli.RegisterHistogram(scenarioKey.SafeName())
+		scenarios.add(scenarioKey, weight)
+	}
+	if len(scenarios.keys) == 0 {
+		contractResponse.Add(group+"_load", nil, fmt.Errorf("no scenarios found for load test of group %s", group))
+		return contractResponse
+	}
+
+	log.WithFields(log.Fields{
+		"Component":               "ProducerExecutor",
+		"Group":                   group,
+		"ProducerContractRequest": contractReq.String(),
+		"Scenarios":               len(scenarios.keys),
+		"Load":                    load,
+	}).Infof("execute-load-by-group BEGIN")
+
+	lx := px.withClient(&observingClient{HTTPClient: px.client})
+	recorder := newLoadRecorder()
+	stopCtx, cancel := context.WithDeadline(ctx, started.Add(load.Duration()))
+	defer cancel()
+	limiter := newRateLimiter(load.TargetRPS)
+	if limiter != nil && load.RampUpSecs > 0 {
+		go rampUpRate(stopCtx, limiter, load.TargetRPS, started, load.RampUp())
+	}
+
+	users := load.Users()
+	var wg sync.WaitGroup
+	for u := 0; u < users; u++ {
+		wg.Add(1)
+		go func(u int) {
+			defer wg.Done()
+			if load.RampUpSecs > 0 {
+				select {
+				case <-stopCtx.Done():
+					return
+				case <-time.After(load.RampUp() * time.Duration(u) / time.Duration(users)):
+				}
+			}
+			userReq := contractReq.Clone()
+			rnd := rand.New(rand.NewSource(time.Now().UnixNano() + int64(u)))
+			for stopCtx.Err() == nil {
+				if limiter != nil && limiter.Wait(stopCtx) != nil {
+					// wait fails early when the next request would exceed duration
+					<-stopCtx.Done()
+					return
+				}
+				// scenario is looked up for each request so that templates generate new fuzz data
+				scenarioKey := scenarios.pick(rnd)
+				scenario, err := lx.scenarioRepository.Lookup(scenarioKey, userReq.Overrides())
+				if err != nil {
+					// the virtual user is stopped as retrying a failed lookup would spin without sending requests
+					log.WithFields(log.Fields{
+						"Component":   "ProducerExecutor",
+						"Group":       group,
+						"ScenarioKey": scenarioKey.String(),
+						"User":        u,
+						"Error":       err,
+					}).Warnf("failed to lookup for load, stopping virtual user")
+					recorder.record(scenarioKey.Name, &responseObservation{}, err)
+					return
+				}
+				obs := &responseObservation{}
+				url := scenario.BuildURL(userReq.BaseURL)
+				_, err = lx.execute(context.WithValue(stopCtx, responseObservationKey{}, obs), req, url, scenario,
+					userReq, contractResponse, dataTemplate, sli)
+				if err != nil && stopCtx.Err() != nil {
+					// request was aborted at the end of the duration rather than failed by the producer
+					return
+				}
+				recorder.record(scenario.Name, obs, err)
+				time.Sleep(scenario.WaitBeforeReply)
+			}
+		}(u)
+	}
+	wg.Wait()
+
+	elapsed := time.Since(started)
+	contractResponse.Load = recorder.report(elapsed, load.Thresholds)
+	names := make([]string, 0, len(contractResponse.Load.Scenarios))
+	for name := range contractResponse.Load.Scenarios {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	// each scenario is counted once by its aggregated outcome rather than by its requests
+	for _, name := range names {
+		contractResponse.Add(name+"_load", nil, recorder.scenarioError(name, contractResponse.Load, load.Thresholds))
+	}
+	contractResponse.Metrics = sli.Summary()
+	px.recordRun(group, types.RunKindLoad, started, contractReq, contractResponse)
+	log.WithFields(log.Fields{
+		"Component":  "ProducerExecutor",
+		"Group":      group,
+		"Elapsed":    elapsed.String(),
+		"Requests":   contractResponse.Load.Requests,
+		"Throughput": contractResponse.Load.Throughput,
+		"ErrorRate":  contractResponse.Load.ErrorRate,
+		"Passed":     contractResponse.Load.Passed,
+	}).Infof("execute-load-by-group COMPLETED")
+	return contractResponse
+}
+
+// rampUpRate increases limit of rate limiter linearly to target rps during ramp-up
+func rampUpRate(ctx context.Context, limiter *rate.Limiter, targetRPS float64, started time.Time, rampUp time.Duration) {
+	const tick = 100 * time.Millisecond
+	limiter.SetLimit(rate.Limit(targetRPS * float64(tick) / float64(rampUp)))
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			progress := float64(now.Sub(started)) / float64(rampUp)
+			if progress >= 1 {
+				limiter.SetLimit(rate.Limit(targetRPS))
+				return
+			}
+			limiter.SetLimit(rate.Limit(targetRPS * progress))
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/fuzz"
+	"github.com/bhatti/api-mock-service/internal/repository"
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/bhatti/api-mock-service/internal/web"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldExecuteLoadByGroup(t *testing.T) {
+	// GIVEN a producer that fails checkout requests
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasPrefix(r.URL.Path, "/checkout") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	// AND weighted scenarios of a group
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	group := fmt.Sprintf("load_group_%d", time.Now().UnixNano())
+	items := newParallelTestScenario(group, "/items/1", 0)
+	checkout := newParallelTestScenario(group, "/checkout/1", 0)
+	skipped := newParallelTestScenario(group, "/skipped/1", 0)
+	for _, scenario := range []*types.APIScenario{items, checkout, skipped} {
+		require.NoError(t, scenarioRepository.Save(scenario))
+	}
+	runRepository, err := repository.NewFileContractRunRepository(&types.Configuration{DataDir: t.TempDir()})
+	require.NoError(t, err)
+	executor := NewProducerExecutor(scenarioRepository, groupConfigRepository, web.NewHTTPClient(config, web.NewAuthAdapter(config))).
+		WithRunRepository(runRepository)
+	contractReq := types.NewProducerContractRequest(server.URL, 1, 0)
+	contractReq.RecordResults = true
+	contractReq.RunLabel = "load-v1"
+	contractReq.Load = &types.LoadConfig{
+		DurationSecs: 1,
+		TargetRPS:    50,
+		VirtualUsers: 4,
+		Weights:      map[string]int{items.Name: 3, checkout.Name: 1, skipped.Name: 0},
+		Thresholds:   types.LoadThresholds{P99Millis: 5000, MaxErrorRate: 0.01},
+	}
+
+	// WHEN executing the group in load mode
+	started := time.Now()
+	res := executor.ExecuteByGroup(context.Background(), &http.Request{}, group, fuzz.NewDataTemplateRequest(false, 1, 1), contractReq)
+
+	// THEN requests should be throttled for the duration and reported by scenario and status
+	require.True(t, time.Since(started) >= time.Second)
+	report := res.Load
+	require.NotNil(t, report)
+	require.True(t, report.Requests > 20 && report.Requests <= 60, fmt.Sprintf("%d", report.Requests))
+	require.Len(t, report.Scenarios, 2)
+	require.Nil(t, report.Scenarios[skipped.Name])
+	require.True(t, report.Scenarios[items.Name].Requests > report.Scenarios[checkout.Name].Requests)
+	require.Equal(t, report.Scenarios[checkout.Name].Requests, report.ErrorsByStatus["503"])
+	require.Equal(t, report.Scenarios[items.Name].Requests, report.StatusCounts["200"])
+	require.Equal(t, 1.0, report.Scenarios[checkout.Name].ErrorRate)
+	require.True(t, report.Latency.P99 > 0 && report.Latency.P50 <= report.Latency.P99)
+
+	// AND SLO should fail on error rate
+	require.False(t, report.Passed)
+	require.Len(t, report.SLO, 2)
+	require.True(t, report.SLO[0].Passed)
+	require.False(t, report.SLO[1].Passed)
+	require.Contains(t, report.PrometheusText(),
+		fmt.Sprintf(`load_responses_total{scenario="%s",status="503"}`, checkout.Name))
+
+	// AND each scenario should be counted once by its error rate and the SLO
+	require.Equal(t, 0, res.Succeeded)
+	require.Equal(t, 2, res.Failed)
+	require.Contains(t, res.Errors[checkout.Name+"_load"], "load requests failed")
+	require.Contains(t, res.Errors[checkout.Name+"_load"], "503")
+	require.Contains(t, res.Errors[items.Name+"_load"], "load SLO failed: max_error_rate")
+
+	// AND run should be recorded as load run of the group
+	require.NotEmpty(t, res.RunID)
+	run, err := executor.LoadRun(res.RunID)
+	require.NoError(t, err)
+	require.Equal(t, group, run.Group)
+	require.Equal(t, types.RunKindLoad, run.Kind)
+	require.Equal(t, "load-v1", run.Label)
+	require.Len(t, run.Scenarios, 2)
+}
+
+func Test_ShouldPassLoadScenariosWithinErrorRate(t *testing.T) {
+	// GIVEN a producer that responds successfully
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	group := fmt.Sprintf("load_pass_group_%d", time.Now().UnixNano())
+	items := newParallelTestScenario(group, "/items/1", 0)
+	require.NoError(t, scenarioRepository.Save(items))
+	executor := NewProducerExecutor(scenarioRepository, groupConfigRepository, web.NewHTTPClient(config, web.NewAuthAdapter(config)))
+	contractReq := types.NewProducerContractRequest(server.URL, 1, 0)
+	contractReq.Load = &types.LoadConfig{DurationSecs: 1, TargetRPS: 20, VirtualUsers: 2}
+
+	// WHEN executing load
+	res := executor.ExecuteLoadByGroup(context.Background(), &http.Request{}, group, fuzz.NewDataTemplateRequest(false, 1, 1), contractReq)
+
+	// THEN scenario should succeed once regardless of number of requests
+	require.True(t, res.Load.Requests > 1)
+	require.Equal(t, 0, res.Load.Errors, res.Errors)
+	require.Equal(t, 1, res.Succeeded)
+	require.Equal(t, 0, res.Failed)
+}
+
+func Test_ShouldNotExecuteLoadWithInvalidConfig(t *testing.T) {
+	// GIVEN executor and load config without duration
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	executor := NewProducerExecutor(scenarioRepository, groupConfigRepository, web.NewStubHTTPClient())
+	contractReq := types.NewProducerContractRequest(baseURL, 1, 0)
+	contractReq.Load = &types.LoadConfig{}
+
+	// WHEN executing load
+	res := executor.ExecuteLoadByGroup(context.Background(), &http.Request{}, "missing", fuzz.NewDataTemplateRequest(false, 1, 1), contractReq)
+
+	// THEN it should fail without load report
+	require.Equal(t, 1, res.Failed)
+	require.Nil(t, res.Load)
+}
+
+func Test_ShouldStopVirtualUserWhenLoadLookupFails(t *testing.T) {
+	// GIVEN a scenario repository whose lookups fail after the scenario is checked
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	group := fmt.Sprintf("load_lookup_group_%d", time.Now().UnixNano())
+	items := newParallelTestScenario(group, "/items/1", 0)
+	require.NoError(t, scenarioRepository.Save(items))
+	failingRepository := &failingLookupRepository{APIScenarioRepository: scenarioRepository, succeed: 1}
+	executor := NewProducerExecutor(failingRepository, groupConfigRepository, web.NewStubHTTPClient())
+	contractReq := types.NewProducerContractRequest(baseURL, 1, 0)
+	contractReq.Load = &types.LoadConfig{DurationSecs: 2, VirtualUsers: 3}
+
+	// WHEN executing load without target rps
+	started := time.Now()
+	res := executor.ExecuteLoadByGroup(context.Background(), &http.Request{}, group, fuzz.NewDataTemplateRequest(false, 1, 1), contractReq)
+
+	// THEN each virtual user should record the lookup error and stop before the duration
+	require.True(t, time.Since(started) < time.Second, time.Since(started).String())
+	require.Equal(t, 0, res.Mismatched)
+	require.Equal(t, 1, res.Failed)
+	require.Contains(t, res.Errors[items.Name+"_load"], "lookup failed")
+	require.Equal(t, 3, res.Load.Requests)
+	require.Equal(t, 3, res.Load.ErrorsByStatus["error"])
+}
+
+// failingLookupRepository fails lookups after the given number of successful lookups
+type failingLookupRepository struct {
+	repository.APIScenarioRepository
+	lock    sync.Mutex
+	succeed int
+}
+
+func (r *failingLookupRepository) Lookup(key *types.APIKeyData, data map[string]any) (*types.APIScenario, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.succeed <= 0 {
+		return nil, fmt.Errorf("lookup failed for %s", key.Name)
+	}
+	r.succeed--
+	return r.APIScenarioRepository.Lookup(key, data)
+}
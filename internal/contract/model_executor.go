@@ -0,0 +1,321 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/fuzz"
+	"github.com/bhatti/api-mock-service/internal/metrics"
+	"github.com/bhatti/api-mock-service/internal/shrink"
+	"github.com/bhatti/api-mock-service/internal/types"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// ExecuteModelByGroup treats state machines of the group's scenarios as a model: it generates random valid
+// sequences of API calls from the initial state, executes them and checks response assertions and invariants
+// after each step. The first failing sequence is shrunk to the minimal sequence that still fails.
+func (px *ProducerExecutor) ExecuteModelByGroup(
+	ctx context.Context,
+	req *http.Request,
+	group string,
+	dataTemplate fuzz.DataTemplateRequest,
+	contractReq *types.ProducerContractRequest,
+) *types.ProducerContractResponse {
+	started := time.Now()
+	contractResponse := types.NewProducerContractResponse()
+	config := contractReq.Model
+	if config == nil {
+		contractResponse.Add(group+"_model", nil, fmt.Errorf("model config is not specified"))
+		return contractResponse
+	}
+	model, err := px.buildStateModel(group, contractReq)
+	if err != nil {
+		contractResponse.Add(group+"_model", nil, err)
+		return contractResponse
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	log.WithFields(log.Fields{
+		"Component":               "ProducerExecutor",
+		"Group":                   group,
+		"ProducerContractRequest": contractReq.String(),
+		"Seed":                    seed,
+		"Scenarios":               len(model.scenarios),
+	}).Infof("execute-model BEGIN")
+
+	sli := metrics.NewMetrics()
+	for name := range model.keys {
+		sli.RegisterHistogram(model.keys[name].SafeName())
+	}
+	runner := &modelRunner{
+		px:           px,
+		req:          req,
+		model:        model,
+		initialState: config.InitialState,
+		invariants:   append(px.groupInvariants(group), config.Invariants...),
+		contractReq:  contractReq,
+		dataTemplate: dataTemplate,
+		sli:          sli,
+	}
+	report := &types.ModelReport{Seed: seed}
+	visited := map[string]bool{config.InitialState: true}
+	rnd := rand.New(rand.NewSource(seed))
+	for i := 0; i < config.SequenceCount() && ctx.Err() == nil; i++ {
+		sequence := model.generate(rnd, config.InitialState, config.Steps())
+		if len(sequence) == 0 {
+			break
+		}
+		report.Sequences++
+		steps, err := runner.run(ctx, sequence, contractResponse, report.Steps, visited)
+		report.Steps += steps
+		if err != nil {
+			report.Failure = runner.shrink(ctx, sequence, err)
+			break
+		}
+	}
+	if report.Sequences == 0 {
+		contractResponse.Add(group+"_model", nil,
+			fmt.Errorf("no scenario of group %s can be called in state '%s'", group, config.InitialState))
+	}
+	report.StatesVisited = make([]string, 0, len(visited))
+	for state := range visited {
+		if state != "" {
+			report.StatesVisited = append(report.StatesVisited, state)
+		}
+	}
+	sort.Strings(report.StatesVisited)
+	report.UnvisitedStates = make([]string, 0)
+	for _, state := range types.BuildStateMachineGraph(group, model.scenarios).States {
+		if !visited[state] {
+			report.UnvisitedStates = append(report.UnvisitedStates, state)
+		}
+	}
+	contractResponse.Model = report
+	contractResponse.Metrics = sli.Summary()
+	px.recordRun(group, types.RunKindModel, started, contractReq, contractResponse)
+	log.WithFields(log.Fields{
+		"Component": "ProducerExecutor",
+		"Group":     group,
+		"Seed":      seed,
+		"Sequences": report.Sequences,
+		"Steps":     report.Steps,
+		"Passed":    report.Passed(),
+		"Elapsed":   time.Since(started).String(),
+	}).Infof("execute-model COMPLETED")
+	return contractResponse
+}
+
+// buildStateModel loads scenarios of the group with state machines without rendering their templates
+func (px *ProducerExecutor) buildStateModel(group string, contractReq *types.ProducerContractRequest) (*stateModel, error) {
+	model := &stateModel{keys: make(map[string]*types.APIKeyData)}
+	for _, keyData := range px.scenarioRepository.LookupAllByGroup(group) {
+		b, err := px.scenarioRepository.LoadRaw(keyData.Method, keyData.Name, keyData.Path)
+		if err != nil {
+			return nil, err
+		}
+		scenario := &types.APIScenario{}
+		if err = yaml.Unmarshal(b, scenario); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal scenario %s due to %w", keyData.Name, err)
+		}
+		if scenario.StateMachine == nil {
+			continue
+		}
+		if contractReq.MatchResponseCode > 0 {
+			keyData.Response = types.APIResponseKey{StatusCode: contractReq.MatchResponseCode}
+		}
+		model.keys[scenario.Name] = keyData
+		model.scenarios = append(model.scenarios, scenario)
+	}
+	if len(model.scenarios) == 0 {
+		return nil, fmt.Errorf("no scenario of group %s has a state machine", group)
+	}
+	sort.Slice(model.scenarios, func(i, j int) bool {
+		return model.scenarios[i].Name < model.scenarios[j].Name
+	})
+	return model, nil
+}
+
+// groupInvariants returns invariants of group config
+func (px *ProducerExecutor) groupInvariants(group string) []string {
+	if px.groupConfigRepository == nil {
+		return nil
+	}
+	if gc, err := px.groupConfigRepository.Load(group); err == nil && gc != nil {
+		return gc.Invariants
+	}
+	return nil
+}
+
+// stateModel is the state machine of a group's scenarios. A scenario can be called in its initial state and
+// in the from states of its transitions, where transitions without from state fire in any state.
+type stateModel struct {
+	scenarios []*types.APIScenario
+	keys      map[string]*types.APIKeyData
+}
+
+// enabled returns scenarios that can be called in the state
+func (m *stateModel) enabled(state string) []*types.APIScenario {
+	res := make([]*types.APIScenario, 0)
+	for _, scenario := range m.scenarios {
+		if m.canCall(state, scenario) {
+			res = append(res, scenario)
+		}
+	}
+	return res
+}
+
+func (m *stateModel) canCall(state string, scenario *types.APIScenario) bool {
+	if scenario.StateMachine.InitialState == state {
+		return true
+	}
+	for _, t := range scenario.StateMachine.Transitions {
+		if t.From == "" || t.From == state {
+			return true
+		}
+	}
+	return false
+}
+
+// transition returns transition of the scenario that fires in the state for its expected response
+func (m *stateModel) transition(state string, scenario *types.APIScenario) *types.StateTransition {
+	for _, t := range scenario.StateMachine.Transitions {
+		methodMatch := t.OnMethod == "" || strings.EqualFold(t.OnMethod, string(scenario.Method))
+		statusMatch := t.OnStatus == 0 || t.OnStatus == scenario.Response.StatusCode
+		if (t.From == "" || t.From == state) && methodMatch && statusMatch {
+			return &t
+		}
+	}
+	return nil
+}
+
+// next returns state after calling the scenario in the state
+func (m *stateModel) next(state string, scenario *types.APIScenario) string {
+	if t := m.transition(state, scenario); t != nil && t.To != "" {
+		return t.To
+	}
+	return state
+}
+
+// generate returns a random sequence of scenarios that can be called one after another from the state
+func (m *stateModel) generate(rnd *rand.Rand, state string, maxSteps int) []*types.APIScenario {
+	sequence := make([]*types.APIScenario, 0, maxSteps)
+	for len(sequence) < maxSteps {
+		candidates := m.enabled(state)
+		if len(candidates) == 0 {
+			break
+		}
+		scenario := candidates[rnd.Intn(len(candidates))]
+		sequence = append(sequence, scenario)
+		state = m.next(state, scenario)
+	}
+	return sequence
+}
+
+// valid returns true if each scenario of the sequence can be called in the state reached by earlier calls
+func (m *stateModel) valid(state string, sequence []*types.APIScenario) bool {
+	for _, scenario := range sequence {
+		if !m.canCall(state, scenario) {
+			return false
+		}
+		state = m.next(state, scenario)
+	}
+	return true
+}
+
+// modelRunner executes sequences of the model against the producer
+type modelRunner struct {
+	px           *ProducerExecutor
+	req          *http.Request
+	model        *stateModel
+	initialState string
+	invariants   []string
+	contractReq  *types.ProducerContractRequest
+	dataTemplate fuzz.DataTemplateRequest
+	sli          *metrics.Metrics
+}
+
+// run executes the sequence in a new session of variables and returns executed steps and the first failure.
+// Results are added to contractResponse as scenario name with step number starting from offset.
+func (r *modelRunner) run(
+	ctx context.Context,
+	sequence []*types.APIScenario,
+	contractResponse *types.ProducerContractResponse,
+	offset int,
+	visited map[string]bool,
+) (int, error) {
+	state := r.initialState
+	variables := make(map[string]any)
+	for i, template := range sequence {
+		key := fmt.Sprintf("%s_%d", template.Name, offset+i)
+		stepReq := r.contractReq.Clone()
+		for k, v := range variables {
+			stepReq.Params[k] = v
+		}
+		scenario, err := r.px.scenarioRepository.Lookup(r.model.keys[template.Name], stepReq.Overrides())
+		if err != nil {
+			err = fmt.Errorf("failed to lookup %s in state '%s' due to %w", template.Name, state, err)
+			contractResponse.Add(key, nil, err)
+			return i + 1, &shrink.StepFailure{Step: i, Scenario: template.Name, Err: err}
+		}
+		scenario.Response.Assertions = append(append([]string{}, scenario.Response.Assertions...), r.invariants...)
+		resContents, err := r.px.executeWithRetry(ctx, r.req, key, scenario, stepReq, contractResponse,
+			r.dataTemplate, r.sli, nil)
+		if err != nil {
+			return i + 1, &shrink.StepFailure{Step: i, Scenario: template.Name,
+				Err: fmt.Errorf("step %d %s in state '%s' failed: %w", i+1, template.Name, state, err)}
+		}
+		if t := r.model.transition(state, template); t != nil && t.ExtractKey != "" {
+			if val := fuzz.ExtractJSONPath(t.ExtractKey, resContents); val != nil {
+				variables[strings.TrimPrefix(t.ExtractKey, "$.")] = val
+			}
+		}
+		state = r.model.next(state, template)
+		if visited != nil {
+			visited[state] = true
+		}
+	}
+	return len(sequence), nil
+}
+
+// TestSequence implements shrink.SequenceFailureDetector; sequences that are not valid for the model pass
+func (r *modelRunner) TestSequence(ctx context.Context, sequence []*types.APIScenario) error {
+	if !r.model.valid(r.initialState, sequence) {
+		return nil
+	}
+	_, err := r.run(ctx, sequence, types.NewProducerContractResponse(), 0, nil)
+	return err
+}
+
+// shrink finds the minimal sequence that still fails
+func (r *modelRunner) shrink(ctx context.Context, sequence []*types.APIScenario, err error) *types.ModelFailure {
+	failure := &types.ModelFailure{
+		Sequence: scenarioNames(sequence),
+		Minimal:  scenarioNames(sequence),
+		Error:    err.Error(),
+	}
+	res, shrinkErr := shrink.ShrinkSequence(ctx, r, sequence, shrink.ShrinkOptions{})
+	if shrinkErr != nil || res.Err == nil {
+		// failure could not be reproduced
+		return failure
+	}
+	failure.Minimal = scenarioNames(res.Minimal)
+	failure.Error = res.Err.Error()
+	failure.ShrinkAttempts = res.Attempts
+	return failure
+}
+
+func scenarioNames(scenarios []*types.APIScenario) []string {
+	names := make([]string, len(scenarios))
+	for i, scenario := range scenarios {
+		names[i] = scenario.Name
+	}
+	return names
+}
@@ -0,0 +1,235 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/fuzz"
+	"github.com/bhatti/api-mock-service/internal/repository"
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/bhatti/api-mock-service/internal/web"
+	"github.com/stretchr/testify/require"
+)
+
+// newOrderModelServer returns a producer of orders; with softDelete, deleted orders are still returned
+func newOrderModelServer(softDelete bool) *httptest.Server {
+	var lock sync.Mutex
+	orders := make(map[string]string)
+	next := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		id := strings.TrimPrefix(r.URL.Path, "/orders/")
+		switch {
+		case r.URL.Path == "/orders" && r.Method == http.MethodPost:
+			next++
+			id = fmt.Sprintf("%d", next)
+			orders[id] = "created"
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"id": "%s", "status": "created"}`, id)))
+		case r.URL.Path == "/orders":
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"count": %d}`, len(orders))))
+		case r.Method == http.MethodDelete && orders[id] != "":
+			if softDelete {
+				orders[id] = "deleted"
+			} else {
+				delete(orders, id)
+			}
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodGet && orders[id] != "":
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"id": "%s", "status": "%s"}`, id, orders[id])))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "not found"}`))
+		}
+	}))
+}
+
+// saveOrderModelScenarios saves scenarios of an order life cycle with state machines
+func saveOrderModelScenarios(t *testing.T, scenarioRepository repository.APIScenarioRepository, group string) {
+	for _, scenario := range []*types.APIScenario{
+		newModelTestScenario(group, "create_order", types.Post, "/orders", 201, "",
+			types.StateTransition{To: "created", ExtractKey: "$.id"}),
+		newModelTestScenario(group, "get_order", types.Get, "/orders/{id}", 200, "created",
+			types.StateTransition{From: "created", To: "created"}),
+		newModelTestScenario(group, "delete_order", types.Delete, "/orders/{id}", 200, "created",
+			types.StateTransition{From: "created", To: "deleted"}),
+		newModelTestScenario(group, "get_deleted_order", types.Get, "/orders/{id}", 404, "deleted",
+			types.StateTransition{From: "deleted", To: "deleted"}),
+		newModelTestScenario(group, "list_orders", types.Get, "/orders", 200, ""),
+	} {
+		require.NoError(t, scenarioRepository.Save(scenario))
+	}
+}
+
+func newModelTestScenario(group string, name string, method types.MethodType, path string, status int,
+	initialState string, transitions ...types.StateTransition) *types.APIScenario {
+	scenario := newParallelTestScenario(group, path, 0)
+	scenario.Name = name
+	scenario.Method = method
+	scenario.Response.StatusCode = status
+	scenario.StateMachine = &types.ScenarioStateMachine{InitialState: initialState, Transitions: transitions}
+	return scenario
+}
+
+func Test_ShouldExecuteModelSequencesFromStateMachine(t *testing.T) {
+	// GIVEN a producer and scenarios of a group with state machines
+	server := newOrderModelServer(false)
+	defer server.Close()
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	group := fmt.Sprintf("model_group_%d", time.Now().UnixNano())
+	saveOrderModelScenarios(t, scenarioRepository, group)
+	// AND invariants of the group
+	require.NoError(t, groupConfigRepository.Save(group, &types.GroupConfig{
+		Invariants: []string{`{{if .contents.status}}{{HasProperty "contents.id"}}{{else}}true{{end}}`}}))
+	executor := NewProducerExecutor(scenarioRepository, groupConfigRepository,
+		web.NewHTTPClient(config, web.NewAuthAdapter(config)))
+
+	// WHEN executing the group in model mode
+	contractReq := types.NewProducerContractRequest(server.URL, 1, 0)
+	contractReq.Model = &types.ModelConfig{Sequences: 10, MaxSteps: 8, Seed: 7}
+	res := executor.ExecuteByGroup(context.Background(), &http.Request{}, group,
+		fuzz.NewDataTemplateRequest(false, 1, 2), contractReq)
+
+	// THEN all sequences should pass and visit states of the model
+	require.NotNil(t, res.Model)
+	require.True(t, res.Model.Passed(), res.Errors)
+	require.Equal(t, int64(7), res.Model.Seed)
+	require.Equal(t, 10, res.Model.Sequences)
+	require.Equal(t, 80, res.Model.Steps)
+	require.Equal(t, 80, res.Succeeded)
+	require.Equal(t, []string{"created", "deleted"}, res.Model.StatesVisited)
+	require.Empty(t, res.Model.UnvisitedStates)
+
+	// AND same seed should generate same sequences
+	again := executor.ExecuteByGroup(context.Background(), &http.Request{}, group,
+		fuzz.NewDataTemplateRequest(false, 1, 2), contractReq)
+	require.Equal(t, len(res.Results), len(again.Results))
+	for key := range res.Results {
+		require.Contains(t, again.Results, key)
+	}
+}
+
+func Test_ShouldShrinkFailingModelSequence(t *testing.T) {
+	// GIVEN a producer that still returns deleted orders
+	server := newOrderModelServer(true)
+	defer server.Close()
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	group := fmt.Sprintf("model_group_%d", time.Now().UnixNano())
+	saveOrderModelScenarios(t, scenarioRepository, group)
+	executor := NewProducerExecutor(scenarioRepository, groupConfigRepository,
+		web.NewHTTPClient(config, web.NewAuthAdapter(config)))
+
+	// WHEN executing long sequences in model mode
+	contractReq := types.NewProducerContractRequest(server.URL, 1, 0)
+	contractReq.Model = &types.ModelConfig{Sequences: 50, MaxSteps: 12, Seed: 42}
+	res := executor.ExecuteModelByGroup(context.Background(), &http.Request{}, group,
+		fuzz.NewDataTemplateRequest(false, 1, 2), contractReq)
+
+	// THEN failing sequence should be shrunk to create, delete and get of the deleted order
+	require.NotNil(t, res.Model.Failure)
+	require.Equal(t, []string{"create_order", "delete_order", "get_deleted_order"}, res.Model.Failure.Minimal)
+	require.GreaterOrEqual(t, len(res.Model.Failure.Sequence), 3)
+	require.Contains(t, res.Model.Failure.Error, "get_deleted_order")
+	require.Contains(t, res.Model.Failure.Error, "deleted")
+	require.Equal(t, 1, res.Failed)
+}
+
+func Test_ShouldNotExecuteModelWithoutStateMachines(t *testing.T) {
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	group := fmt.Sprintf("model_group_%d", time.Now().UnixNano())
+	require.NoError(t, scenarioRepository.Save(newParallelTestScenario(group, "/todos", 0)))
+	executor := NewProducerExecutor(scenarioRepository, groupConfigRepository, web.NewStubHTTPClient())
+	contractReq := types.NewProducerContractRequest("http://localhost", 1, 0)
+	contractReq.Model = &types.ModelConfig{}
+	res := executor.ExecuteModelByGroup(context.Background(), &http.Request{}, group,
+		fuzz.NewDataTemplateRequest(false, 1, 2), contractReq)
+	require.Contains(t, res.Errors[group+"_model"], "state machine")
+}
+
+func Test_ShouldFailModelOnGroupInvariant(t *testing.T) {
+	// GIVEN a producer and an invariant of the group that doesn't hold for created orders
+	server := newOrderModelServer(false)
+	defer server.Close()
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	group := fmt.Sprintf("model_group_%d", time.Now().UnixNano())
+	saveOrderModelScenarios(t, scenarioRepository, group)
+	executor := NewProducerExecutor(scenarioRepository, groupConfigRepository,
+		web.NewHTTPClient(config, web.NewAuthAdapter(config)))
+
+	// WHEN executing with an invariant of the request
+	contractReq := types.NewProducerContractRequest(server.URL, 1, 0)
+	contractReq.Model = &types.ModelConfig{Sequences: 5, MaxSteps: 5, Seed: 3,
+		Invariants: []string{`{{if .contents.status}}{{PropertyEquals "contents.status" "pending"}}{{else}}true{{end}}`}}
+	res := executor.ExecuteModelByGroup(context.Background(), &http.Request{}, group,
+		fuzz.NewDataTemplateRequest(false, 1, 2), contractReq)
+
+	// THEN failing sequence should be shrunk to the first call that returns a status
+	require.NotNil(t, res.Model.Failure)
+	require.Equal(t, []string{"create_order"}, res.Model.Failure.Minimal)
+}
+
+func Test_ShouldRetryTransientFailuresOfModelSteps(t *testing.T) {
+	// GIVEN a producer of orders that fails first request with 502
+	server := newOrderModelServer(false)
+	defer server.Close()
+	var lock sync.Mutex
+	requests := 0
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests++
+		n := requests
+		lock.Unlock()
+		if n == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	group := fmt.Sprintf("model_group_%d", time.Now().UnixNano())
+	saveOrderModelScenarios(t, scenarioRepository, group)
+	executor := NewProducerExecutor(scenarioRepository, groupConfigRepository,
+		web.NewHTTPClient(config, web.NewAuthAdapter(config)))
+
+	// WHEN executing the group in model mode with a retry policy
+	contractReq := types.NewProducerContractRequest(proxy.URL, 1, 0)
+	contractReq.Model = &types.ModelConfig{Sequences: 3, MaxSteps: 4, Seed: 7}
+	contractReq.Retry = &types.RetryPolicy{MaxAttempts: 2, BackoffMillis: 1}
+	res := executor.ExecuteModelByGroup(context.Background(), &http.Request{}, group,
+		fuzz.NewDataTemplateRequest(false, 1, 2), contractReq)
+
+	// THEN transient failure of first step should be retried and classified as flaky
+	require.Nil(t, res.Model.Failure)
+	require.True(t, res.Model.Passed(), res.Errors)
+	require.Equal(t, 1, res.Flaky)
+	require.Equal(t, 0, res.Failed)
+}
@@ -0,0 +1,51 @@
+package contract
+
+import (
+	"github.com/bhatti/api-mock-service/internal/types"
+)
+
+// Expected status classes of mutations
+const (
+	// ExpectClientError for malformed input that the producer must reject with a 4xx
+	ExpectClientError = "4xx"
+	// ExpectNoServerError for input that the producer may accept or reject but must not fail with a 5xx
+	ExpectNoServerError = "not-5xx"
+)
+
+// Mutation is a mutated request of a scenario with the status class expected from the producer
+type Mutation struct {
+	// Scenario with mutated request
+	Scenario *types.APIScenario
+	// Kind of mutation such as null or above-maximum
+	Kind string
+	// Field path of the mutated property such as items[0].quantity, empty if all fields are mutated
+	Field string
+	// Value sent for the field, nil for removed fields
+	Value any
+	// Expected status class such as 4xx
+	Expected string
+}
+
+// Accepts returns true if response status matches expected status class of the mutation
+func (m *Mutation) Accepts(status int) bool {
+	switch m.Expected {
+	case ExpectClientError:
+		return status >= 400 && status < 500
+	case ExpectNoServerError:
+		return status > 0 && status < 500
+	default:
+		return status > 0
+	}
+}
+
+// Outcome classifies response status of the mutation as killed, survived or crashed; status is 0 without a response
+func (m *Mutation) Outcome(status int) string {
+	switch {
+	case status == 0 || status >= 500:
+		return types.MutationCrashed
+	case m.Accepts(status):
+		return types.MutationKilled
+	default:
+		return types.MutationSurvived
+	}
+}
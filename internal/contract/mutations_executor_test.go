@@ -2,7 +2,10 @@ package contract
 
 import (
 	"context"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/bhatti/api-mock-service/internal/fuzz"
@@ -154,3 +157,65 @@ func Test_WithOpenAPISpec_ReturnsDistinctCopies(t *testing.T) {
 	// Original's openAPIDoc field must remain nil (not mutated)
 	require.Nil(t, original.openAPIDoc, "original executor must not be modified")
 }
+
+// Test_ExecuteMutationsByGroup_ReportsMutationScore verifies that mutations are classified by their expected
+// status class and that survivors are reported with their payload.
+func Test_ExecuteMutationsByGroup_ReportsMutationScore(t *testing.T) {
+	// GIVEN a producer that accepts any body but crashes on SQL injection
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(string(data), "DROP TABLE") {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"error": "internal"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+	config := types.BuildTestConfig()
+	scenarioRepo, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepo, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	scenario := &types.APIScenario{
+		Method: types.Post,
+		Name:   "mutation-score-scenario",
+		Path:   "/mutation-score",
+		Group:  "mutation-score-group",
+		Request: types.APIRequest{
+			Contents: `{"name":"Alice"}`,
+			Headers:  map[string]string{"Content-Type": "application/json"},
+		},
+		Response: types.APIResponse{StatusCode: 200, Contents: `{"ok": true}`},
+	}
+	require.NoError(t, scenarioRepo.Save(scenario))
+	executor := NewProducerExecutor(scenarioRepo, groupConfigRepo, web.NewHTTPClient(config, web.NewAuthAdapter(config)))
+
+	// WHEN executing mutations
+	res := executor.ExecuteMutationsByGroup(context.Background(), &http.Request{}, "mutation-score-group",
+		fuzz.NewDataTemplateRequest(false, 1, 1), types.NewProducerContractRequest(server.URL, 1, 0))
+
+	// THEN mutations that must be rejected should survive and SQL injection should crash
+	require.NotNil(t, res.Mutations)
+	require.Equal(t, 1, len(res.Mutations.Endpoints))
+	require.Equal(t, "POST /mutation-score", res.Mutations.Endpoints[0].Endpoint)
+	require.Greater(t, res.Mutations.Killed, 0)
+	require.Greater(t, res.Mutations.Survived, 0)
+	require.Equal(t, 1, res.Mutations.Crashed)
+	require.Equal(t, res.Mutations.Total, res.Mutations.Killed+res.Mutations.Survived+res.Mutations.Crashed)
+	require.Less(t, res.Mutations.Score, 100.0)
+	require.Equal(t, "sec-sqli-drop", res.Mutations.Crashes[0].Kind)
+	require.Contains(t, res.Errors[res.Mutations.Crashes[0].Key], "crashed with status 500")
+	var nullSurvivor *types.MutationResult
+	for _, survivor := range res.Mutations.Survivors {
+		require.Equal(t, ExpectClientError, survivor.Expected)
+		require.Equal(t, 200, survivor.Status)
+		if survivor.Kind == "null" {
+			nullSurvivor = survivor
+		}
+	}
+	require.NotNil(t, nullSurvivor)
+	require.Equal(t, `{"name":null}`, nullSurvivor.Payload)
+	require.Contains(t, res.Errors[nullSurvivor.Key], "null mutation of 'name' was accepted with status 200, expected 4xx")
+}
@@ -11,19 +11,31 @@ import (
 // ContractMutator creates variations of a contract to test robustness
 type ContractMutator struct {
 	scenario  *types.APIScenario
-	mutations []*types.APIScenario
+	mutations []*Mutation
 }
 
 // NewContractMutator creates a new mutator
 func NewContractMutator(scenario *types.APIScenario) *ContractMutator {
 	return &ContractMutator{
 		scenario:  scenario,
-		mutations: make([]*types.APIScenario, 0),
+		mutations: make([]*Mutation, 0),
 	}
 }
 
 // GenerateMutations Generate mutations to test boundary conditions and edge cases
 func (m *ContractMutator) GenerateMutations() []*types.APIScenario {
+	mutations := m.Mutations()
+	scenarios := make([]*types.APIScenario, len(mutations))
+	for i, mutation := range mutations {
+		scenarios[i] = mutation.Scenario
+	}
+	return scenarios
+}
+
+// Mutations generates mutations with their expected outcome, e.g. malformed input expects a 4xx and a security
+// payload must not cause a 5xx
+func (m *ContractMutator) Mutations() []*Mutation {
+	m.mutations = make([]*Mutation, 0)
 	// Create a copy with missing optional fields
 	m.createMissingFieldsMutation()
 
@@ -63,7 +75,7 @@ func (m *ContractMutator) createBoundaryValuesMutation() {
 			s := *m.scenario
 			s.Name = s.Name + "-boundary-" + strategy
 			s.Request.Contents = string(newBody)
-			m.mutations = append(m.mutations, &s)
+			m.add(&s, "boundary-"+strategy, "", nil, ExpectNoServerError)
 		}
 	}
 }
@@ -96,7 +108,7 @@ func (m *ContractMutator) createMissingFieldsMutation() {
 
 			if newBody, err := json.Marshal(requestBody); err == nil {
 				scenarioCopy.Request.Contents = string(newBody)
-				m.mutations = append(m.mutations, &scenarioCopy)
+				m.add(&scenarioCopy, "missing-fields", "", nil, ExpectNoServerError)
 			}
 		}
 	}
@@ -106,11 +118,13 @@ func (m *ContractMutator) createMissingFieldsMutation() {
 func (m *ContractMutator) createMalformedDataMutation() {
 	// Create several malformed variations
 	malformations := []struct {
-		name    string
-		mutator func(map[string]interface{}) map[string]interface{}
+		name     string
+		expected string
+		mutator  func(map[string]interface{}) map[string]interface{}
 	}{
 		{
-			name: "overflow-strings",
+			name:     "overflow-strings",
+			expected: ExpectNoServerError,
 			mutator: func(data map[string]interface{}) map[string]interface{} {
 				result := make(map[string]interface{})
 				for k, v := range data {
@@ -128,7 +142,8 @@ func (m *ContractMutator) createMalformedDataMutation() {
 			},
 		},
 		{
-			name: "invalid-types",
+			name:     "invalid-types",
+			expected: ExpectClientError,
 			mutator: func(data map[string]interface{}) map[string]interface{} {
 				result := make(map[string]interface{})
 				for k, v := range data {
@@ -149,7 +164,8 @@ func (m *ContractMutator) createMalformedDataMutation() {
 			},
 		},
 		{
-			name: "special-chars",
+			name:     "special-chars",
+			expected: ExpectNoServerError,
 			mutator: func(data map[string]interface{}) map[string]interface{} {
 				result := make(map[string]interface{})
 				for k, v := range data {
@@ -184,7 +200,7 @@ func (m *ContractMutator) createMalformedDataMutation() {
 					scenarioCopy.Request.Contents = string(newBody)
 					// For malformed data, expect an error response
 					scenarioCopy.Response.StatusCode = 400
-					m.mutations = append(m.mutations, &scenarioCopy)
+					m.add(&scenarioCopy, malformation.name, "", nil, malformation.expected)
 				}
 			}
 		}
@@ -240,7 +256,7 @@ func (m *ContractMutator) createNullFieldMutations() {
 			s.Name = s.Name + "-null-" + field
 			s.Request.Contents = string(newBody)
 			s.Response.StatusCode = 422
-			m.mutations = append(m.mutations, &s)
+			m.add(&s, "null", field, nil, ExpectClientError)
 		}
 	}
 }
@@ -272,7 +288,8 @@ func (m *ContractMutator) createCombinatorialMutations() {
 				s.Name = s.Name + "-combo-" + fields[i] + "-" + fields[j]
 				s.Request.Contents = string(newBody)
 				s.Response.StatusCode = 422
-				m.mutations = append(m.mutations, &s)
+				// both the boundary field and the null field are recorded so survivors point at the pair
+				m.add(&s, "combo", fields[i]+"+"+fields[j], nil, ExpectClientError)
 				count++
 			}
 		}
@@ -311,7 +328,7 @@ func (m *ContractMutator) createFormatSpecificBoundaryMutations() {
 					s.Name = s.Name + "-format-" + formatKey + "-" + mut[1]
 					s.Request.Contents = string(newBody)
 					s.Response.StatusCode = 400
-					m.mutations = append(m.mutations, &s)
+					m.add(&s, "format-"+formatKey, field, mut[0], ExpectClientError)
 				}
 			}
 		}
@@ -350,12 +367,26 @@ func (m *ContractMutator) createSecurityInjectionMutations() {
 				s.Name = s.Name + "-sec-" + p.name + "-" + field
 				s.Request.Contents = string(newBody)
 				s.Response.StatusCode = 400
-				m.mutations = append(m.mutations, &s)
+				m.add(&s, "sec-"+p.name, field, p.payload, ExpectNoServerError)
 			}
 		}
 	}
 }
 
+// add adds mutated scenario with its expected outcome; request assertions of the scenario are removed because
+// mutated requests violate them by design
+func (m *ContractMutator) add(s *types.APIScenario, kind string, field string, value any, expected string) {
+	s.Request.AssertContentsPattern = ""
+	s.Request.Assertions = nil
+	m.mutations = append(m.mutations, &Mutation{
+		Scenario: s,
+		Kind:     kind,
+		Field:    field,
+		Value:    value,
+		Expected: expected,
+	})
+}
+
 // applyBoundaryValuesEnhanced applies min or max boundary values to all numeric/string fields.
 func applyBoundaryValuesEnhanced(obj map[string]interface{}, strategy string) {
 	for key, value := range obj {
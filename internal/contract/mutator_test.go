@@ -166,3 +166,47 @@ func Test_GenerateMutations_EmptyBodyProducesNoMutations(t *testing.T) {
 	// boundary/null/combinatorial/format/security all skip too
 	require.Empty(t, mutations)
 }
+
+func Test_Mutations_HaveExpectedStatusClass(t *testing.T) {
+	mutations := NewContractMutator(baseScenario()).Mutations()
+	require.NotEmpty(t, mutations)
+	for _, m := range mutations {
+		switch {
+		case strings.Contains(m.Scenario.Name, "-sec-"), strings.Contains(m.Scenario.Name, "-boundary-"),
+			strings.Contains(m.Scenario.Name, "-special-chars"):
+			require.Equal(t, ExpectNoServerError, m.Expected, m.Scenario.Name)
+		case strings.Contains(m.Scenario.Name, "-null-"), strings.Contains(m.Scenario.Name, "-invalid-types"),
+			strings.Contains(m.Scenario.Name, "-format-"):
+			require.Equal(t, ExpectClientError, m.Expected, m.Scenario.Name)
+		}
+		require.NotEmpty(t, m.Kind)
+	}
+	// mutations of a field should name the field
+	for _, m := range mutations {
+		if strings.HasSuffix(m.Scenario.Name, "-null-email") {
+			require.Equal(t, "email", m.Field)
+			require.Equal(t, "null", m.Kind)
+		}
+		// combinatorial mutations should name both the boundary and the null field
+		if m.Kind == "combo" {
+			fields := strings.Split(m.Field, "+")
+			require.Len(t, fields, 2, m.Field)
+			require.True(t, strings.HasSuffix(m.Scenario.Name, "-combo-"+fields[0]+"-"+fields[1]), m.Scenario.Name)
+		}
+	}
+}
+
+func Test_MutationOutcome_ByExpectedStatusClass(t *testing.T) {
+	malformed := &Mutation{Expected: ExpectClientError}
+	require.Equal(t, types.MutationKilled, malformed.Outcome(400))
+	require.Equal(t, types.MutationKilled, malformed.Outcome(422))
+	require.Equal(t, types.MutationSurvived, malformed.Outcome(200))
+	require.Equal(t, types.MutationSurvived, malformed.Outcome(302))
+	require.Equal(t, types.MutationCrashed, malformed.Outcome(500))
+	require.Equal(t, types.MutationCrashed, malformed.Outcome(0))
+
+	security := &Mutation{Expected: ExpectNoServerError}
+	require.Equal(t, types.MutationKilled, security.Outcome(200))
+	require.Equal(t, types.MutationKilled, security.Outcome(400))
+	require.Equal(t, types.MutationCrashed, security.Outcome(503))
+}
@@ -0,0 +1,52 @@
+package contract
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/web"
+)
+
+// responseObservationKey is context key of the response observed by observingClient
+type responseObservationKey struct{}
+
+// responseObservation of a request sent to the producer
+type responseObservation struct {
+	sent    bool
+	status  int
+	elapsed time.Duration
+}
+
+// transportFailed returns true if request was sent without receiving a response
+func (obs *responseObservation) transportFailed() bool {
+	return obs.sent && obs.status == 0
+}
+
+// observingClient records status and latency of responses for load tests and retries
+type observingClient struct {
+	web.HTTPClient
+}
+
+// Handle invokes the wrapped client and records the response in the observation of the context
+func (oc *observingClient) Handle(
+	ctx context.Context,
+	url string,
+	method string,
+	headers http.Header,
+	params map[string]string,
+	body io.ReadCloser,
+) (int, string, io.ReadCloser, http.Header, error) {
+	started := time.Now()
+	status, httpVersion, resBody, resHeaders, err := oc.HTTPClient.Handle(ctx, url, method, headers, params, body)
+	if obs, ok := ctx.Value(responseObservationKey{}).(*responseObservation); ok {
+		obs.sent = true
+		obs.status = status
+		obs.elapsed = time.Since(started)
+		if err != nil {
+			obs.status = 0
+		}
+	}
+	return status, httpVersion, resBody, resHeaders, err
+}
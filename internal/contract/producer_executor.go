@@ -35,6 +35,8 @@ type ProducerExecutor struct {
 	openAPIRouter         routers.Router
 	// coverageCache stores the last coverage result per group, keyed by group name.
 	coverageCache map[string]*types.CoverageSummary
+	// runRepository stores runs with record_results
+	runRepository repository.ContractRunRepository
 }
 
 // WithOpenAPISpec returns a new ProducerExecutor with the OpenAPI document and router attached
@@ -47,10 +49,25 @@ func (px *ProducerExecutor) WithOpenAPISpec(doc *openapi3.T, router routers.Rout
 		openAPIDoc:            doc,
 		openAPIRouter:         router,
 		coverageCache:         px.coverageCache,
+		runRepository:         px.runRepository,
 	}
 	return clone
 }
 
+// WithRunRepository returns a copy of the executor that saves runs with record_results to the repository
+func (px *ProducerExecutor) WithRunRepository(runRepository repository.ContractRunRepository) *ProducerExecutor {
+	clone := *px
+	clone.runRepository = runRepository
+	return &clone
+}
+
+// withClient returns a copy of the executor that sends requests with the given client
+func (px *ProducerExecutor) withClient(client web.HTTPClient) *ProducerExecutor {
+	clone := *px
+	clone.client = client
+	return &clone
+}
+
 // NewProducerExecutor executes contracts for producers
 func NewProducerExecutor(
 	scenarioRepository repository.APIScenarioRepository,
@@ -112,16 +129,12 @@ func (px *ProducerExecutor) Execute(
 			//contractResponse.Metrics = sli.Summary()
 			//return contractResponse
 		}
-		url := scenario.BuildURL(contractReq.BaseURL)
-		resContents, err := px.execute(ctx, req, url, scenario, contractReq, contractResponse, dataTemplate, sli)
-		contractResponse.Add(scenario.Name, resContents, err)
-		if cve, ok := err.(*ContractValidationError); ok && cve.DiffReport != nil {
-			contractResponse.SetErrorDetail(scenario.Name, buildContractValidationDetail(cve))
-		}
+		px.executeWithRetry(ctx, req, scenario.Name, scenario, contractReq, contractResponse, dataTemplate, sli, nil)
 		time.Sleep(scenario.WaitBeforeReply)
 	}
 
 	contractResponse.Metrics = sli.Summary()
+	px.recordRun(scenarioKey.Group, types.RunKindContract, started, contractReq, contractResponse)
 	elapsed := time.Since(started).String()
 	if contractReq.Verbose {
 		log.WithFields(log.Fields{
@@ -170,13 +183,8 @@ func (px *ProducerExecutor) ExecuteByHistory(
 				if !registered[scenario.SafeName()] {
 					sli.RegisterHistogram(scenario.SafeName())
 				}
-				url := scenario.BuildURL(contractReq.BaseURL)
-				resContents, err := px.execute(ctx, req, url, scenario, contractReq, contractResponse, dataTemplate, sli)
 				key := fmt.Sprintf("%s_%d", scenario.Name, i)
-				contractResponse.Add(key, resContents, err)
-				if cve, ok := err.(*ContractValidationError); ok && cve.DiffReport != nil {
-					contractResponse.SetErrorDetail(key, buildContractValidationDetail(cve))
-				}
+				px.executeWithRetry(ctx, req, key, scenario, contractReq, contractResponse, dataTemplate, sli, nil)
 				time.Sleep(scenario.WaitBeforeReply)
 			}
 		}
@@ -184,6 +192,7 @@ func (px *ProducerExecutor) ExecuteByHistory(
 
 	elapsed := time.Since(started).String()
 	contractResponse.Metrics = sli.Summary()
+	px.recordRun(group, types.RunKindContract, started, contractReq, contractResponse)
 	log.WithFields(log.Fields{
 		"Component":               "ProducerExecutor",
 		"Group":                   group,
@@ -231,40 +240,58 @@ func (px *ProducerExecutor) ExecuteByGroup(
 		return contractResponse
 	}
 
+	if contractReq.Load != nil {
+		return px.ExecuteLoadByGroup(ctx, req, group, dataTemplate, contractReq)
+	}
+
+	if contractReq.Model != nil {
+		return px.ExecuteModelByGroup(ctx, req, group, dataTemplate, contractReq)
+	}
+
 	sli := metrics.NewMetrics()
 	for _, scenarioKey := range scenarioKeys {
 		sli.RegisterHistogram(scenarioKey.SafeName())
 	}
 
-	for i := 0; i < contractReq.ExecutionTimes; i++ {
+	if contractReq.MatchResponseCode > 0 {
 		for _, scenarioKey := range scenarioKeys {
-			if contractReq.MatchResponseCode > 0 {
-				scenarioKey.Response = types.APIResponseKey{StatusCode: contractReq.MatchResponseCode}
-			}
-			scenario, err := px.scenarioRepository.Lookup(scenarioKey, contractReq.Overrides())
-			if err != nil {
-				log.WithFields(log.Fields{
-					"Component":               "ProducerExecutor",
-					"Group":                   group,
-					"ProducerContractRequest": contractReq.String(),
-					"ScenarioKey":             scenarioKey.String(),
-					"Error":                   err,
-				}).Warnf("failed to lookup")
-				contractResponse.Mismatched++
-				continue
-				//contractResponse.Add(fmt.Sprintf("%s_%d", scenarioKey.Name, i), nil,
-				//	fmt.Errorf("scenario %s failed: %s", scenarioKey.String(), err))
-				//contractResponse.Metrics = sli.Summary()
-				//return contractResponse
+			scenarioKey.Response = types.APIResponseKey{StatusCode: contractReq.MatchResponseCode}
+		}
+	}
+
+	limiter := newRateLimiter(contractReq.MaxRPS)
+	run := func(scenarioKey *types.APIKeyData, i int, contractReq *types.ProducerContractRequest) {
+		if limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				contractResponse.Add(fmt.Sprintf("%s_%d", scenarioKey.Name, i), nil, err)
+				return
 			}
-			url := scenario.BuildURL(contractReq.BaseURL)
-			resContents, err := px.execute(ctx, req, url, scenario, contractReq, contractResponse, dataTemplate, sli)
-			key := fmt.Sprintf("%s_%d", scenarioKey.Name, i)
-			contractResponse.Add(key, resContents, err)
-			if cve, ok := err.(*ContractValidationError); ok && cve.DiffReport != nil {
-				contractResponse.SetErrorDetail(key, buildContractValidationDetail(cve))
+		}
+		scenario, err := px.scenarioRepository.Lookup(scenarioKey, contractReq.Overrides())
+		if err != nil {
+			log.WithFields(log.Fields{
+				"Component":               "ProducerExecutor",
+				"Group":                   group,
+				"ProducerContractRequest": contractReq.String(),
+				"ScenarioKey":             scenarioKey.String(),
+				"Error":                   err,
+			}).Warnf("failed to lookup")
+			contractResponse.AddMismatched()
+			return
+		}
+		key := fmt.Sprintf("%s_%d", scenarioKey.Name, i)
+		px.executeWithRetry(ctx, req, key, scenario, contractReq, contractResponse, dataTemplate, sli, nil)
+		time.Sleep(scenario.WaitBeforeReply)
+	}
+
+	if contractReq.Concurrency > 1 {
+		executeConcurrently(ctx, buildExecutionLanes(scenarioKeys), contractReq.Concurrency,
+			contractReq.ExecutionTimes, contractReq, run)
+	} else {
+		for i := 0; i < contractReq.ExecutionTimes; i++ {
+			for _, scenarioKey := range scenarioKeys {
+				run(scenarioKey, i, contractReq)
 			}
-			time.Sleep(scenario.WaitBeforeReply)
 		}
 	}
 
@@ -282,6 +309,7 @@ func (px *ProducerExecutor) ExecuteByGroup(
 
 	elapsed := time.Since(started).String()
 	contractResponse.Metrics = sli.Summary()
+	px.recordRun(group, types.RunKindContract, started, contractReq, contractResponse)
 	log.WithFields(log.Fields{
 		"Component":               "ProducerExecutor",
 		"Group":                   group,
@@ -362,10 +390,10 @@ func (px *ProducerExecutor) execute(
 	}).Debugf("before execute")
 
 	statusCode, httpVersion, resBody, resHeaders, err := px.client.Handle(
-		ctx, url, string(scenario.Method), reqHeaders, queryParams, reqBody)
+		web.WithHostOverrides(ctx, contractReq.HostOverrides), url, string(scenario.Method), reqHeaders, queryParams, reqBody)
 	elapsed := time.Now().UnixMilli() - started
 	sli.AddHistogram(scenario.SafeName(), float64(elapsed)/1000.0, nil)
-	contractRes.URLs[url] = contractRes.URLs[url] + 1
+	contractRes.AddURL(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to invoke %s for %s (%s) due to %w", scenario.Name, url, scenario.Method, err)
 	}
@@ -527,10 +555,7 @@ func (px *ProducerExecutor) execute(
 			// You could add the coverage data to the scenario's metadata or
 			// incorporate it into your existing history mechanism
 			// For example, store it in contractReq.Results:
-			if contractRes.Results == nil {
-				contractRes.Results = make(map[string]any)
-			}
-			contractRes.Results[scenario.Name+"_coverage"] = coverage
+			contractRes.AddResult(scenario.Name+"_coverage", coverage)
 		}
 	}
 	return resContents, err
@@ -671,6 +696,78 @@ func (px *ProducerExecutor) GetContractStats(scenarioName string) (*ContractVali
 	return stats, nil
 }
 
+// GetContractRunStats analyzes recorded runs of the group (all groups if empty) for a scenario
+func (px *ProducerExecutor) GetContractRunStats(group string, scenarioName string, limit int) (*ContractValidationStats, error) {
+	runs, err := px.ListRuns(group, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load runs for %s: %w", scenarioName, err)
+	}
+	return BuildContractStats(scenarioName, runs), nil
+}
+
+// ListRuns returns most recent recorded runs of the group (all groups if empty)
+func (px *ProducerExecutor) ListRuns(group string, limit int) ([]*types.ContractRun, error) {
+	if px.runRepository == nil {
+		return nil, fmt.Errorf("contract run history is not enabled")
+	}
+	return px.runRepository.List(group, limit)
+}
+
+// LoadRun returns recorded run by id
+func (px *ProducerExecutor) LoadRun(id string) (*types.ContractRun, error) {
+	if px.runRepository == nil {
+		return nil, fmt.Errorf("contract run history is not enabled")
+	}
+	return px.runRepository.Load(id)
+}
+
+// CompareRuns compares recorded head run against base run
+func (px *ProducerExecutor) CompareRuns(baseID string, headID string, latencyThresholdPercent float64) (*types.ContractRunComparison, error) {
+	base, err := px.LoadRun(baseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load base run %s: %w", baseID, err)
+	}
+	head, err := px.LoadRun(headID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load head run %s: %w", headID, err)
+	}
+	return CompareContractRuns(base, head, latencyThresholdPercent), nil
+}
+
+// recordRun saves outcome of the run if record_results is set and sets its id in the response
+func (px *ProducerExecutor) recordRun(
+	group string,
+	kind string,
+	started time.Time,
+	contractReq *types.ProducerContractRequest,
+	contractResponse *types.ProducerContractResponse) {
+	if !contractReq.RecordResults {
+		return
+	}
+	if px.runRepository == nil {
+		log.WithFields(log.Fields{
+			"Component": "ProducerExecutor",
+			"Group":     group,
+		}).Warnf("record_results is set but contract run history is not enabled")
+		return
+	}
+	run := NewContractRun(group, kind, started, contractReq, contractResponse)
+	if run.Label != "" {
+		if previous, err := px.runRepository.List(group, 0); err == nil {
+			markFlakyOnRetry(run, previous)
+		}
+	}
+	if err := px.runRepository.Save(run); err != nil {
+		log.WithFields(log.Fields{
+			"Component": "ProducerExecutor",
+			"Group":     group,
+			"Error":     err,
+		}).Warnf("failed to record contract run")
+		return
+	}
+	contractResponse.RunID = run.ID
+}
+
 func handleSharedVariables(scenario *types.APIScenario, resContents any,
 	params map[string]any, groupVariables map[string]string,
 	sharedVariables map[string]any, resHeaders http.Header) any {
@@ -916,6 +1013,8 @@ func coverageReportToSummary(r *CoverageReport, results map[string]any) *types.C
 // ExecuteMutationsByGroup looks up all scenarios for a group, generates mutations for each,
 // and executes them. Returns a ProducerContractResponse aggregating all mutation results.
 // Mutations test robustness: null fields, boundary values, format violations, security payloads.
+// Each mutation is killed when the producer responds with its expected status class, survived when it
+// doesn't and crashed on a 5xx; the response adds mutation score of each endpoint.
 func (px *ProducerExecutor) ExecuteMutationsByGroup(
 	ctx context.Context,
 	req *http.Request,
@@ -923,9 +1022,11 @@ func (px *ProducerExecutor) ExecuteMutationsByGroup(
 	dataTemplate fuzz.DataTemplateRequest,
 	contractReq *types.ProducerContractRequest,
 ) *types.ProducerContractResponse {
+	started := time.Now()
 	scenarioKeys := px.scenarioRepository.LookupAllByGroup(group)
 	contractResponse := types.NewProducerContractResponse()
 	sli := metrics.NewMetrics()
+	results := make([]*types.MutationResult, 0)
 
 	log.WithFields(log.Fields{
 		"Component": "ProducerExecutor",
@@ -945,31 +1046,91 @@ func (px *ProducerExecutor) ExecuteMutationsByGroup(
 		}
 		sli.RegisterHistogram(scenario.SafeName())
 
-		mutator := NewContractMutator(scenario)
-		mutations := mutator.GenerateMutations()
+		mutations := NewContractMutator(scenario).Mutations()
+		// mutations from request schema of the spec
+		if px.openAPIDoc != nil {
+			mutations = append(mutations, NewSchemaMutator(px.openAPIDoc).GenerateMutations(scenario)...)
+		}
 		log.WithFields(log.Fields{
 			"Scenario":  scenario.Name,
 			"Mutations": len(mutations),
 		}).Debugf("generated mutations")
 
-		for i, mutatedScenario := range mutations {
-			url := mutatedScenario.BuildURL(contractReq.BaseURL)
-			resContents, err := px.execute(ctx, req, url, mutatedScenario, contractReq, contractResponse, dataTemplate, sli)
-			key := fmt.Sprintf("%s_%d", mutatedScenario.Name, i)
-			contractResponse.Add(key, resContents, err)
-			if cve, ok := err.(*ContractValidationError); ok && cve.DiffReport != nil {
-				contractResponse.SetErrorDetail(key, buildContractValidationDetail(cve))
+		for i, mutation := range mutations {
+			key := fmt.Sprintf("%s_%d", mutation.Scenario.Name, i)
+			if res := px.executeMutation(ctx, req, key, mutation, contractReq, contractResponse, dataTemplate, sli); res != nil {
+				res.Endpoint = fmt.Sprintf("%s %s", scenario.Method, scenario.Path)
+				results = append(results, res)
 			}
 		}
 	}
 
 	contractResponse.Metrics = sli.Summary()
+	contractResponse.Mutations = types.NewMutationReport(results)
+	px.recordRun(group, types.RunKindMutations, started, contractReq, contractResponse)
 	log.WithFields(log.Fields{
 		"Component": "ProducerExecutor",
 		"Group":     group,
 		"Errors":    len(contractResponse.Errors),
 		"Succeeded": contractResponse.Succeeded,
 		"Failed":    contractResponse.Failed,
+		"Score":     contractResponse.Mutations.Score,
+		"Survived":  contractResponse.Mutations.Survived,
+		"Crashed":   contractResponse.Mutations.Crashed,
 	}).Infof("execute-mutations-by-group COMPLETED")
 	return contractResponse
 }
+
+// executeMutation executes the mutation and fails it unless the response status matches its expected status
+// class, e.g. a 2xx response to malformed input is a missing validation of the producer. It returns nil if the
+// request couldn't be sent.
+func (px *ProducerExecutor) executeMutation(
+	ctx context.Context,
+	req *http.Request,
+	key string,
+	mutation *Mutation,
+	contractReq *types.ProducerContractRequest,
+	contractResponse *types.ProducerContractResponse,
+	dataTemplate fuzz.DataTemplateRequest,
+	sli *metrics.Metrics,
+) *types.MutationResult {
+	obs := &responseObservation{}
+	exec := px.withClient(&observingClient{HTTPClient: px.client})
+	url := mutation.Scenario.BuildURL(contractReq.BaseURL)
+	executed := time.Now()
+	resContents, err := exec.execute(context.WithValue(ctx, responseObservationKey{}, obs),
+		req, url, mutation.Scenario, contractReq, contractResponse, dataTemplate, sli)
+	contractResponse.AddLatency(key, time.Since(executed).Milliseconds())
+	if !obs.sent {
+		contractResponse.Add(key, resContents, err)
+		return nil
+	}
+	outcome := mutation.Outcome(obs.status)
+	subject := mutation.Kind + " mutation"
+	if mutation.Field != "" {
+		subject = fmt.Sprintf("%s of '%s'", subject, mutation.Field)
+	}
+	switch {
+	case outcome == types.MutationKilled:
+		err = nil
+	case obs.status == 0:
+		err = fmt.Errorf("%s crashed without a response, expected %s: %v", subject, mutation.Expected, err)
+	case outcome == types.MutationCrashed:
+		err = fmt.Errorf("%s crashed with status %d, expected %s", subject, obs.status, mutation.Expected)
+	case obs.status < 300:
+		err = fmt.Errorf("%s was accepted with status %d, expected %s", subject, obs.status, mutation.Expected)
+	default:
+		err = fmt.Errorf("%s survived with status %d, expected %s", subject, obs.status, mutation.Expected)
+	}
+	contractResponse.Add(key, resContents, err)
+	return &types.MutationResult{
+		Key:      key,
+		Scenario: mutation.Scenario.Name,
+		Kind:     mutation.Kind,
+		Field:    mutation.Field,
+		Expected: mutation.Expected,
+		Status:   obs.status,
+		Outcome:  outcome,
+		Payload:  mutation.Scenario.Request.Contents,
+	}
+}
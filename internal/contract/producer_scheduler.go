@@ -0,0 +1,112 @@
+package contract
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	"golang.org/x/time/rate"
+)
+
+// executionLane is a list of scenarios that must be executed sequentially in the given order
+type executionLane struct {
+	keys []*types.APIKeyData
+	// ordered lanes run all iterations in a single job so that shared variables flow between scenarios
+	ordered bool
+}
+
+// executionJob executes scenarios of a lane for an iteration or for all iterations of an ordered lane
+type executionJob struct {
+	lane      *executionLane
+	iteration int
+}
+
+// newRateLimiter returns limiter for max requests per second or nil when it's not limited
+func newRateLimiter(maxRPS float64) *rate.Limiter {
+	if maxRPS <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(maxRPS), 1)
+}
+
+// buildExecutionLanes partitions scenarios of a group into lanes. Scenarios with explicit order, chained
+// via next_request, sharing variables or referencing shared variables in request templates are kept in
+// a single ordered lane and all other scenarios are independent and get their own lane.
+func buildExecutionLanes(scenarioKeys []*types.APIKeyData) (lanes []*executionLane) {
+	chained := make(map[string]bool)
+	shared := make(map[string]bool)
+	for _, key := range scenarioKeys {
+		if key.NextRequest != "" {
+			chained[key.NextRequest] = true
+		}
+		for _, name := range key.SharedVariables {
+			shared[name] = true
+		}
+	}
+	ordered := &executionLane{ordered: true}
+	for _, key := range scenarioKeys {
+		if key.Order != 0 || key.NextRequest != "" || key.SharesVariables || chained[key.Name] ||
+			key.UsesAnyVariable(shared) {
+			ordered.keys = append(ordered.keys, key)
+		} else {
+			lanes = append(lanes, &executionLane{keys: []*types.APIKeyData{key}})
+		}
+	}
+	if len(ordered.keys) > 0 {
+		sort.SliceStable(ordered.keys, func(i, j int) bool {
+			return ordered.keys[i].Order < ordered.keys[j].Order
+		})
+		lanes = append([]*executionLane{ordered}, lanes...)
+	}
+	return
+}
+
+// executeConcurrently runs lanes in a bounded pool of workers
+func executeConcurrently(
+	ctx context.Context,
+	lanes []*executionLane,
+	concurrency int,
+	executionTimes int,
+	contractReq *types.ProducerContractRequest,
+	run func(key *types.APIKeyData, iteration int, contractReq *types.ProducerContractRequest),
+) {
+	// independent scenarios start from a snapshot of the params as the ordered lane updates shared variables
+	independentReq := contractReq.Clone()
+	jobs := make(chan executionJob)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if job.lane.ordered {
+					for i := 0; i < executionTimes; i++ {
+						for _, key := range job.lane.keys {
+							run(key, i, contractReq)
+						}
+					}
+					continue
+				}
+				req := independentReq.Clone()
+				for _, key := range job.lane.keys {
+					run(key, job.iteration, req)
+				}
+			}
+		}()
+	}
+	for _, lane := range lanes {
+		if lane.ordered {
+			jobs <- executionJob{lane: lane}
+			continue
+		}
+		for i := 0; i < executionTimes; i++ {
+			if ctx.Err() != nil {
+				break
+			}
+			jobs <- executionJob{lane: lane, iteration: i}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,137 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/fuzz"
+	"github.com/bhatti/api-mock-service/internal/repository"
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/bhatti/api-mock-service/internal/web"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldExecuteGroupScenariosConcurrently(t *testing.T) {
+	// GIVEN a producer that tracks requests in flight and order of checkout requests
+	var inFlight, maxInFlight int32
+	var lock sync.Mutex
+	var checkout []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		if strings.HasPrefix(r.URL.Path, "/checkout") {
+			lock.Lock()
+			checkout = append(checkout, r.URL.Path)
+			lock.Unlock()
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	// AND independent scenarios with ordered checkout scenarios in the same group
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	group := fmt.Sprintf("parallel_group_%d", time.Now().UnixNano())
+	for i := 0; i < 8; i++ {
+		require.NoError(t, scenarioRepository.Save(newParallelTestScenario(group, fmt.Sprintf("/items/%d", i), 0)))
+	}
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, scenarioRepository.Save(newParallelTestScenario(group, fmt.Sprintf("/checkout/%d", i), i)))
+	}
+	client := web.NewHTTPClient(config, web.NewAuthAdapter(config))
+	executor := NewProducerExecutor(scenarioRepository, groupConfigRepository, client)
+	dataTemplate := fuzz.NewDataTemplateRequest(false, 1, 2)
+
+	// WHEN executing the group with a pool of workers
+	contractReq := types.NewProducerContractRequest(server.URL, 2, 0)
+	contractReq.Concurrency = 4
+	res := executor.ExecuteByGroup(context.Background(), &http.Request{}, group, dataTemplate, contractReq)
+
+	// THEN all scenarios should be executed in parallel with ordered scenarios kept in sequence
+	require.Equal(t, 0, len(res.Errors), fmt.Sprintf("%v", res.Errors))
+	require.Equal(t, 22, len(res.Results))
+	require.Equal(t, 22, res.Succeeded)
+	require.Equal(t, 2, res.URLs[server.URL+"/items/0"])
+	require.True(t, atomic.LoadInt32(&maxInFlight) > 1)
+	require.Equal(t, []string{"/checkout/1", "/checkout/2", "/checkout/3",
+		"/checkout/1", "/checkout/2", "/checkout/3"}, checkout)
+
+	// WHEN executing the group with max requests per second
+	atomic.StoreInt32(&maxInFlight, 0)
+	contractReq = types.NewProducerContractRequest(server.URL, 1, 0)
+	contractReq.Concurrency = 4
+	contractReq.MaxRPS = 20
+	started := time.Now()
+	res = executor.ExecuteByGroup(context.Background(), &http.Request{}, group, dataTemplate, contractReq)
+
+	// THEN requests should be throttled
+	require.Equal(t, 0, len(res.Errors), fmt.Sprintf("%v", res.Errors))
+	require.Equal(t, 11, res.Succeeded)
+	require.True(t, time.Since(started) >= 400*time.Millisecond, time.Since(started).String())
+}
+
+func Test_ShouldBuildExecutionLanes(t *testing.T) {
+	// GIVEN scenario keys with independent, ordered and chained scenarios
+	keys := []*types.APIKeyData{
+		{Name: "list"},
+		{Name: "second", Order: 2},
+		{Name: "create", SharesVariables: true, SharedVariables: []string{"id"}},
+		{Name: "login", NextRequest: "profile"},
+		{Name: "profile"},
+		{Name: "first", Order: 1},
+		{Name: "get", TemplateVariables: []string{"id"}},
+		{Name: "search", TemplateVariables: []string{"query"}},
+	}
+
+	// WHEN building lanes
+	lanes := buildExecutionLanes(keys)
+
+	// THEN dependent scenarios should share an ordered lane
+	require.Len(t, lanes, 3)
+	require.True(t, lanes[0].ordered)
+	var names []string
+	for _, key := range lanes[0].keys {
+		names = append(names, key.Name)
+	}
+	// AND scenarios referencing shared variables should run after their producer
+	require.Equal(t, []string{"create", "login", "profile", "get", "first", "second"}, names)
+	require.Equal(t, "list", lanes[1].keys[0].Name)
+	require.Equal(t, "search", lanes[2].keys[0].Name)
+}
+
+func newParallelTestScenario(group string, path string, order int) *types.APIScenario {
+	return &types.APIScenario{
+		Method:         types.Get,
+		Name:           group + strings.ReplaceAll(path, "/", "_"),
+		Path:           path,
+		Group:          group,
+		Order:          order,
+		Authentication: make(map[string]types.APIAuthorization),
+		Request: types.APIRequest{
+			Headers: map[string]string{"Content-Type": "application/json"},
+		},
+		Response: types.APIResponse{
+			StatusCode: 200,
+			Headers:    map[string][]string{"Content-Type": {"application/json"}},
+			Contents:   `{"id": 1}`,
+		},
+	}
+}
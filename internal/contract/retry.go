@@ -0,0 +1,78 @@
+package contract
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/fuzz"
+	"github.com/bhatti/api-mock-service/internal/metrics"
+	"github.com/bhatti/api-mock-service/internal/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// executeWithRetry executes the scenario up to max attempts of the retry policy and adds its result as key.
+// An execution that fails and then passes on retry is added as flaky instead of succeeded or failed.
+// The optional verify checks contents of a passing execution and its error fails the execution without retry.
+func (px *ProducerExecutor) executeWithRetry(
+	ctx context.Context,
+	req *http.Request,
+	key string,
+	scenario *types.APIScenario,
+	contractReq *types.ProducerContractRequest,
+	contractResponse *types.ProducerContractResponse,
+	dataTemplate fuzz.DataTemplateRequest,
+	sli *metrics.Metrics,
+	verify func(resContents any) error,
+) (any, error) {
+	policy := contractReq.Retry
+	maxAttempts := policy.Attempts()
+	exec := px
+	if maxAttempts > 1 {
+		exec = px.withClient(&observingClient{HTTPClient: px.client})
+	}
+	url := scenario.BuildURL(contractReq.BaseURL)
+	var firstErr error
+	for attempt := 1; ; attempt++ {
+		obs := &responseObservation{}
+		executed := time.Now()
+		resContents, err := exec.execute(context.WithValue(ctx, responseObservationKey{}, obs),
+			req, url, scenario, contractReq, contractResponse, dataTemplate, sli)
+		if err == nil && verify != nil {
+			err = verify(resContents)
+		}
+		contractResponse.AddLatency(key, time.Since(executed).Milliseconds())
+		if err != nil && attempt < maxAttempts && ctx.Err() == nil &&
+			policy.Retryable(obs.status, obs.transportFailed(), err) {
+			if firstErr == nil {
+				firstErr = err
+			}
+			backoff := policy.Backoff(attempt)
+			log.WithFields(log.Fields{
+				"Component": "ProducerExecutor",
+				"Key":       key,
+				"Attempt":   attempt,
+				"Status":    obs.status,
+				"Backoff":   backoff,
+				"Error":     err,
+			}).Warnf("retrying execution")
+			select {
+			case <-ctx.Done():
+			case <-time.After(backoff):
+			}
+			continue
+		}
+		if maxAttempts > 1 {
+			contractResponse.AddAttempts(key, attempt)
+		}
+		if err == nil && firstErr != nil {
+			contractResponse.AddFlaky(key, resContents, firstErr)
+			return resContents, nil
+		}
+		contractResponse.Add(key, resContents, err)
+		if cve, ok := err.(*ContractValidationError); ok && cve.DiffReport != nil {
+			contractResponse.SetErrorDetail(key, buildContractValidationDetail(cve))
+		}
+		return resContents, err
+	}
+}
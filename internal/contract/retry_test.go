@@ -0,0 +1,104 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/fuzz"
+	"github.com/bhatti/api-mock-service/internal/repository"
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/bhatti/api-mock-service/internal/web"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldRetryTransientFailuresAndClassifyFlakyExecutions(t *testing.T) {
+	// GIVEN a producer with a transient 502, a permanent 502 and a non-retryable 500
+	var lock sync.Mutex
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests[r.URL.Path]++
+		n := requests[r.URL.Path]
+		lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/transient" && n == 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case r.URL.Path == "/down":
+			w.WriteHeader(http.StatusBadGateway)
+		case r.URL.Path == "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	// AND scenarios of a group
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	group := fmt.Sprintf("retry_group_%d", time.Now().UnixNano())
+	for _, path := range []string{"/stable", "/transient", "/down", "/error"} {
+		require.NoError(t, scenarioRepository.Save(newParallelTestScenario(group, path, 0)))
+	}
+	client := web.NewHTTPClient(config, web.NewAuthAdapter(config))
+	executor := NewProducerExecutor(scenarioRepository, groupConfigRepository, client)
+	dataTemplate := fuzz.NewDataTemplateRequest(false, 1, 2)
+
+	// WHEN executing the group with a retry policy
+	contractReq := types.NewProducerContractRequest(server.URL, 1, 0)
+	contractReq.Retry = &types.RetryPolicy{MaxAttempts: 3, BackoffMillis: 1}
+	res := executor.ExecuteByGroup(context.Background(), &http.Request{}, group, dataTemplate, contractReq)
+
+	// THEN transient failure should be flaky instead of passed or failed
+	require.Equal(t, 1, res.Succeeded)
+	require.Equal(t, 1, res.Flaky)
+	require.Equal(t, 2, res.Failed)
+	require.Contains(t, res.FlakyErrors[group+"_transient_0"], "502")
+	require.NotContains(t, res.Errors, group+"_transient_0")
+	require.Equal(t, 0.25, res.FlakyRate())
+
+	// AND attempts should be recorded for each execution
+	require.Equal(t, 1, res.Attempts[group+"_stable_0"])
+	require.Equal(t, 2, res.Attempts[group+"_transient_0"])
+	require.Equal(t, 3, res.Attempts[group+"_down_0"])
+	require.Equal(t, 1, res.Attempts[group+"_error_0"])
+	require.Equal(t, 3, requests["/down"])
+	require.Equal(t, 1, requests["/error"])
+}
+
+func Test_ShouldNotRetryWithoutRetryPolicy(t *testing.T) {
+	// GIVEN a producer that always fails with 503
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	group := fmt.Sprintf("no_retry_group_%d", time.Now().UnixNano())
+	require.NoError(t, scenarioRepository.Save(newParallelTestScenario(group, "/unavailable", 0)))
+	client := web.NewHTTPClient(config, web.NewAuthAdapter(config))
+	executor := NewProducerExecutor(scenarioRepository, groupConfigRepository, client)
+
+	// WHEN executing the group without retry policy
+	contractReq := types.NewProducerContractRequest(server.URL, 1, 0)
+	res := executor.ExecuteByGroup(context.Background(), &http.Request{}, group, fuzz.NewDataTemplateRequest(false, 1, 2), contractReq)
+
+	// THEN execution should fail after single attempt
+	require.Equal(t, 1, res.Failed)
+	require.Equal(t, 0, res.Flaky)
+	require.Equal(t, 1, requests)
+	require.Empty(t, res.Attempts)
+}
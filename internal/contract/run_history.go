@@ -0,0 +1,223 @@
+package contract
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/fuzz"
+	"github.com/bhatti/api-mock-service/internal/report"
+	"github.com/bhatti/api-mock-service/internal/types"
+)
+
+// DefaultLatencyRegressionPercent is increase of average latency reported as regression by CompareContractRuns
+const DefaultLatencyRegressionPercent = 20.0
+
+// minLatencyRegressionMillis ignores regressions of fast scenarios within noise
+const minLatencyRegressionMillis = 5.0
+
+// maxRecordedFailures limits failure reasons stored per scenario of a run
+const maxRecordedFailures = 20
+
+// NewContractRun builds run of the group with per-scenario outcome from the contract response
+func NewContractRun(
+	group string,
+	kind string,
+	started time.Time,
+	contractReq *types.ProducerContractRequest,
+	res *types.ProducerContractResponse) *types.ContractRun {
+	run := &types.ContractRun{
+		ID:             fuzz.ULID(),
+		Group:          group,
+		Kind:           kind,
+		Label:          contractReq.RunLabel,
+		Environment:    contractReq.Environment,
+		BaseURL:        contractReq.BaseURL,
+		StartedAt:      started.UTC(),
+		DurationMillis: time.Since(started).Milliseconds(),
+		Succeeded:      res.Succeeded,
+		Failed:         res.Failed,
+		Mismatched:     res.Mismatched,
+		Scenarios:      make(map[string]*types.ScenarioOutcome),
+	}
+	cases := report.BuildTestCases(res)
+	sort.SliceStable(cases, func(i, j int) bool {
+		return cases[i].Iteration < cases[j].Iteration
+	})
+	var totalLatency = make(map[string]int64)
+	for _, tc := range cases {
+		outcome := run.Scenarios[tc.Scenario]
+		if outcome == nil {
+			outcome = &types.ScenarioOutcome{}
+			run.Scenarios[tc.Scenario] = outcome
+		}
+		outcome.Executions++
+		totalLatency[tc.Scenario] += tc.LatencyMillis
+		if tc.Failed() {
+			outcome.Failed++
+			reasons := report.FailureMessages(tc.Detail)
+			if len(reasons) == 0 {
+				reasons = []string{tc.Failure}
+			}
+			for _, reason := range reasons {
+				if len(outcome.Failures) < maxRecordedFailures {
+					outcome.Failures = append(outcome.Failures, reason)
+				}
+			}
+		} else {
+			// passed on retry after an earlier attempt or execution failed
+			if tc.Flaky() || outcome.Failed > 0 {
+				outcome.Flaky = true
+			}
+			outcome.Passed++
+		}
+	}
+	for name, outcome := range run.Scenarios {
+		outcome.LatencyMillis = float64(totalLatency[name]) / float64(outcome.Executions)
+	}
+	return run
+}
+
+// markFlakyOnRetry marks scenarios flaky that failed in the latest earlier run of same group, label and
+// environment but passed in the run
+func markFlakyOnRetry(run *types.ContractRun, previous []*types.ContractRun) {
+	if run.Label == "" {
+		return
+	}
+	for _, prev := range previous {
+		if prev.ID == run.ID || prev.Group != run.Group || prev.Kind != run.Kind || prev.Label != run.Label ||
+			prev.Environment != run.Environment {
+			continue
+		}
+		for name, outcome := range run.Scenarios {
+			if prevOutcome := prev.Scenarios[name]; prevOutcome != nil &&
+				prevOutcome.Failed > 0 && outcome.Failed == 0 {
+				outcome.Flaky = true
+			}
+		}
+		return
+	}
+}
+
+// CompareContractRuns compares head run against base run and reports new failures, fixed scenarios and
+// average latencies that increased by more than latencyThresholdPercent
+func CompareContractRuns(
+	base *types.ContractRun,
+	head *types.ContractRun,
+	latencyThresholdPercent float64) *types.ContractRunComparison {
+	if latencyThresholdPercent <= 0 {
+		latencyThresholdPercent = DefaultLatencyRegressionPercent
+	}
+	comparison := &types.ContractRunComparison{
+		Base:               base.Summary(),
+		Head:               head.Summary(),
+		NewFailures:        make([]string, 0),
+		Fixed:              make([]string, 0),
+		StillFailing:       make([]string, 0),
+		Flaky:              make([]string, 0),
+		Added:              make([]string, 0),
+		Removed:            make([]string, 0),
+		LatencyRegressions: make([]*types.LatencyRegression, 0),
+	}
+	for _, name := range base.ScenarioNames() {
+		if head.Scenarios[name] == nil {
+			comparison.Removed = append(comparison.Removed, name)
+		}
+	}
+	for _, name := range head.ScenarioNames() {
+		headOutcome := head.Scenarios[name]
+		baseOutcome := base.Scenarios[name]
+		headFailed := headOutcome.Status() == types.OutcomeFailed
+		baseFailed := baseOutcome != nil && baseOutcome.Status() == types.OutcomeFailed
+		if baseOutcome == nil {
+			comparison.Added = append(comparison.Added, name)
+		}
+		if headOutcome.Flaky {
+			comparison.Flaky = append(comparison.Flaky, name)
+		}
+		switch {
+		case headFailed && baseFailed:
+			comparison.StillFailing = append(comparison.StillFailing, name)
+		case headFailed:
+			comparison.NewFailures = append(comparison.NewFailures, name)
+		case baseFailed:
+			comparison.Fixed = append(comparison.Fixed, name)
+		}
+		if baseOutcome == nil || baseOutcome.LatencyMillis <= 0 {
+			continue
+		}
+		increase := (headOutcome.LatencyMillis - baseOutcome.LatencyMillis) / baseOutcome.LatencyMillis * 100
+		if increase > latencyThresholdPercent &&
+			headOutcome.LatencyMillis-baseOutcome.LatencyMillis >= minLatencyRegressionMillis {
+			comparison.LatencyRegressions = append(comparison.LatencyRegressions, &types.LatencyRegression{
+				Scenario:   name,
+				BaseMillis: baseOutcome.LatencyMillis,
+				HeadMillis: headOutcome.LatencyMillis,
+				Increase:   increase,
+			})
+		}
+	}
+	return comparison
+}
+
+// BuildContractStats computes success rate, latency, flakiness and top failures of the scenario over runs
+// that are sorted from most recent
+func BuildContractStats(scenarioName string, runs []*types.ContractRun) *ContractValidationStats {
+	stats := &ContractValidationStats{
+		ScenarioName: scenarioName,
+		Top5Failures: make([]string, 0),
+		Trend:        make([]*ContractRunPoint, 0),
+	}
+	failures := make(map[string]int)
+	var totalLatency float64
+	for i := len(runs) - 1; i >= 0; i-- {
+		outcome := runs[i].Scenarios[scenarioName]
+		if outcome == nil {
+			continue
+		}
+		stats.Runs++
+		stats.TotalExecutions += outcome.Executions
+		stats.SuccessCount += outcome.Passed
+		stats.FailureCount += outcome.Failed
+		totalLatency += outcome.LatencyMillis * float64(outcome.Executions)
+		if outcome.Flaky {
+			stats.FlakyRuns++
+		}
+		if runs[i].StartedAt.After(stats.LastExecuted) {
+			stats.LastExecuted = runs[i].StartedAt
+		}
+		for _, reason := range outcome.Failures {
+			failures[reason]++
+		}
+		stats.Trend = append(stats.Trend, &ContractRunPoint{
+			RunID:         runs[i].ID,
+			Label:         runs[i].Label,
+			StartedAt:     runs[i].StartedAt,
+			Status:        outcome.Status(),
+			Passed:        outcome.Passed,
+			Failed:        outcome.Failed,
+			LatencyMillis: outcome.LatencyMillis,
+		})
+	}
+	if stats.TotalExecutions == 0 {
+		return stats
+	}
+	stats.SuccessRate = float64(stats.SuccessCount) / float64(stats.TotalExecutions) * 100
+	stats.AverageLatency = totalLatency / float64(stats.TotalExecutions)
+	stats.Flaky = stats.FlakyRuns > 0
+
+	reasons := make([]string, 0, len(failures))
+	for reason := range failures {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if failures[reasons[i]] == failures[reasons[j]] {
+			return reasons[i] < reasons[j]
+		}
+		return failures[reasons[i]] > failures[reasons[j]]
+	})
+	for i := 0; i < len(reasons) && i < 5; i++ {
+		stats.Top5Failures = append(stats.Top5Failures, fmt.Sprintf("%s (%d times)", reasons[i], failures[reasons[i]]))
+	}
+	return stats
+}
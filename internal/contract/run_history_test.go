@@ -0,0 +1,172 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/fuzz"
+	"github.com/bhatti/api-mock-service/internal/repository"
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/bhatti/api-mock-service/internal/web"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldRecordContractRunsWithFlakyScenarios(t *testing.T) {
+	// GIVEN a producer that fails first request of flaky path and all requests of broken path
+	var lock sync.Mutex
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests[r.URL.Path]++
+		n := requests[r.URL.Path]
+		lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/broken" || (r.URL.Path == "/flaky" && n == 1) {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	// AND scenarios of a group with executor recording runs
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	runRepository, err := repository.NewFileContractRunRepository(&types.Configuration{DataDir: t.TempDir()})
+	require.NoError(t, err)
+	group := fmt.Sprintf("runs_group_%d", time.Now().UnixNano())
+	for _, path := range []string{"/stable", "/flaky", "/broken"} {
+		require.NoError(t, scenarioRepository.Save(newParallelTestScenario(group, path, 0)))
+	}
+	client := web.NewHTTPClient(config, web.NewAuthAdapter(config))
+	executor := NewProducerExecutor(scenarioRepository, groupConfigRepository, client).WithRunRepository(runRepository)
+	dataTemplate := fuzz.NewDataTemplateRequest(false, 1, 2)
+
+	// WHEN executing the group twice with record_results
+	contractReq := types.NewProducerContractRequest(server.URL, 2, 0)
+	contractReq.RecordResults = true
+	contractReq.RunLabel = "abc123"
+	contractReq.Environment = "staging"
+	first := executor.ExecuteByGroup(context.Background(), &http.Request{}, group, dataTemplate, contractReq)
+	second := executor.ExecuteByGroup(context.Background(), &http.Request{}, group, dataTemplate, contractReq)
+
+	// THEN both runs should be saved with per-scenario outcome
+	require.NotEmpty(t, first.RunID)
+	runs, err := executor.ListRuns(group, 0)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	require.Equal(t, second.RunID, runs[0].ID)
+	run, err := executor.LoadRun(first.RunID)
+	require.NoError(t, err)
+	require.Equal(t, "abc123", run.Label)
+	require.Equal(t, "staging", run.Environment)
+	require.Equal(t, types.RunKindContract, run.Kind)
+	require.Len(t, run.Scenarios, 3)
+	require.Equal(t, types.OutcomePassed, run.Scenarios[group+"_stable"].Status())
+	require.Equal(t, types.OutcomeFailed, run.Scenarios[group+"_broken"].Status())
+	require.Equal(t, 2, run.Scenarios[group+"_broken"].Failed)
+	require.NotEmpty(t, run.Scenarios[group+"_broken"].Failures)
+
+	// AND scenario that passed on retry should be flaky
+	require.Equal(t, types.OutcomeFlaky, run.Scenarios[group+"_flaky"].Status())
+	require.Equal(t, 1, run.Summary().Flaky)
+
+	// AND stats should aggregate outcomes over runs
+	stats, err := executor.GetContractRunStats(group, group+"_broken", 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.Runs)
+	require.Equal(t, 4, stats.TotalExecutions)
+	require.Equal(t, 0.0, stats.SuccessRate)
+	require.Len(t, stats.Trend, 2)
+	require.Equal(t, first.RunID, stats.Trend[0].RunID)
+	require.Len(t, stats.Top5Failures, 1)
+	require.Contains(t, stats.Top5Failures[0], "(4 times)")
+	stats, err = executor.GetContractRunStats(group, group+"_flaky", 0)
+	require.NoError(t, err)
+	require.True(t, stats.Flaky)
+	require.Equal(t, 75.0, stats.SuccessRate)
+
+	// AND comparing runs should report no regressions
+	comparison, err := executor.CompareRuns(first.RunID, second.RunID, 0)
+	require.NoError(t, err)
+	require.Empty(t, comparison.NewFailures)
+	require.Equal(t, []string{group + "_broken"}, comparison.StillFailing)
+	require.Empty(t, comparison.Fixed)
+
+	// WHEN running mutations of the group with record_results
+	mutations := executor.ExecuteMutationsByGroup(context.Background(), &http.Request{}, group, dataTemplate, contractReq)
+
+	// THEN run should be recorded under the same group with mutations kind
+	require.NotEmpty(t, mutations.RunID)
+	mutationRun, err := executor.LoadRun(mutations.RunID)
+	require.NoError(t, err)
+	require.Equal(t, group, mutationRun.Group)
+	require.Equal(t, types.RunKindMutations, mutationRun.Kind)
+}
+
+func Test_ShouldCompareContractRuns(t *testing.T) {
+	// GIVEN base and head runs
+	base := &types.ContractRun{ID: "base", Scenarios: map[string]*types.ScenarioOutcome{
+		"get_todo":    {Executions: 1, Passed: 1, LatencyMillis: 100},
+		"create_todo": {Executions: 1, Failed: 1, LatencyMillis: 50},
+		"list_todos":  {Executions: 1, Passed: 1, LatencyMillis: 2},
+		"old_todo":    {Executions: 1, Passed: 1},
+	}}
+	head := &types.ContractRun{ID: "head", Scenarios: map[string]*types.ScenarioOutcome{
+		"get_todo":    {Executions: 1, Failed: 1, LatencyMillis: 100},
+		"create_todo": {Executions: 1, Passed: 1, LatencyMillis: 200},
+		"list_todos":  {Executions: 1, Passed: 1, LatencyMillis: 4},
+		"new_todo":    {Executions: 2, Passed: 1, Failed: 1, Flaky: true},
+	}}
+
+	// WHEN comparing head against base
+	comparison := CompareContractRuns(base, head, 0)
+
+	// THEN new failures, fixes and latency regressions above noise should be reported
+	require.True(t, comparison.Regressed())
+	require.Equal(t, []string{"get_todo"}, comparison.NewFailures)
+	require.Equal(t, []string{"create_todo"}, comparison.Fixed)
+	require.Equal(t, []string{"new_todo"}, comparison.Flaky)
+	require.Equal(t, []string{"new_todo"}, comparison.Added)
+	require.Equal(t, []string{"old_todo"}, comparison.Removed)
+	require.Len(t, comparison.LatencyRegressions, 1)
+	require.Equal(t, "create_todo", comparison.LatencyRegressions[0].Scenario)
+	require.Equal(t, 300.0, comparison.LatencyRegressions[0].Increase)
+}
+
+func Test_ShouldMarkFlakyWhenScenarioPassesOnRerunOfSameLabel(t *testing.T) {
+	// GIVEN a failed run and a passing rerun of the same label
+	previous := []*types.ContractRun{
+		{ID: "2", Group: "todos", Label: "v2", Scenarios: map[string]*types.ScenarioOutcome{"get_todo": {Failed: 1}}},
+		{ID: "1", Group: "todos", Label: "v1", Scenarios: map[string]*types.ScenarioOutcome{"get_todo": {Failed: 1}}},
+	}
+	run := &types.ContractRun{ID: "3", Group: "todos", Label: "v1", Scenarios: map[string]*types.ScenarioOutcome{
+		"get_todo": {Executions: 1, Passed: 1},
+	}}
+
+	// WHEN marking flaky scenarios
+	markFlakyOnRetry(run, previous)
+
+	// THEN scenario should be flaky
+	require.True(t, run.Scenarios["get_todo"].Flaky)
+
+	// AND scenario passing in a run of another kind should not be flaky
+	run.Kind = types.RunKindMutations
+	run.Scenarios["get_todo"].Flaky = false
+	markFlakyOnRetry(run, previous)
+	require.False(t, run.Scenarios["get_todo"].Flaky)
+
+	// AND scenario passing under a new label should not be flaky
+	run.Kind = ""
+	run.Label = "v3"
+	run.Scenarios["get_todo"].Flaky = false
+	markFlakyOnRetry(run, previous)
+	require.False(t, run.Scenarios["get_todo"].Flaky)
+}
@@ -0,0 +1,348 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bhatti/api-mock-service/internal/jsonpath"
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Kinds of schema mutations
+const (
+	MutationWrongType          = "wrong-type"
+	MutationBelowMinimum       = "below-minimum"
+	MutationAboveMaximum       = "above-maximum"
+	MutationTooShort           = "too-short"
+	MutationTooLong            = "too-long"
+	MutationPattern            = "pattern"
+	MutationEnum               = "enum"
+	MutationFormat             = "format"
+	MutationAdditionalProperty = "additional-property"
+	MutationMissingRequired    = "missing-required"
+)
+
+// additionalPropertyName is added to objects that don't allow additional properties
+const additionalPropertyName = "unexpected_property"
+
+// invalidFormatValues violate string formats of OpenAPI
+var invalidFormatValues = map[string]string{
+	"email":     "not-an-email",
+	"uuid":      "not-a-uuid",
+	"date":      "2024-13-45",
+	"date-time": "not-a-date-time",
+	"uri":       "not a uri",
+	"hostname":  "-invalid-host-",
+	"ipv4":      "999.999.999.999",
+	"ipv6":      "not-an-ipv6",
+}
+
+// SchemaMutator generates mutations of request bodies from the OpenAPI schema of operations
+type SchemaMutator struct {
+	doc *openapi3.T
+}
+
+// NewSchemaMutator creates mutator for the spec
+func NewSchemaMutator(doc *openapi3.T) *SchemaMutator {
+	return &SchemaMutator{doc: doc}
+}
+
+// GenerateMutations returns a mutation for each constraint of the request body schema: wrong types, values outside
+// minimum/maximum/minLength/maxLength, invalid pattern, enum and format values, additional properties when not
+// allowed and missing required fields. Scenarios without JSON object body or operation in the spec have none.
+func (m *SchemaMutator) GenerateMutations(scenario *types.APIScenario) []*Mutation {
+	schema := m.requestSchema(scenario)
+	if schema == nil || scenario.Request.Contents == "" {
+		return nil
+	}
+	var body map[string]any
+	if err := json.Unmarshal([]byte(scenario.Request.Contents), &body); err != nil {
+		return nil
+	}
+	gen := &schemaMutationGenerator{scenario: scenario, body: body}
+	gen.object(schema, nil)
+	return gen.mutations
+}
+
+// requestSchema finds JSON request body schema of the operation matching method and path of the scenario
+func (m *SchemaMutator) requestSchema(scenario *types.APIScenario) *openapi3.Schema {
+	if m.doc == nil {
+		return nil
+	}
+	for _, specPath := range sortedSpecPaths(m.doc) {
+		if !specPathMatches(specPath, scenario.Path) {
+			continue
+		}
+		op := m.doc.Paths[specPath].GetOperation(string(scenario.Method))
+		if op == nil || op.RequestBody == nil || op.RequestBody.Value == nil {
+			continue
+		}
+		content := op.RequestBody.Value.Content
+		media := content.Get("application/json")
+		if media == nil {
+			for contentType, candidate := range content {
+				if strings.Contains(contentType, "json") {
+					media = candidate
+					break
+				}
+			}
+		}
+		if media != nil && media.Schema != nil && media.Schema.Value != nil {
+			return media.Schema.Value
+		}
+	}
+	return nil
+}
+
+// sortedSpecPaths returns paths of spec with literal paths before templated paths
+func sortedSpecPaths(doc *openapi3.T) []string {
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		pi, pj := strings.Count(paths[i], "{"), strings.Count(paths[j], "{")
+		if pi != pj {
+			return pi < pj
+		}
+		return paths[i] < paths[j]
+	})
+	return paths
+}
+
+// specPathMatches matches path of spec such as /orders/{id} with path of scenario such as /orders/:id or /orders/42
+func specPathMatches(specPath string, path string) bool {
+	specParts := strings.Split(strings.Trim(specPath, "/"), "/")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(specParts) != len(parts) {
+		return false
+	}
+	for i, specPart := range specParts {
+		if strings.HasPrefix(specPart, "{") && strings.HasSuffix(specPart, "}") {
+			continue
+		}
+		if specPart != parts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// schemaMutationGenerator walks schema and request body together
+type schemaMutationGenerator struct {
+	scenario  *types.APIScenario
+	body      map[string]any
+	mutations []*Mutation
+}
+
+// object adds mutations of properties of the object at path and recurses into nested objects
+func (g *schemaMutationGenerator) object(schema *openapi3.Schema, path []any) {
+	obj, ok := jsonpath.ValueAt(g.body, path).(map[string]any)
+	if !ok {
+		return
+	}
+	for _, name := range schema.Required {
+		if _, exists := obj[name]; exists {
+			g.add(MutationMissingRequired, jsonpath.Append(path, name), nil, true)
+		}
+	}
+	if schema.AdditionalPropertiesAllowed != nil && !*schema.AdditionalPropertiesAllowed {
+		if _, exists := obj[additionalPropertyName]; !exists {
+			g.add(MutationAdditionalProperty, jsonpath.Append(path, additionalPropertyName), "unexpected", false)
+		}
+	}
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ref := schema.Properties[name]
+		if ref == nil || ref.Value == nil || ref.Value.ReadOnly {
+			continue
+		}
+		if _, exists := obj[name]; !exists {
+			continue
+		}
+		g.property(ref.Value, jsonpath.Append(path, name))
+	}
+}
+
+// property adds mutations of the property at path
+func (g *schemaMutationGenerator) property(schema *openapi3.Schema, path []any) {
+	value := jsonpath.ValueAt(g.body, path)
+	if wrong := wrongTypeValue(schema); wrong != nil {
+		g.add(MutationWrongType, path, wrong, false)
+	}
+	switch schema.Type {
+	case openapi3.TypeInteger, openapi3.TypeNumber:
+		if schema.Min != nil {
+			below := *schema.Min - 1
+			if schema.ExclusiveMin {
+				below = *schema.Min
+			}
+			g.add(MutationBelowMinimum, path, below, false)
+		}
+		if schema.Max != nil {
+			above := *schema.Max + 1
+			if schema.ExclusiveMax {
+				above = *schema.Max
+			}
+			g.add(MutationAboveMaximum, path, above, false)
+		}
+	case openapi3.TypeString:
+		if schema.MinLength > 0 {
+			g.add(MutationTooShort, path, strings.Repeat("x", int(schema.MinLength)-1), false)
+		}
+		if schema.MaxLength != nil {
+			g.add(MutationTooLong, path, strings.Repeat("x", int(*schema.MaxLength)+1), false)
+		}
+		if invalid, ok := patternViolation(schema.Pattern); ok {
+			g.add(MutationPattern, path, invalid, false)
+		}
+		if invalid, ok := invalidFormatValues[schema.Format]; ok {
+			g.add(MutationFormat, path, invalid, false)
+		}
+	case openapi3.TypeArray:
+		if items, ok := value.([]any); ok && len(items) > 0 && schema.Items != nil && schema.Items.Value != nil {
+			g.nested(schema.Items.Value, jsonpath.Append(path, 0))
+		}
+	default:
+		g.nested(schema, path)
+	}
+	if invalid, ok := enumViolation(schema); ok {
+		g.add(MutationEnum, path, invalid, false)
+	}
+}
+
+// nested recurses into object schema of the value at path
+func (g *schemaMutationGenerator) nested(schema *openapi3.Schema, path []any) {
+	if schema.Type == openapi3.TypeObject || (schema.Type == "" && len(schema.Properties) > 0) {
+		g.object(schema, path)
+	}
+}
+
+// add creates mutation that sets (or removes) the value at path of a copy of the body
+func (g *schemaMutationGenerator) add(kind string, path []any, value any, remove bool) {
+	body := deepCopyJSON(g.body).(map[string]any)
+	var changed bool
+	if remove {
+		changed = jsonpath.RemoveValueAt(body, path)
+	} else {
+		changed = jsonpath.SetValueAt(body, path, value)
+	}
+	if !changed {
+		return
+	}
+	contents, err := json.Marshal(body)
+	if err != nil {
+		return
+	}
+	field := jsonpath.Format(path)
+	s := *g.scenario
+	s.Name = fmt.Sprintf("%s-schema-%s-%s", g.scenario.Name, kind, field)
+	s.Request.Contents = string(contents)
+	// mutated body violates request assertions of the scenario by design
+	s.Request.AssertContentsPattern = ""
+	s.Request.Assertions = nil
+	s.Response.StatusCode = 400
+	s.Response.AssertContentsPattern = ""
+	s.Response.AssertHeadersPattern = nil
+	s.Response.Assertions = nil
+	g.mutations = append(g.mutations, &Mutation{
+		Scenario: &s,
+		Kind:     kind,
+		Field:    field,
+		Value:    value,
+		Expected: ExpectClientError,
+	})
+}
+
+// wrongTypeValue returns value of another JSON type than the schema type
+func wrongTypeValue(schema *openapi3.Schema) any {
+	switch schema.Type {
+	case openapi3.TypeString:
+		return 12345
+	case openapi3.TypeInteger:
+		return "not-an-integer"
+	case openapi3.TypeNumber:
+		return "not-a-number"
+	case openapi3.TypeBoolean:
+		return "not-a-boolean"
+	case openapi3.TypeArray:
+		return "not-an-array"
+	case openapi3.TypeObject:
+		return "not-an-object"
+	}
+	return nil
+}
+
+// patternViolation returns a string that doesn't match the pattern
+func patternViolation(pattern string) (string, bool) {
+	if pattern == "" {
+		return "", false
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", false
+	}
+	for _, candidate := range []string{"!@#$%^&*", "", " ", "0", "a", "INVALID value"} {
+		if !re.MatchString(candidate) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// enumViolation returns a value of the schema type that isn't in the enum
+func enumViolation(schema *openapi3.Schema) (any, bool) {
+	if len(schema.Enum) == 0 {
+		return nil, false
+	}
+	inEnum := func(candidate any) bool {
+		for _, val := range schema.Enum {
+			if fmt.Sprintf("%v", val) == fmt.Sprintf("%v", candidate) {
+				return true
+			}
+		}
+		return false
+	}
+	var candidates []any
+	switch schema.Type {
+	case openapi3.TypeInteger, openapi3.TypeNumber:
+		candidates = []any{-1, 0, 999999}
+	case openapi3.TypeBoolean:
+		return nil, false
+	default:
+		candidates = []any{"not-in-enum", "NOT_IN_ENUM_VALUE"}
+	}
+	for _, candidate := range candidates {
+		if !inEnum(candidate) {
+			return candidate, true
+		}
+	}
+	return nil, false
+}
+
+// deepCopyJSON copies maps and arrays of a JSON document
+func deepCopyJSON(doc any) any {
+	switch v := doc.(type) {
+	case map[string]any:
+		clone := make(map[string]any, len(v))
+		for k, val := range v {
+			clone[k] = deepCopyJSON(val)
+		}
+		return clone
+	case []any:
+		clone := make([]any, len(v))
+		for i, val := range v {
+			clone[i] = deepCopyJSON(val)
+		}
+		return clone
+	}
+	return doc
+}
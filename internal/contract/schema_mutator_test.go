@@ -0,0 +1,230 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/fuzz"
+	"github.com/bhatti/api-mock-service/internal/oapi"
+	"github.com/bhatti/api-mock-service/internal/repository"
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/bhatti/api-mock-service/internal/web"
+	"github.com/stretchr/testify/require"
+)
+
+// schemaMutationYAML is a spec of orders with constraints on the request body
+const schemaMutationYAML = `
+openapi: "3.0.3"
+info:
+  title: Orders API
+  version: "1.0"
+paths:
+  /orders:
+    post:
+      operationId: createOrder
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              additionalProperties: false
+              required: [sku, quantity]
+              properties:
+                sku:
+                  type: string
+                  pattern: "^[A-Z]{3}-[0-9]{4}$"
+                quantity:
+                  type: integer
+                  minimum: 1
+                  maximum: 100
+                status:
+                  type: string
+                  enum: [new, paid]
+                email:
+                  type: string
+                  format: email
+                  maxLength: 20
+                shipping:
+                  type: object
+                  required: [zip]
+                  properties:
+                    zip:
+                      type: string
+                      minLength: 5
+                items:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      price:
+                        type: number
+                        minimum: 0
+      responses:
+        "201":
+          description: Created
+`
+
+func newSchemaMutationScenario(group string) *types.APIScenario {
+	return &types.APIScenario{
+		Method: types.Post,
+		Name:   "create-order",
+		Path:   "/orders",
+		Group:  group,
+		Request: types.APIRequest{
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Contents: `{"sku": "ABC-1234", "quantity": 2, "status": "new", "email": "a@b.io",
+"shipping": {"zip": "94105"}, "items": [{"price": 10.5}]}`,
+			AssertContentsPattern: `{"sku": "(__string__\\w+)"}`,
+		},
+		Response: types.APIResponse{
+			StatusCode: 201,
+			Contents:   `{"id": "1"}`,
+		},
+	}
+}
+
+func Test_ShouldGenerateSchemaMutationsForEachConstraint(t *testing.T) {
+	// GIVEN a spec with constraints of request body
+	doc := mustParseOAPIDoc(t, []byte(schemaMutationYAML))
+	scenario := newSchemaMutationScenario("orders")
+
+	// WHEN generating schema mutations
+	mutations := NewSchemaMutator(doc).GenerateMutations(scenario)
+
+	// THEN each constraint should have a mutation that expects a 4xx
+	byName := make(map[string]*Mutation)
+	for _, mutation := range mutations {
+		byName[mutation.Kind+" "+mutation.Field] = mutation
+		require.Equal(t, ExpectClientError, mutation.Expected)
+		require.Equal(t, 400, mutation.Scenario.Response.StatusCode)
+		require.Empty(t, mutation.Scenario.Request.AssertContentsPattern)
+	}
+	for _, name := range []string{
+		"wrong-type sku", "pattern sku", "wrong-type quantity", "below-minimum quantity", "above-maximum quantity",
+		"enum status", "format email", "too-long email", "additional-property unexpected_property",
+		"missing-required sku", "missing-required quantity", "wrong-type shipping", "too-short shipping.zip",
+		"missing-required shipping.zip", "wrong-type items", "below-minimum items[0].price",
+	} {
+		require.Contains(t, byName, name)
+	}
+
+	// AND mutated body should only change the mutated field
+	var body map[string]any
+	require.NoError(t, json.Unmarshal([]byte(byName["above-maximum quantity"].Scenario.Request.Contents), &body))
+	require.Equal(t, float64(101), body["quantity"])
+	require.Equal(t, "ABC-1234", body["sku"])
+	require.NoError(t, json.Unmarshal([]byte(byName["below-minimum items[0].price"].Scenario.Request.Contents), &body))
+	require.Equal(t, float64(-1), body["items"].([]any)[0].(map[string]any)["price"])
+	require.NoError(t, json.Unmarshal([]byte(byName["missing-required shipping.zip"].Scenario.Request.Contents), &body))
+	require.Empty(t, body["shipping"])
+	require.Equal(t, 21, len(byName["too-long email"].Value.(string)))
+
+	// AND original scenario should not be changed
+	require.Contains(t, scenario.Request.Contents, `"quantity": 2`)
+	require.Equal(t, 201, scenario.Response.StatusCode)
+}
+
+func Test_ShouldNotGenerateSchemaMutationsWithoutOperation(t *testing.T) {
+	doc := mustParseOAPIDoc(t, []byte(schemaMutationYAML))
+	scenario := newSchemaMutationScenario("orders")
+	scenario.Path = "/customers"
+	require.Empty(t, NewSchemaMutator(doc).GenerateMutations(scenario))
+	scenario = newSchemaMutationScenario("orders")
+	scenario.Request.Contents = ""
+	require.Empty(t, NewSchemaMutator(doc).GenerateMutations(scenario))
+}
+
+func Test_ShouldMatchSpecPaths(t *testing.T) {
+	require.True(t, specPathMatches("/orders/{id}", "/orders/:id"))
+	require.True(t, specPathMatches("/orders/{id}", "/orders/42"))
+	require.True(t, specPathMatches("/orders", "/orders/"))
+	require.False(t, specPathMatches("/orders/{id}", "/orders"))
+	require.False(t, specPathMatches("/orders/{id}/items", "/orders/42/lines"))
+}
+
+func Test_ShouldReportSchemaMutationsAcceptedByProducer(t *testing.T) {
+	// GIVEN a producer that validates sku and quantity but accepts any other field
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		data, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.Unmarshal(data, &body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "bad json"}`))
+			return
+		}
+		sku, _ := body["sku"].(string)
+		quantity, ok := body["quantity"].(float64)
+		if len(sku) != 8 || !strings.Contains(sku, "-") || !ok || quantity < 1 || quantity > 100 {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"error": "invalid order"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer server.Close()
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	group := fmt.Sprintf("schema_mutation_%d", time.Now().UnixNano())
+	require.NoError(t, scenarioRepository.Save(newSchemaMutationScenario(group)))
+	doc := mustParseOAPIDoc(t, []byte(schemaMutationYAML))
+	router, err := oapi.BuildRouter(doc)
+	require.NoError(t, err)
+	executor := NewProducerExecutor(scenarioRepository, groupConfigRepository,
+		web.NewHTTPClient(config, web.NewAuthAdapter(config))).WithOpenAPISpec(doc, router)
+
+	// WHEN executing mutations of the group with the spec
+	contractReq := types.NewProducerContractRequest(server.URL, 1, 0)
+	res := executor.ExecuteMutationsByGroup(context.Background(), &http.Request{}, group,
+		fuzz.NewDataTemplateRequest(false, 1, 1), contractReq)
+
+	// THEN rejected schema mutations should pass and accepted ones should be reported as defects
+	schemaErrors := make(map[string]string)
+	schemaPassed := make(map[string]bool)
+	for key := range res.Results {
+		if strings.Contains(key, "-schema-") {
+			schemaPassed[key] = true
+		}
+	}
+	for key, msg := range res.Errors {
+		if strings.Contains(key, "-schema-") {
+			schemaErrors[key] = msg
+		}
+	}
+	require.True(t, hasKeyPrefix(schemaPassed, "create-order-schema-above-maximum-quantity_"))
+	require.True(t, hasKeyPrefix(schemaPassed, "create-order-schema-missing-required-sku_"))
+	require.True(t, hasKeyPrefix(schemaPassed, "create-order-schema-pattern-sku_"))
+	for _, prefix := range []string{
+		"create-order-schema-enum-status_",
+		"create-order-schema-format-email_",
+		"create-order-schema-additional-property-unexpected_property_",
+		"create-order-schema-too-short-shipping.zip_",
+	} {
+		require.True(t, hasKeyPrefix(schemaErrors, prefix), prefix)
+	}
+	for key, msg := range schemaErrors {
+		if strings.HasPrefix(key, "create-order-schema-enum-status_") {
+			require.Contains(t, msg, "enum mutation of 'status' was accepted with status 201, expected 4xx")
+		}
+	}
+}
+
+func hasKeyPrefix[V any](m map[string]V, prefix string) bool {
+	for key := range m {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
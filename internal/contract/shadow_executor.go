@@ -0,0 +1,321 @@
+package contract
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/fuzz"
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/bhatti/api-mock-service/internal/utils"
+	"github.com/bhatti/api-mock-service/internal/web"
+	log "github.com/sirupsen/logrus"
+)
+
+// ShadowServedUpstream value of served header when upstream response is returned
+const ShadowServedUpstream = "upstream"
+
+// ShadowServedMock value of served header when mock response is returned
+const ShadowServedMock = "mock"
+
+// mockHeaderPrefix of headers that control the mock service and are never sent to or compared with upstream
+const mockHeaderPrefix = "X-Mock-"
+
+// skipMockDelayKey is context key of mock requests in shadow mode whose artificial delay is skipped
+type skipMockDelayKey struct{}
+
+// shadowIgnoredHeaders are never compared because they change on every response
+var shadowIgnoredHeaders = []string{"Date", types.ContentLengthHeader, "Connection", "Transfer-Encoding"}
+
+// ScenarioDrift records differences between mock and live upstream response of a scenario
+type ScenarioDrift struct {
+	Group          string              `json:"group"`
+	ScenarioName   string              `json:"scenario_name"`
+	Method         string              `json:"method"`
+	Path           string              `json:"path"`
+	UpstreamURL    string              `json:"upstream_url"`
+	MockStatus     int                 `json:"mock_status"`
+	UpstreamStatus int                 `json:"upstream_status"`
+	Drifted        bool                `json:"drifted"`
+	Checks         int                 `json:"checks"`
+	DriftCount     int                 `json:"drift_count"`
+	LastCheckedAt  time.Time           `json:"last_checked_at"`
+	Report         *ContractDiffReport `json:"report"`
+}
+
+// DriftStore keeps latest drift of each scenario observed in shadow mode
+type DriftStore struct {
+	lock   sync.RWMutex
+	drifts map[string]*ScenarioDrift
+}
+
+// NewDriftStore instantiates drift store
+func NewDriftStore() *DriftStore {
+	return &DriftStore{drifts: make(map[string]*ScenarioDrift)}
+}
+
+// Record stores drift of the scenario and accumulates number of checks and drifts
+func (ds *DriftStore) Record(drift *ScenarioDrift) {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	key := drift.Group + "/" + drift.ScenarioName
+	drift.Checks = 1
+	drift.DriftCount = 0
+	if drift.Drifted {
+		drift.DriftCount = 1
+	}
+	if old := ds.drifts[key]; old != nil {
+		drift.Checks += old.Checks
+		drift.DriftCount += old.DriftCount
+	}
+	ds.drifts[key] = drift
+}
+
+// List returns drifts sorted by group and scenario name, optionally filtered by group and drifted status
+func (ds *DriftStore) List(group string, driftedOnly bool) []*ScenarioDrift {
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	res := make([]*ScenarioDrift, 0)
+	for _, drift := range ds.drifts {
+		if group != "" && drift.Group != group {
+			continue
+		}
+		if driftedOnly && !drift.Drifted {
+			continue
+		}
+		res = append(res, drift)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Group != res[j].Group {
+			return res[i].Group < res[j].Group
+		}
+		return res[i].ScenarioName < res[j].ScenarioName
+	})
+	return res
+}
+
+// Clear removes drifts of the group or all drifts if group is empty
+func (ds *DriftStore) Clear(group string) {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	for k, drift := range ds.drifts {
+		if group == "" || drift.Group == group {
+			delete(ds.drifts, k)
+		}
+	}
+}
+
+// shadowEnabled checks shadow header or config when upstream client is available
+func (cx *ConsumerExecutor) shadowEnabled(req *http.Request) bool {
+	if cx.upstreamClient == nil || cx.driftStore == nil {
+		return false
+	}
+	if val := req.Header.Get(types.MockShadow); val != "" {
+		return val == "true"
+	}
+	return cx.config.Shadow.Enabled
+}
+
+// executeShadow calls both upstream and mock, records drift and serves upstream (or mock) response
+func (cx *ConsumerExecutor) executeShadow(c web.APIContext) (err error) {
+	var reqBody []byte
+	reqBody, c.Request().Body, err = utils.ReadAll(c.Request().Body)
+	if err != nil {
+		return web.HandleError(c, err)
+	}
+	overrides := buildOverrides(c)
+	key, err := web.BuildMockScenarioKeyData(c.Request())
+	if err != nil {
+		return web.HandleError(c, err)
+	}
+	mockReq := c.Request()
+	if !cx.config.Shadow.ServeMock {
+		// upstream response is served so the mock's wait or latency would only delay it
+		mockReq = mockReq.WithContext(context.WithValue(mockReq.Context(), skipMockDelayKey{}, true))
+	}
+	mockHeaders := make(http.Header)
+	scenario, mockBody, _, mockErr := cx.ExecuteWithKey(mockReq, mockHeaders, key, overrides)
+
+	upstreamURL := cx.shadowUpstreamURL(c.Request(), scenario)
+	upStatus, upHeaders, upBody, upErr := cx.callUpstream(c.Request(), upstreamURL, reqBody)
+
+	if mockErr == nil && upErr == nil {
+		drift := compareShadowResponses(
+			scenario, mockHeaders, mockBody, upstreamURL, upStatus, upHeaders, upBody, cx.config.Shadow.IgnoreHeaders)
+		cx.driftStore.Record(drift)
+		if drift.Drifted {
+			log.WithFields(log.Fields{
+				"Component":      "ConsumerExecutor-Shadow",
+				"Scenario":       scenario.Name,
+				"Group":          scenario.Group,
+				"UpstreamURL":    upstreamURL,
+				"MockStatus":     drift.MockStatus,
+				"UpstreamStatus": upStatus,
+			}).Warnf("mock response drifted from upstream")
+		}
+	} else {
+		log.WithFields(log.Fields{
+			"Component":     "ConsumerExecutor-Shadow",
+			"Path":          c.Request().URL,
+			"UpstreamURL":   upstreamURL,
+			"MockError":     mockErr,
+			"UpstreamError": upErr,
+		}).Infof("skipped drift comparison")
+	}
+
+	if upErr != nil || cx.config.Shadow.ServeMock {
+		if mockErr != nil {
+			return web.HandleError(c, mockErr)
+		}
+		for k, vals := range mockHeaders {
+			c.Response().Header()[k] = vals
+		}
+		c.Response().Header().Set(types.MockShadowServed, ShadowServedMock)
+		return c.Blob(scenario.Response.StatusCode, scenario.Response.ContentType(""), mockBody)
+	}
+	for k, vals := range upHeaders {
+		if !strings.EqualFold(k, types.ContentLengthHeader) {
+			c.Response().Header()[k] = vals
+		}
+	}
+	c.Response().Header().Set(types.MockShadowServed, ShadowServedUpstream)
+	return c.Blob(upStatus, upHeaders.Get(types.ContentTypeHeader), upBody)
+}
+
+// shadowUpstreamURL builds url of live service from X-Mock-Url header, shadow config or base url of scenario
+func (cx *ConsumerExecutor) shadowUpstreamURL(req *http.Request, scenario *types.APIScenario) string {
+	if mockURL := req.Header.Get(types.MockURL); mockURL != "" {
+		return mockURL
+	}
+	baseURL := cx.config.Shadow.UpstreamURL
+	if baseURL == "" && scenario != nil {
+		baseURL = scenario.BaseURL
+	}
+	if baseURL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(baseURL, "/") + req.URL.RequestURI()
+}
+
+func (cx *ConsumerExecutor) callUpstream(
+	req *http.Request,
+	upstreamURL string,
+	reqBody []byte) (status int, headers http.Header, body []byte, err error) {
+	if upstreamURL == "" {
+		return 0, nil, nil, fmt.Errorf("upstream url is not defined for shadow request %s", req.URL)
+	}
+	status, _, resBody, headers, err := cx.upstreamClient.Handle(
+		context.Background(),
+		upstreamURL,
+		req.Method,
+		upstreamRequestHeaders(req.Header),
+		nil,
+		utils.NopCloser(bytes.NewReader(reqBody)),
+	)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	body, _, err = utils.ReadAll(resBody)
+	return
+}
+
+// upstreamRequestHeaders returns copy of request headers without mock control headers
+func upstreamRequestHeaders(headers http.Header) http.Header {
+	res := make(http.Header, len(headers))
+	for k, vals := range headers {
+		if !strings.HasPrefix(http.CanonicalHeaderKey(k), mockHeaderPrefix) {
+			res[k] = vals
+		}
+	}
+	return res
+}
+
+// compareShadowResponses compares status, headers and body of mock against upstream response
+func compareShadowResponses(
+	scenario *types.APIScenario,
+	mockHeaders http.Header,
+	mockBody []byte,
+	upstreamURL string,
+	upStatus int,
+	upHeaders http.Header,
+	upBody []byte,
+	ignoreHeaders []string,
+) *ScenarioDrift {
+	report := &ContractDiffReport{
+		ExpectedFields:   make(map[string]interface{}),
+		ActualFields:     make(map[string]interface{}),
+		MissingFields:    make([]string, 0),
+		ExtraFields:      make([]string, 0),
+		TypeMismatches:   make(map[string]string),
+		ValueMismatches:  make(map[string]ValueMismatch),
+		HeaderMismatches: make(map[string]ValueMismatch),
+	}
+	for k, vals := range mockHeaders {
+		if len(vals) == 0 || strings.HasPrefix(k, mockHeaderPrefix) ||
+			containsHeader(shadowIgnoredHeaders, k) || containsHeader(ignoreHeaders, k) {
+			continue
+		}
+		expected := strings.TrimPrefix(vals[0], fuzz.PrefixTypeExample)
+		if strings.HasPrefix(expected, fuzz.PrefixTypeString) {
+			continue
+		}
+		actual := upHeaders.Get(k)
+		if actual == "" {
+			report.HeaderMismatches[k] = ValueMismatch{Expected: expected, Actual: nil}
+		} else if actual != expected {
+			report.HeaderMismatches[k] = ValueMismatch{Expected: expected, Actual: actual}
+		}
+	}
+
+	mockContents, mockErr := fuzz.UnmarshalArrayOrObject(mockBody)
+	upContents, upErr := fuzz.UnmarshalArrayOrObject(upBody)
+	if mockErr == nil && upErr == nil && mockContents != nil && upContents != nil {
+		mockType, upType := getTypeName(mockContents), getTypeName(upContents)
+		if mockType != upType {
+			report.TypeMismatches["body"] = fmt.Sprintf("expected %s, got %s", mockType, upType)
+		} else if mockObj, ok := mockContents.(map[string]any); ok {
+			report.ExpectedFields = mockObj
+			report.ActualFields = upContents.(map[string]any)
+			compareObjects(mockObj, report.ActualFields, "", report)
+		} else if mockArr, ok := mockContents.([]any); ok {
+			compareArrays(mockArr, upContents.([]any), "", report)
+		}
+	} else if strings.TrimSpace(string(mockBody)) != strings.TrimSpace(string(upBody)) {
+		report.ValueMismatches["body"] = ValueMismatch{Expected: string(mockBody), Actual: string(upBody)}
+	}
+	sort.Strings(report.MissingFields)
+	sort.Strings(report.ExtraFields)
+
+	// values of mock responses are usually generated so only structural differences are treated as drift
+	drifted := scenario.Response.StatusCode != upStatus ||
+		len(report.MissingFields) > 0 ||
+		len(report.ExtraFields) > 0 ||
+		len(report.TypeMismatches) > 0 ||
+		len(report.HeaderMismatches) > 0
+	return &ScenarioDrift{
+		Group:          scenario.Group,
+		ScenarioName:   scenario.Name,
+		Method:         string(scenario.Method),
+		Path:           scenario.Path,
+		UpstreamURL:    upstreamURL,
+		MockStatus:     scenario.Response.StatusCode,
+		UpstreamStatus: upStatus,
+		Drifted:        drifted,
+		LastCheckedAt:  time.Now(),
+		Report:         report,
+	}
+}
+
+func containsHeader(headers []string, name string) bool {
+	for _, next := range headers {
+		if strings.EqualFold(next, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package contract
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/repository"
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/bhatti/api-mock-service/internal/web"
+	"github.com/stretchr/testify/require"
+)
+
+func buildShadowScenario() *types.APIScenario {
+	return &types.APIScenario{
+		Method:  types.Get,
+		Name:    "get-shadow-todo",
+		Path:    "/shadow/todos/:id",
+		Group:   "shadow",
+		BaseURL: "https://upstream.example.com",
+		Request: types.APIRequest{
+			Headers: make(map[string]string),
+		},
+		Response: types.APIResponse{
+			StatusCode: 200,
+			Headers: map[string][]string{
+				types.ContentTypeHeader: {"application/json"},
+			},
+			Contents: `{"id": {{.id}}, "title": "mock title", "completed": true}`,
+		},
+	}
+}
+
+func Test_ShouldRecordDriftAndServeUpstreamInShadowMode(t *testing.T) {
+	// GIVEN a mock scenario and shadow mode enabled
+	config := types.BuildTestConfig()
+	config.Shadow.Enabled = true
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	fixtureRepository, err := repository.NewFileFixtureRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	require.NoError(t, scenarioRepository.Save(buildShadowScenario()))
+	// AND upstream that renamed title and changed completed type
+	client := web.NewStubHTTPClient()
+	client.AddMapping("GET", "https://upstream.example.com/shadow/todos/7",
+		web.NewStubHTTPResponse(200, `{"id": 7, "name": "live title", "completed": "yes"}`))
+	driftStore := NewDriftStore()
+	player := NewConsumerExecutor(config, scenarioRepository, fixtureRepository, groupConfigRepository)
+	player.EnableShadow(client, driftStore)
+
+	// WHEN executing request
+	u, err := url.Parse("http://localhost:8080/shadow/todos/7")
+	require.NoError(t, err)
+	ctx := web.NewStubContext(&http.Request{Method: "GET", URL: u, Header: http.Header{}})
+	err = player.Execute(ctx)
+
+	// THEN upstream response should be served
+	require.NoError(t, err)
+	require.Contains(t, string(ctx.Result.([]byte)), "live title")
+	// AND drift should be recorded for the scenario
+	drifts := driftStore.List("shadow", true)
+	require.Len(t, drifts, 1)
+	require.Equal(t, "get-shadow-todo", drifts[0].ScenarioName)
+	require.Equal(t, "https://upstream.example.com/shadow/todos/7", drifts[0].UpstreamURL)
+	require.Equal(t, []string{"title"}, drifts[0].Report.MissingFields)
+	require.Equal(t, []string{"name"}, drifts[0].Report.ExtraFields)
+	require.Contains(t, drifts[0].Report.TypeMismatches, "completed")
+	require.Equal(t, 1, drifts[0].Checks)
+	require.Equal(t, 1, drifts[0].DriftCount)
+}
+
+func Test_ShouldServeMockWithoutDriftInShadowMode(t *testing.T) {
+	// GIVEN a mock scenario and shadow mode serving mock responses
+	config := types.BuildTestConfig()
+	config.Shadow.ServeMock = true
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	fixtureRepository, err := repository.NewFileFixtureRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	require.NoError(t, scenarioRepository.Save(buildShadowScenario()))
+	// AND upstream with same shape but different values
+	client := web.NewStubHTTPClient()
+	client.AddMapping("GET", "https://upstream.example.com/shadow/todos/8",
+		web.NewStubHTTPResponse(200, `{"id": 8, "title": "live title", "completed": false}`))
+	driftStore := NewDriftStore()
+	player := NewConsumerExecutor(config, scenarioRepository, fixtureRepository, groupConfigRepository)
+	player.EnableShadow(client, driftStore)
+
+	// WHEN executing request with shadow header
+	u, err := url.Parse("http://localhost:8080/shadow/todos/8")
+	require.NoError(t, err)
+	ctx := web.NewStubContext(&http.Request{Method: "GET", URL: u, Header: http.Header{
+		types.MockShadow: {"true"},
+	}})
+	err = player.Execute(ctx)
+
+	// THEN mock response should be served
+	require.NoError(t, err)
+	require.Contains(t, string(ctx.Result.([]byte)), "mock title")
+	// AND scenario should be checked without drift
+	require.Len(t, driftStore.List("shadow", true), 0)
+	all := driftStore.List("shadow", false)
+	require.Len(t, all, 1)
+	require.False(t, all[0].Drifted)
+	require.Contains(t, all[0].Report.ValueMismatches, "title")
+}
+
+func Test_ShouldNotForwardMockHeadersToUpstreamInShadowMode(t *testing.T) {
+	// GIVEN an upstream that records request headers
+	var upstreamHeaders http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamHeaders = r.Header.Clone()
+		w.Header().Set(types.ContentTypeHeader, "application/json")
+		_, _ = w.Write([]byte(`{"id": 9, "title": "live title", "completed": true}`))
+	}))
+	defer server.Close()
+	config := types.BuildTestConfig()
+	config.Shadow.Enabled = true
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	fixtureRepository, err := repository.NewFileFixtureRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	scenario := buildShadowScenario()
+	scenario.BaseURL = server.URL
+	require.NoError(t, scenarioRepository.Save(scenario))
+	player := NewConsumerExecutor(config, scenarioRepository, fixtureRepository, groupConfigRepository)
+	player.EnableShadow(web.NewHTTPClient(config, web.NewAuthAdapter(config)), NewDriftStore())
+
+	// WHEN executing request with mock control headers
+	u, err := url.Parse("http://localhost:8080/shadow/todos/9")
+	require.NoError(t, err)
+	ctx := web.NewStubContext(&http.Request{Method: "GET", URL: u, Header: http.Header{
+		types.MockShadow: {"true"},
+		types.MockGroup:  {"shadow"},
+		"X-Request-Id":   {"abc"},
+	}})
+	err = player.Execute(ctx)
+
+	// THEN upstream should receive other headers without mock headers
+	require.NoError(t, err)
+	require.Equal(t, "abc", upstreamHeaders.Get("X-Request-Id"))
+	for k := range upstreamHeaders {
+		require.False(t, strings.HasPrefix(k, mockHeaderPrefix), k)
+	}
+}
+
+func Test_ShouldNotDelayUpstreamResponseByMockWaitInShadowMode(t *testing.T) {
+	// GIVEN a mock scenario that waits before reply and shadow mode serving upstream
+	config := types.BuildTestConfig()
+	config.Shadow.Enabled = true
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	fixtureRepository, err := repository.NewFileFixtureRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	scenario := buildShadowScenario()
+	scenario.Name = "get-slow-shadow-todo"
+	scenario.Path = "/shadow/slow-todos/:id"
+	scenario.WaitBeforeReply = 2 * time.Second
+	require.NoError(t, scenarioRepository.Save(scenario))
+	client := web.NewStubHTTPClient()
+	client.AddMapping("GET", "https://upstream.example.com/shadow/slow-todos/3",
+		web.NewStubHTTPResponse(200, `{"id": 3, "title": "live title", "completed": true}`))
+	driftStore := NewDriftStore()
+	player := NewConsumerExecutor(config, scenarioRepository, fixtureRepository, groupConfigRepository)
+	player.EnableShadow(client, driftStore)
+
+	// WHEN executing request
+	u, err := url.Parse("http://localhost:8080/shadow/slow-todos/3")
+	require.NoError(t, err)
+	ctx := web.NewStubContext(&http.Request{Method: "GET", URL: u, Header: http.Header{}})
+	started := time.Now()
+	err = player.Execute(ctx)
+
+	// THEN upstream response should be served without the mock delay
+	require.NoError(t, err)
+	require.True(t, time.Since(started) < time.Second, time.Since(started).String())
+	require.Contains(t, string(ctx.Result.([]byte)), "live title")
+	// AND mock response should still be compared
+	require.Len(t, driftStore.List("shadow", false), 1)
+}
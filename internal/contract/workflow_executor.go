@@ -0,0 +1,269 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/fuzz"
+	"github.com/bhatti/api-mock-service/internal/metrics"
+	"github.com/bhatti/api-mock-service/internal/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// ExecuteWorkflow executes setup steps in order, then steps of the workflow as a dependency graph with
+// independent branches in parallel and finally teardown steps that always run. Variables extracted from
+// responses of a step are available to the steps that run after it.
+func (px *ProducerExecutor) ExecuteWorkflow(
+	ctx context.Context,
+	req *http.Request,
+	workflow *types.Workflow,
+	dataTemplate fuzz.DataTemplateRequest,
+	contractReq *types.ProducerContractRequest,
+) *types.ProducerContractResponse {
+	started := time.Now()
+	contractResponse := types.NewProducerContractResponse()
+	if err := workflow.Validate(); err != nil {
+		contractResponse.Add(workflow.Group+"_workflow", nil, err)
+		return contractResponse
+	}
+	log.WithFields(log.Fields{
+		"Component":               "ProducerExecutor",
+		"Group":                   workflow.Group,
+		"Workflow":                workflow.Name,
+		"ProducerContractRequest": contractReq.String(),
+		"Steps":                   len(workflow.Steps),
+	}).Infof("execute-workflow BEGIN")
+
+	run := &workflowRun{
+		px:               px,
+		ctx:              ctx,
+		req:              req,
+		dataTemplate:     dataTemplate,
+		contractReq:      contractReq,
+		contractResponse: contractResponse,
+		sli:              metrics.NewMetrics(),
+		scenarioKeys:     make(map[string]*types.APIKeyData),
+		variables:        make(map[string]any),
+		results:          make(map[string]*types.WorkflowStepResult),
+	}
+	for _, scenarioKey := range px.scenarioRepository.LookupAllByGroup(workflow.Group) {
+		run.scenarioKeys[scenarioKey.Name] = scenarioKey
+		run.sli.RegisterHistogram(scenarioKey.SafeName())
+	}
+	for k, v := range workflow.Variables {
+		run.variables[k] = v
+	}
+
+	setupPassed := true
+	for _, step := range workflow.Setup {
+		if setupPassed {
+			setupPassed = run.executeStep(step, types.WorkflowSetup).Passed()
+		} else {
+			run.skip(step, types.WorkflowSetup, "earlier setup step failed")
+		}
+	}
+	if setupPassed {
+		run.executeGraph(workflow.Steps)
+	} else {
+		for _, step := range workflow.Steps {
+			run.skip(step, types.WorkflowStep, "setup failed")
+		}
+	}
+	for _, step := range workflow.Teardown {
+		run.executeStep(step, types.WorkflowTeardown)
+	}
+
+	for _, step := range workflow.AllSteps() {
+		contractResponse.Workflow = append(contractResponse.Workflow, run.results[step.Name])
+	}
+	contractResponse.Metrics = run.sli.Summary()
+	px.recordRun(workflow.Group, types.RunKindWorkflow, started, contractReq, contractResponse)
+	log.WithFields(log.Fields{
+		"Component":               "ProducerExecutor",
+		"Group":                   workflow.Group,
+		"Workflow":                workflow.Name,
+		"ProducerContractRequest": contractReq.String(),
+		"Elapsed":                 time.Since(started).String(),
+		"Errors":                  len(contractResponse.Errors),
+		"Metrics":                 contractResponse.Metrics,
+	}).Infof("execute-workflow COMPLETED")
+	return contractResponse
+}
+
+// workflowRun holds state of a workflow execution shared by its steps
+type workflowRun struct {
+	px               *ProducerExecutor
+	ctx              context.Context
+	req              *http.Request
+	dataTemplate     fuzz.DataTemplateRequest
+	contractReq      *types.ProducerContractRequest
+	contractResponse *types.ProducerContractResponse
+	sli              *metrics.Metrics
+	scenarioKeys     map[string]*types.APIKeyData
+	lock             sync.Mutex
+	variables        map[string]any
+	results          map[string]*types.WorkflowStepResult
+}
+
+// executeGraph starts each step as soon as all of its dependencies passed and skips steps whose
+// dependencies failed or were skipped. At most contractReq.Concurrency steps run at the same time.
+func (wr *workflowRun) executeGraph(steps []*types.WorkflowStepDefinition) {
+	limit := wr.contractReq.Concurrency
+	if limit < 1 {
+		limit = len(steps)
+	}
+	done := make(chan *types.WorkflowStepDefinition)
+	pending := steps
+	running := 0
+	for len(pending) > 0 || running > 0 {
+		next := make([]*types.WorkflowStepDefinition, 0, len(pending))
+		progressed := false
+		for _, step := range pending {
+			ready, skipReason := wr.dependencyState(step)
+			switch {
+			case ready && skipReason != "":
+				wr.skip(step, types.WorkflowStep, skipReason)
+				progressed = true
+			case ready && running < limit:
+				running++
+				progressed = true
+				go func(step *types.WorkflowStepDefinition) {
+					wr.executeStep(step, types.WorkflowStep)
+					done <- step
+				}(step)
+			default:
+				next = append(next, step)
+			}
+		}
+		pending = next
+		if running == 0 {
+			if !progressed {
+				// unreachable for validated workflows
+				return
+			}
+			continue
+		}
+		<-done
+		running--
+	}
+}
+
+// dependencyState returns true if all dependencies of the step completed along with reason to skip the step
+// if any of them didn't pass
+func (wr *workflowRun) dependencyState(step *types.WorkflowStepDefinition) (bool, string) {
+	wr.lock.Lock()
+	defer wr.lock.Unlock()
+	for _, dep := range step.DependsOn {
+		result := wr.results[dep]
+		if result == nil {
+			return false, ""
+		}
+		if !result.Passed() {
+			return true, fmt.Sprintf("dependency %s %s", dep, result.Status)
+		}
+	}
+	return true, ""
+}
+
+// executeStep executes scenario of the step with workflow variables and extracts variables from its response
+func (wr *workflowRun) executeStep(step *types.WorkflowStepDefinition, phase string) *types.WorkflowStepResult {
+	result := &types.WorkflowStepResult{
+		Name:      step.Name,
+		Scenario:  step.Scenario,
+		Phase:     phase,
+		StartedAt: time.Now().UTC(),
+	}
+	err := wr.executeScenario(step, result)
+	result.LatencyMillis = time.Since(result.StartedAt).Milliseconds()
+	if err != nil {
+		result.Status = types.OutcomeFailed
+		result.Error = err.Error()
+	} else if wr.contractResponse.IsFlaky(step.Name) {
+		result.Status = types.OutcomeFlaky
+	} else {
+		result.Status = types.OutcomePassed
+	}
+	log.WithFields(log.Fields{
+		"Component": "ProducerExecutor",
+		"Step":      step.Name,
+		"Phase":     phase,
+		"Scenario":  step.Scenario,
+		"Status":    result.Status,
+		"Error":     err,
+	}).Debugf("executed workflow step")
+
+	wr.lock.Lock()
+	defer wr.lock.Unlock()
+	for k, v := range result.Extracted {
+		wr.variables[k] = v
+	}
+	wr.results[step.Name] = result
+	return result
+}
+
+func (wr *workflowRun) executeScenario(step *types.WorkflowStepDefinition, result *types.WorkflowStepResult) error {
+	scenarioKey := wr.scenarioKeys[step.Scenario]
+	if scenarioKey == nil {
+		err := fmt.Errorf("scenario %s of workflow step %s is not found", step.Scenario, step.Name)
+		wr.contractResponse.Add(step.Name, nil, err)
+		return err
+	}
+	stepReq := wr.contractReq.Clone()
+	wr.lock.Lock()
+	for k, v := range wr.variables {
+		stepReq.Params[k] = v
+	}
+	wr.lock.Unlock()
+	for k, v := range step.Variables {
+		stepReq.Params[k] = v
+	}
+	if stepReq.MatchResponseCode > 0 {
+		keyData := *scenarioKey
+		keyData.Response = types.APIResponseKey{StatusCode: stepReq.MatchResponseCode}
+		scenarioKey = &keyData
+	}
+	scenario, err := wr.px.scenarioRepository.Lookup(scenarioKey, stepReq.Overrides())
+	if err != nil {
+		err = fmt.Errorf("failed to lookup scenario %s of workflow step %s due to %w", step.Scenario, step.Name, err)
+		wr.contractResponse.Add(step.Name, nil, err)
+		return err
+	}
+	if len(step.Assertions) > 0 {
+		scenario.Response.Assertions = append(
+			append([]string{}, scenario.Response.Assertions...), step.Assertions...)
+	}
+	verify := func(resContents any) error {
+		extracted := make(map[string]any)
+		for name, path := range step.Extract {
+			value := fuzz.ExtractJSONPath(path, resContents)
+			if value == nil {
+				return fmt.Errorf("failed to extract %s from %s of workflow step %s", name, path, step.Name)
+			}
+			extracted[name] = value
+		}
+		if len(extracted) > 0 {
+			result.Extracted = extracted
+		}
+		return nil
+	}
+	_, err = wr.px.executeWithRetry(wr.ctx, wr.req, step.Name, scenario, stepReq, wr.contractResponse,
+		wr.dataTemplate, wr.sli, verify)
+	return err
+}
+
+// skip records step as skipped without executing it
+func (wr *workflowRun) skip(step *types.WorkflowStepDefinition, phase string, reason string) {
+	wr.lock.Lock()
+	defer wr.lock.Unlock()
+	wr.results[step.Name] = &types.WorkflowStepResult{
+		Name:      step.Name,
+		Scenario:  step.Scenario,
+		Phase:     phase,
+		Status:    types.OutcomeSkipped,
+		Error:     reason,
+		StartedAt: time.Now().UTC(),
+	}
+}
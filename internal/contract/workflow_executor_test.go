@@ -0,0 +1,178 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/fuzz"
+	"github.com/bhatti/api-mock-service/internal/repository"
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/bhatti/api-mock-service/internal/web"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldExecuteWorkflowAsDependencyGraph(t *testing.T) {
+	// GIVEN a producer that creates orders and tracks called paths
+	var lock sync.Mutex
+	calls := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/login":
+			_, _ = w.Write([]byte(`{"session": {"token": "abc"}}`))
+		case r.URL.Path == "/orders" && r.Method == http.MethodPost:
+			_, _ = w.Write([]byte(`{"order": {"id": 42, "status": "created"}}`))
+		case r.URL.Path == "/orders/42" && r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"id": 42, "status": "created"}`))
+		case r.URL.Path == "/orders/42/ship":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"error": "out of stock"}`))
+		default:
+			_, _ = w.Write([]byte(`{"ok": true}`))
+		}
+	}))
+	defer server.Close()
+
+	// AND scenarios of a group
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	group := fmt.Sprintf("workflow_group_%d", time.Now().UnixNano())
+	for _, scenario := range []*types.APIScenario{
+		newWorkflowTestScenario(group, "login", types.Post, "/login"),
+		newWorkflowTestScenario(group, "create_order", types.Post, "/orders"),
+		newWorkflowTestScenario(group, "get_order", types.Get, "/orders/{order_id}"),
+		newWorkflowTestScenario(group, "ship_order", types.Post, "/orders/{order_id}/ship"),
+		newWorkflowTestScenario(group, "notify", types.Post, "/orders/{order_id}/notify"),
+		newWorkflowTestScenario(group, "list_products", types.Get, "/products"),
+		newWorkflowTestScenario(group, "delete_order", types.Delete, "/orders/{order_id}"),
+	} {
+		require.NoError(t, scenarioRepository.Save(scenario))
+	}
+
+	// AND a workflow with setup, dependencies, extractions, assertions and teardown
+	workflow := &types.Workflow{
+		Name:  "orders",
+		Group: group,
+		Setup: []*types.WorkflowStepDefinition{
+			{Name: "login", Scenario: "login", Extract: map[string]string{"token": "$.session.token"}},
+		},
+		Steps: []*types.WorkflowStepDefinition{
+			{Name: "create", Scenario: "create_order", Extract: map[string]string{"order_id": "$.order.id"}},
+			{Name: "get", Scenario: "get_order", DependsOn: []string{"create"},
+				Assertions: []string{"PropertyContains contents.status created"}},
+			{Name: "ship", Scenario: "ship_order", DependsOn: []string{"create"}},
+			{Name: "notify", Scenario: "notify", DependsOn: []string{"ship", "get"}},
+			{Name: "products", Scenario: "list_products"},
+		},
+		Teardown: []*types.WorkflowStepDefinition{
+			{Name: "cleanup", Scenario: "delete_order"},
+		},
+	}
+	client := web.NewHTTPClient(config, web.NewAuthAdapter(config))
+	executor := NewProducerExecutor(scenarioRepository, groupConfigRepository, client)
+	dataTemplate := fuzz.NewDataTemplateRequest(false, 1, 2)
+
+	// WHEN executing the workflow
+	contractReq := types.NewProducerContractRequest(server.URL, 1, 0)
+	res := executor.ExecuteWorkflow(context.Background(), &http.Request{}, workflow, dataTemplate, contractReq)
+
+	// THEN results should be reported per step in definition order
+	statuses := make(map[string]string)
+	names := make([]string, 0)
+	for _, result := range res.Workflow {
+		statuses[result.Name] = result.Status
+		names = append(names, result.Name)
+	}
+	require.Equal(t, []string{"login", "create", "get", "ship", "notify", "products", "cleanup"}, names)
+	require.Equal(t, types.OutcomePassed, statuses["login"])
+	require.Equal(t, types.OutcomePassed, statuses["create"])
+	require.Equal(t, types.OutcomePassed, statuses["get"])
+	require.Equal(t, types.OutcomeFailed, statuses["ship"])
+	require.Equal(t, types.OutcomePassed, statuses["products"])
+	require.Equal(t, types.WorkflowTeardown, res.Workflow[6].Phase)
+
+	// AND dependents of the failed step should be skipped without executing
+	require.Equal(t, types.OutcomeSkipped, statuses["notify"])
+	require.Contains(t, res.Workflow[4].Error, "ship")
+	require.Equal(t, 5, res.Succeeded)
+	require.Equal(t, 1, res.Failed)
+
+	// AND extracted variables should be used by later steps and teardown
+	require.Equal(t, "abc", res.Workflow[0].Extracted["token"])
+	require.EqualValues(t, 42, res.Workflow[1].Extracted["order_id"])
+	require.Contains(t, calls, "GET /orders/42")
+	require.Contains(t, calls, "DELETE /orders/42")
+	require.Equal(t, "POST /login", calls[0])
+	require.Equal(t, "DELETE /orders/42", calls[len(calls)-1])
+	for _, call := range calls {
+		require.False(t, strings.HasSuffix(call, "/notify"))
+	}
+}
+
+func Test_ShouldFailWorkflowStepWithFailedAssertionOrExtraction(t *testing.T) {
+	// GIVEN a producer
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 1, "status": "pending"}`))
+	}))
+	defer server.Close()
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	group := fmt.Sprintf("workflow_group_%d", time.Now().UnixNano())
+	require.NoError(t, scenarioRepository.Save(newWorkflowTestScenario(group, "get_order", types.Get, "/orders/1")))
+	executor := NewProducerExecutor(scenarioRepository, groupConfigRepository,
+		web.NewHTTPClient(config, web.NewAuthAdapter(config)))
+
+	// AND a workflow with unmet assertion, missing field and unknown scenario
+	workflow := &types.Workflow{
+		Group: group,
+		Steps: []*types.WorkflowStepDefinition{
+			{Name: "assert", Scenario: "get_order", Assertions: []string{"PropertyContains contents.status shipped"}},
+			{Name: "extract", Scenario: "get_order", Extract: map[string]string{"tracking": "$.tracking.id"}},
+			{Name: "missing", Scenario: "unknown"},
+		},
+	}
+
+	// WHEN executing the workflow
+	res := executor.ExecuteWorkflow(context.Background(), &http.Request{}, workflow,
+		fuzz.NewDataTemplateRequest(false, 1, 2), types.NewProducerContractRequest(server.URL, 1, 0))
+
+	// THEN each step should fail with its reason
+	require.Len(t, res.Workflow, 3)
+	for _, result := range res.Workflow {
+		require.Equal(t, types.OutcomeFailed, result.Status, result.Name)
+	}
+	require.Contains(t, res.Workflow[1].Error, "tracking")
+	require.Contains(t, res.Workflow[2].Error, "not found")
+	require.Equal(t, 3, res.Failed)
+
+	// AND invalid workflow should not execute
+	workflow.Steps[0].DependsOn = []string{"extract"}
+	workflow.Steps[1].DependsOn = []string{"assert"}
+	res = executor.ExecuteWorkflow(context.Background(), &http.Request{}, workflow,
+		fuzz.NewDataTemplateRequest(false, 1, 2), types.NewProducerContractRequest(server.URL, 1, 0))
+	require.Equal(t, 1, res.Failed)
+	require.Contains(t, res.Errors[group+"_workflow"], "cycle")
+}
+
+func newWorkflowTestScenario(group string, name string, method types.MethodType, path string) *types.APIScenario {
+	scenario := newParallelTestScenario(group, path, 0)
+	scenario.Name = name
+	scenario.Method = method
+	return scenario
+}
@@ -1,6 +1,9 @@
 package controller
 
 import (
+	"net/http"
+	"strings"
+
 	"github.com/bhatti/api-mock-service/internal/proxy"
 	"github.com/bhatti/api-mock-service/internal/web"
 )
@@ -22,6 +25,8 @@ func NewAPIProxyController(
 	webserver.PUT("/_proxy", ctrl.putAPIProxy)
 	webserver.POST("/_proxy", ctrl.postAPIProxy)
 	webserver.DELETE("/_proxy", ctrl.deleteAPIProxy)
+	webserver.GET("/_proxy/recording/skipped", ctrl.getSkippedRecordings)
+	webserver.GET("/_proxy/ca.pem", ctrl.getProxyCACert)
 	return ctrl
 }
 
@@ -54,3 +59,53 @@ func (msc *APIProxyController) getAPIProxy(c web.APIContext) (err error) {
 func (msc *APIProxyController) deleteAPIProxy(c web.APIContext) (err error) {
 	return msc.recorder.Handle(c)
 }
+
+// swagger:route GET /_proxy/recording/skipped api-proxy getSkippedRecordings
+// Returns counts of proxied requests that were not recorded by name of recording filter rule.
+// responses:
+//
+//	200: skippedRecordingsResponse
+func (msc *APIProxyController) getSkippedRecordings(c web.APIContext) (err error) {
+	return c.JSON(http.StatusOK, proxy.SkippedRecordings())
+}
+
+// swagger:route GET /_proxy/ca.pem api-proxy getProxyCACert
+// Returns public certificate of the proxy CA in PEM format or DER format with format=der, or 404 if the
+// proxy hasn't generated the CA yet.
+// responses:
+//
+//	200: proxyCACertResponse
+func (msc *APIProxyController) getProxyCACert(c web.APIContext) (err error) {
+	ca, err := msc.recorder.CertificateAuthority()
+	if err != nil {
+		return web.HandleError(c, err)
+	}
+	if strings.EqualFold(c.QueryParam("format"), "der") {
+		c.Response().Header().Set("Content-Disposition", `attachment; filename="api-mock-service-ca.der"`)
+		return c.Blob(http.StatusOK, "application/x-x509-ca-cert", ca.DER())
+	}
+	return c.Blob(http.StatusOK, "application/x-pem-file", ca.PEM())
+}
+
+// ********************************* Swagger types ***********************************
+
+// swagger:parameters getProxyCACert
+// The params for proxy CA certificate
+type proxyCACertParams struct {
+	// in:query
+	Format string `json:"format"`
+}
+
+// Public certificate of the proxy CA
+// swagger:response proxyCACertResponse
+type proxyCACertResponseBody struct {
+	// in:body
+	Body []byte
+}
+
+// Counts of skipped recordings by filter rule
+// swagger:response skippedRecordingsResponse
+type skippedRecordingsResponseBody struct {
+	// in:body
+	Body map[string]int64
+}
@@ -2,9 +2,12 @@ package controller
 
 import (
 	"bytes"
+	"encoding/pem"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -193,3 +196,83 @@ func Test_ShouldRecordPutProxyRequests(t *testing.T) {
 	saved := ctx.Result.([]byte)
 	require.Equal(t, resBody, string(saved))
 }
+
+func Test_ShouldGetSkippedRecordings(t *testing.T) {
+	config := types.BuildTestConfig()
+	config.RecordingFilter = types.RecordingFilterConfig{
+		Include: []*types.RecordingFilterRule{{Hosts: []string{"api.example.com"}}},
+	}
+	// GIVEN repository and controller with recording filter
+	mockScenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	client := web.NewStubHTTPClient()
+	client.AddMapping("GET", "https://jsonplaceholder.typicode.com/todos/11", web.NewStubHTTPResponse(200, "{}"))
+	recorder := proxy.NewRecorder(config, client, mockScenarioRepository, groupConfigRepository)
+	webServer := web.NewStubWebServer()
+	ctrl := NewAPIProxyController(recorder, webServer)
+	_ = skippedRecordingsResponseBody{}
+	u, err := url.Parse("http://localhost:8080")
+	require.NoError(t, err)
+	err = ctrl.getAPIProxy(web.NewStubContext(&http.Request{
+		URL:    u,
+		Method: "GET",
+		Header: map[string][]string{
+			types.MockURL: {"https://jsonplaceholder.typicode.com/todos/11"},
+		},
+	}))
+	require.NoError(t, err)
+	ctx := web.NewStubContext(&http.Request{Method: "GET", URL: u})
+
+	// WHEN fetching skipped recordings
+	err = ctrl.getSkippedRecordings(ctx)
+
+	// THEN it should return counts by rule
+	require.NoError(t, err)
+	counts := ctx.Result.(map[string]int64)
+	require.True(t, counts[types.RecordingSkipInclude] > 0)
+}
+
+func Test_ShouldGetProxyCACert(t *testing.T) {
+	_ = proxyCACertParams{}
+	_ = proxyCACertResponseBody{}
+	config := types.BuildTestConfig()
+	config.DataDir = t.TempDir()
+	// GIVEN repository and controller for proxy
+	mockScenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	recorder := proxy.NewRecorder(config, web.NewStubHTTPClient(), mockScenarioRepository, groupConfigRepository)
+	ctrl := NewAPIProxyController(recorder, web.NewStubWebServer())
+
+	// WHEN fetching CA certificate before proxy generated it
+	ctx := web.NewStubContext(&http.Request{Method: "GET", URL: &url.URL{Path: "/_proxy/ca.pem"}})
+	err = ctrl.getProxyCACert(ctx)
+
+	// THEN it should return not found without generating CA
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "404")
+	_, err = os.Stat(filepath.Join(proxy.CADir(config), proxy.CACertFile))
+	require.True(t, os.IsNotExist(err))
+
+	// WHEN fetching CA certificate in PEM format after proxy generated it
+	_, err = proxy.LoadOrCreateCertificateAuthority(config)
+	require.NoError(t, err)
+	err = ctrl.getProxyCACert(ctx)
+
+	// THEN it should return PEM certificate
+	require.NoError(t, err)
+	pemBytes := ctx.Result.([]byte)
+	require.Contains(t, string(pemBytes), "BEGIN CERTIFICATE")
+
+	// WHEN fetching CA certificate in DER format
+	ctx.Params["format"] = "der"
+	err = ctrl.getProxyCACert(ctx)
+
+	// THEN it should return DER of same certificate
+	require.NoError(t, err)
+	block, _ := pem.Decode(pemBytes)
+	require.Equal(t, block.Bytes, ctx.Result.([]byte))
+}
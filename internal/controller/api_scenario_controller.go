@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/bhatti/api-mock-service/internal/repository"
 	"github.com/bhatti/api-mock-service/internal/types"
 	"github.com/bhatti/api-mock-service/internal/web"
 	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
 )
 
 // APIScenarioController structure
@@ -32,8 +34,10 @@ func NewAPIScenarioController(
 	webserver.GET("/_scenarios", ctrl.listAPIScenarioPaths)
 	webserver.GET("/_scenarios/:method/names/:path", ctrl.getAPIScenarioNames)
 	webserver.GET("/_scenarios/groups", ctrl.getAPIGroups)
+	webserver.GET("/_scenarios/groups/:group/statemachine", ctrl.getStateMachine)
 	webserver.GET("/_scenarios/:method/:name/:path", ctrl.getAPIScenario)
 	webserver.POST("/_scenarios", ctrl.postMockScenario)
+	webserver.POST("/_scenarios/consolidate", ctrl.postConsolidateScenarios)
 	webserver.DELETE("/_scenarios/:method/:name/:path", ctrl.deleteAPIScenario)
 	return ctrl
 }
@@ -143,6 +147,66 @@ func (msc *APIScenarioController) getAPIGroups(c web.APIContext) error {
 	return c.JSON(http.StatusOK, groups)
 }
 
+// getStateMachine handler
+// swagger:route GET /_scenarios/groups/{group}/statemachine api-scenarios getStateMachine
+// Builds state machine diagram from transitions of all scenarios in the group using mermaid, dot or json format.
+// responses:
+//
+//	200: stateMachineResponse
+func (msc *APIScenarioController) getStateMachine(c web.APIContext) error {
+	group := c.Param("group")
+	if group == "" {
+		return fmt.Errorf("scenario group not specified in %s", c.Request().URL)
+	}
+	scenarios := make([]*types.APIScenario, 0)
+	for _, keyData := range msc.scenarioRepository.ListScenarioKeyData(group) {
+		b, err := msc.scenarioRepository.LoadRaw(keyData.Method, keyData.Name, keyData.Path)
+		if err != nil {
+			return err
+		}
+		scenario := &types.APIScenario{}
+		if err = yaml.Unmarshal(b, scenario); err != nil {
+			return fmt.Errorf("failed to unmarshal scenario %s due to %w", keyData.Name, err)
+		}
+		scenarios = append(scenarios, scenario)
+	}
+	graph := types.BuildStateMachineGraph(group, scenarios)
+	switch c.QueryParam("format") {
+	case "", "mermaid":
+		return c.String(http.StatusOK, graph.ToMermaid())
+	case "dot":
+		return c.String(http.StatusOK, graph.ToDot())
+	case "json":
+		return c.JSON(http.StatusOK, graph)
+	default:
+		return fmt.Errorf("unsupported state machine format %s, use mermaid, dot or json", c.QueryParam("format"))
+	}
+}
+
+// postConsolidateScenarios handler
+// swagger:route POST /_scenarios/consolidate api-scenarios postConsolidateScenarios
+// Collapses recorded scenarios with same method, status and path shape into parameterized templates.
+// Templates are only saved and recorded scenarios removed when apply is true.
+// responses:
+//
+//	200: consolidationResponse
+func (msc *APIScenarioController) postConsolidateScenarios(c web.APIContext) error {
+	minSize := types.DefaultConsolidationMinSize
+	if c.QueryParam("min_size") != "" {
+		size, err := strconv.Atoi(c.QueryParam("min_size"))
+		if err != nil {
+			return fmt.Errorf("invalid min_size %s due to %w", c.QueryParam("min_size"), err)
+		}
+		minSize = size
+	}
+	report, err := repository.ConsolidateScenarios(
+		msc.scenarioRepository, c.QueryParam("group"), minSize, c.QueryParam("apply") == "true")
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
 // swagger:route GET /_scenarios/{method}/names/{path} api-scenarios getAPIScenarioNames
 // Finds api scenario names by method and path.
 // responses:
@@ -249,6 +313,40 @@ type apiScenarioIDParams struct {
 	Path string `json:"path"`
 }
 
+// APIScenario state machine graph
+// swagger:response stateMachineResponse
+type stateMachineResponseBody struct {
+	// in:body
+	Body types.StateMachineGraph
+}
+
+// swagger:parameters getStateMachine
+// The parameters for building state machine of a group
+type stateMachineParams struct {
+	// in:path
+	Group string `json:"group"`
+	// in:query
+	Format string `json:"format"`
+}
+
+// Consolidated templates with source scenarios
+// swagger:response consolidationResponse
+type consolidationResponseBody struct {
+	// in:body
+	Body types.ConsolidationReport
+}
+
+// swagger:parameters postConsolidateScenarios
+// The parameters for consolidating recorded scenarios
+type consolidationParams struct {
+	// in:query
+	Group string `json:"group"`
+	// in:query
+	MinSize int `json:"min_size"`
+	// in:query
+	Apply bool `json:"apply"`
+}
+
 // swagger:parameters getAPIScenarioNames
 // The parameters for finding api-scenario names by path and method
 type apiNamesParams struct {
@@ -25,6 +25,10 @@ func Test_InitializeSwaggerStructsForMockScenarioController(t *testing.T) {
 	_ = apiScenarioResponseBody{}
 	_ = apiScenarioIDParams{}
 	_ = apiScenarioPathsResponseBody{}
+	_ = stateMachineResponseBody{}
+	_ = stateMachineParams{}
+	_ = consolidationResponseBody{}
+	_ = consolidationParams{}
 }
 
 func Test_ShouldFailPostScenarioWithoutMethodNameOrPath(t *testing.T) {
@@ -117,6 +121,112 @@ func Test_ShouldGetScenarioGroups(t *testing.T) {
 	require.True(t, len(groups) > 0)
 }
 
+func Test_ShouldGetStateMachineForGroup(t *testing.T) {
+	config := types.BuildTestConfig()
+	// GIVEN repository and controller for mock scenario
+	mockScenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	oapiRepository, err := repository.NewFileOAPIRepository(config)
+	require.NoError(t, err)
+	webServer := web.NewStubWebServer()
+	ctrl := NewAPIScenarioController(mockScenarioRepository, oapiRepository, webServer)
+	// AND scenarios with state machines
+	for _, next := range []struct {
+		name   string
+		method types.MethodType
+		from   string
+		to     string
+	}{{"sm-graph-create", types.Post, "new", "created"}, {"sm-graph-ship", types.Put, "paid", "shipped"}} {
+		scenario := &types.APIScenario{
+			Method:   next.method,
+			Name:     next.name,
+			Path:     "/api/sm-graph/orders",
+			Group:    "sm-graph-group",
+			Response: types.APIResponse{StatusCode: 200},
+			StateMachine: &types.ScenarioStateMachine{
+				Transitions: []types.StateTransition{{From: next.from, To: next.to}},
+			},
+		}
+		if next.from == "new" {
+			scenario.StateMachine.InitialState = "new"
+		}
+		require.NoError(t, mockScenarioRepository.Save(scenario))
+	}
+	u, err := url.Parse("http://localhost:8080/_scenarios/groups/sm-graph-group/statemachine")
+	require.NoError(t, err)
+
+	// WHEN getting state machine without group
+	ctx := web.NewStubContext(&http.Request{URL: u})
+	err = ctrl.getStateMachine(ctx)
+	// THEN it should fail
+	require.Error(t, err)
+
+	// WHEN getting state machine as mermaid
+	ctx.Params["group"] = "sm-graph-group"
+	err = ctrl.getStateMachine(ctx)
+	// THEN it should return diagram
+	require.NoError(t, err)
+	require.Contains(t, ctx.Result.(string), "new --> created : POST /api/sm-graph/orders 200")
+
+	// WHEN getting state machine as json
+	ctx.Params["format"] = "json"
+	err = ctrl.getStateMachine(ctx)
+	// THEN it should flag unreachable states
+	require.NoError(t, err)
+	graph := ctx.Result.(*types.StateMachineGraph)
+	require.Equal(t, []string{"paid", "shipped"}, graph.UnreachableStates)
+
+	// WHEN getting state machine with unknown format
+	ctx.Params["format"] = "svg"
+	err = ctrl.getStateMachine(ctx)
+	// THEN it should fail
+	require.Error(t, err)
+}
+
+func Test_ShouldConsolidateScenarios(t *testing.T) {
+	config := types.BuildTestConfig()
+	// GIVEN repository and controller for mock scenario
+	mockScenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	oapiRepository, err := repository.NewFileOAPIRepository(config)
+	require.NoError(t, err)
+	webServer := web.NewStubWebServer()
+	ctrl := NewAPIScenarioController(mockScenarioRepository, oapiRepository, webServer)
+	// AND recorded scenarios for different ids
+	for i := 1; i <= 2; i++ {
+		require.NoError(t, mockScenarioRepository.Save(&types.APIScenario{
+			Method:   types.Get,
+			Name:     fmt.Sprintf("recorded-ctrl-consolidate-%d", i),
+			Path:     fmt.Sprintf("/api/ctrl-consolidate/users/%d", i),
+			Group:    "ctrl-consolidate",
+			Response: types.APIResponse{StatusCode: 200, Contents: fmt.Sprintf(`{"id": %d}`, i)},
+		}))
+	}
+	u, err := url.Parse("http://localhost:8080/_scenarios/consolidate")
+	require.NoError(t, err)
+	ctx := web.NewStubContext(&http.Request{Method: "POST", URL: u})
+
+	// WHEN consolidating with invalid min size
+	ctx.Params["min_size"] = "x"
+	err = ctrl.postConsolidateScenarios(ctx)
+	// THEN it should fail
+	require.Error(t, err)
+
+	// WHEN consolidating scenarios of group as dry run
+	ctx.Params["min_size"] = "2"
+	ctx.Params["group"] = "ctrl-consolidate"
+	err = ctrl.postConsolidateScenarios(ctx)
+
+	// THEN it should return templates without applying them
+	require.NoError(t, err)
+	report := ctx.Result.(*types.ConsolidationReport)
+	require.False(t, report.Applied)
+	require.Equal(t, 2, report.Scanned)
+	require.Len(t, report.Clusters, 1)
+	require.Equal(t, "/api/ctrl-consolidate/users/:id", report.Clusters[0].Path)
+	require.Equal(t, []string{"response.id"}, report.Clusters[0].GeneratedFields)
+}
+
 func Test_ShouldFailGetScenarioNamesWithoutMethodNameOrPath(t *testing.T) {
 	config := types.BuildTestConfig()
 	// GIVEN repository and controller for mock scenario
@@ -7,11 +7,13 @@ import (
 	"github.com/bhatti/api-mock-service/internal/contract"
 	"github.com/bhatti/api-mock-service/internal/fuzz"
 	"github.com/bhatti/api-mock-service/internal/oapi"
+	"github.com/bhatti/api-mock-service/internal/report"
 	"github.com/bhatti/api-mock-service/internal/types"
 	"github.com/bhatti/api-mock-service/internal/utils"
 	"github.com/bhatti/api-mock-service/internal/web"
 	log "github.com/sirupsen/logrus"
 	"net/http"
+	"strconv"
 )
 
 // ProducerContractController structure for producer driven contracts
@@ -34,6 +36,10 @@ func NewProducerContractController(
 	webserver.POST("/_contracts/:method/:name/:path", ctrl.postProducerContractScenarioByPath)
 	// W1: coverage endpoint
 	webserver.GET("/_coverage/:group", ctrl.getCoverageByGroup)
+	webserver.GET("/_contracts/runs", ctrl.getContractRuns)
+	webserver.GET("/_contracts/runs/compare", ctrl.compareContractRuns)
+	webserver.GET("/_contracts/runs/:id", ctrl.getContractRun)
+	webserver.GET("/_contracts/stats/:scenario", ctrl.getContractStats)
 	return ctrl
 }
 
@@ -42,6 +48,7 @@ func NewProducerContractController(
 // postProducerContractHistoryByGroup handler
 // swagger:route POST /_contracts/history/{group} producer-contract postProducerContractHistoryByGroup
 // Invokes service api-contract using executed history of consumer contracts.
+// Add ?report=junit|html|markdown|sarif to return a report instead of JSON.
 // responses:
 //
 //	200: apiScenarioContractResponse
@@ -54,7 +61,7 @@ func (mcc *ProducerContractController) postProducerContractHistoryByGroup(c web.
 	dataTemplate := fuzz.NewDataTemplateRequest(false, 1, 1)
 	exec := specAwareExecutor(mcc.executor, contractReq, dataTemplate)
 	res := exec.ExecuteByHistory(context.Background(), c.Request(), group, dataTemplate, contractReq)
-	return c.JSON(http.StatusOK, res)
+	return renderContractResponse(c, group, res)
 }
 
 // postProducerContractGroupScenario handler
@@ -62,6 +69,8 @@ func (mcc *ProducerContractController) postProducerContractHistoryByGroup(c web.
 // Invokes service api-contract by group of api contracts.
 // Optionally accepts spec_content (OpenAPI YAML/JSON) in the request body for response schema validation.
 // Set track_coverage:true to include a coverage report in the response.
+// Set load to run the group as load test; add ?format=prometheus to export the load report as Prometheus text.
+// Add ?report=junit|html|markdown|sarif to return a report instead of JSON.
 // responses:
 //
 //	200: apiScenarioContractResponse
@@ -77,13 +86,17 @@ func (mcc *ProducerContractController) postProducerContractGroupScenario(c web.A
 	dataTemplate := fuzz.NewDataTemplateRequest(false, 1, 1)
 	exec := specAwareExecutor(mcc.executor, contractReq, dataTemplate)
 	res := exec.ExecuteByGroup(context.Background(), c.Request(), group, dataTemplate, contractReq)
-	return c.JSON(http.StatusOK, res)
+	if res.Load != nil && c.QueryParam("format") == "prometheus" {
+		return c.Blob(http.StatusOK, "text/plain; version=0.0.4", []byte(res.Load.PrometheusText()))
+	}
+	return renderContractResponse(c, group, res)
 }
 
 // postProducerContractMutationsByGroup handler
 // swagger:route POST /_contracts/mutations/{group} producer-contract postProducerContractMutationsByGroup
 // Generates and executes mutation variants for all scenarios in a group to test API robustness.
 // Mutations include: null fields, boundary values, format violations (date/uuid/email), security payloads.
+// Add ?report=junit|html|markdown|sarif to return a report instead of JSON.
 // responses:
 //
 //	200: apiScenarioContractResponse
@@ -99,12 +112,13 @@ func (mcc *ProducerContractController) postProducerContractMutationsByGroup(c we
 	dataTemplate := fuzz.NewDataTemplateRequest(false, 1, 1)
 	exec := specAwareExecutor(mcc.executor, contractReq, dataTemplate)
 	res := exec.ExecuteMutationsByGroup(context.Background(), c.Request(), group, dataTemplate, contractReq)
-	return c.JSON(http.StatusOK, res)
+	return renderContractResponse(c, group+"-mutations", res)
 }
 
 // postProducerContractScenarioByPath handler
 // swagger:route POST /_contracts/{method}/{name}/{path} producer-contract postProducerContractScenarioByPath
 // Invokes service api-contract by method, contracts-name and path.
+// Add ?report=junit|html|markdown|sarif to return a report instead of JSON.
 // responses:
 //
 //	200: apiScenarioContractResponse
@@ -140,7 +154,7 @@ func (mcc *ProducerContractController) postProducerContractScenarioByPath(c web.
 	dataTemplate := fuzz.NewDataTemplateRequest(true, 1, 1)
 	exec := specAwareExecutor(mcc.executor, contractReq, dataTemplate)
 	res := exec.Execute(context.Background(), c.Request(), keyData, dataTemplate, contractReq)
-	return c.JSON(http.StatusOK, res)
+	return renderContractResponse(c, name, res)
 }
 
 // ********************************* Swagger types ***********************************
@@ -176,6 +190,68 @@ type postProducerContractHistoryParams struct {
 	Body types.ProducerContractRequest
 }
 
+// swagger:parameters getContractRuns
+type getContractRunsParams struct {
+	// in:query
+	Group string `json:"group"`
+	// in:query
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters getContractRun
+type getContractRunParams struct {
+	// in:path
+	ID string `json:"id"`
+}
+
+// swagger:parameters compareContractRuns
+type compareContractRunsParams struct {
+	// in:query
+	Base string `json:"base"`
+	// in:query
+	Head string `json:"head"`
+	// in:query
+	LatencyThreshold float64 `json:"latency_threshold"`
+}
+
+// swagger:parameters getContractStats
+type getContractStatsParams struct {
+	// in:path
+	Scenario string `json:"scenario"`
+	// in:query
+	Group string `json:"group"`
+	// in:query
+	Limit int `json:"limit"`
+}
+
+// Summaries of recorded contract runs
+// swagger:response contractRunsResponse
+type contractRunsResponseBody struct {
+	// in:body
+	Body []types.ContractRunSummary
+}
+
+// Recorded contract run
+// swagger:response contractRunResponse
+type contractRunResponseBody struct {
+	// in:body
+	Body types.ContractRun
+}
+
+// Comparison of contract runs
+// swagger:response contractRunComparisonResponse
+type contractRunComparisonResponseBody struct {
+	// in:body
+	Body types.ContractRunComparison
+}
+
+// Stats of scenario over recorded runs
+// swagger:response contractStatsResponse
+type contractStatsResponseBody struct {
+	// in:body
+	Body contract.ContractValidationStats
+}
+
 // APIScenario body for update
 // swagger:response apiScenarioContractResponse
 type apiScenarioContractResponseBody struct {
@@ -205,6 +281,85 @@ func (mcc *ProducerContractController) getCoverageByGroup(c web.APIContext) erro
 	return c.JSON(http.StatusOK, coverage)
 }
 
+// getContractRuns handler
+// swagger:route GET /_contracts/runs producer-contract getContractRuns
+// Returns summaries of most recent contract runs recorded with record_results, optionally by group and limit.
+// responses:
+//
+//	200: contractRunsResponse
+func (mcc *ProducerContractController) getContractRuns(c web.APIContext) error {
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	runs, err := mcc.executor.ListRuns(c.QueryParam("group"), limit)
+	if err != nil {
+		return err
+	}
+	res := make([]*types.ContractRunSummary, len(runs))
+	for i, run := range runs {
+		res[i] = run.Summary()
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// getContractRun handler
+// swagger:route GET /_contracts/runs/{id} producer-contract getContractRun
+// Returns recorded contract run with outcome of each scenario.
+// responses:
+//
+//	200: contractRunResponse
+func (mcc *ProducerContractController) getContractRun(c web.APIContext) error {
+	run, err := mcc.executor.LoadRun(c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, run)
+}
+
+// compareContractRuns handler
+// swagger:route GET /_contracts/runs/compare producer-contract compareContractRuns
+// Compares head run against base run and returns new failures, fixed scenarios and latency regressions
+// above latency_threshold percent (default 20).
+// responses:
+//
+//	200: contractRunComparisonResponse
+func (mcc *ProducerContractController) compareContractRuns(c web.APIContext) error {
+	base := c.QueryParam("base")
+	head := c.QueryParam("head")
+	if base == "" || head == "" {
+		return fmt.Errorf("base and head runs not specified")
+	}
+	threshold := 0.0
+	if c.QueryParam("latency_threshold") != "" {
+		var err error
+		if threshold, err = strconv.ParseFloat(c.QueryParam("latency_threshold"), 64); err != nil {
+			return fmt.Errorf("invalid latency_threshold %s due to %w", c.QueryParam("latency_threshold"), err)
+		}
+	}
+	res, err := mcc.executor.CompareRuns(base, head, threshold)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// getContractStats handler
+// swagger:route GET /_contracts/stats/{scenario} producer-contract getContractStats
+// Returns success rate, average latency, flakiness, trend and top 5 failures of a scenario over recorded runs.
+// responses:
+//
+//	200: contractStatsResponse
+func (mcc *ProducerContractController) getContractStats(c web.APIContext) error {
+	scenario := c.Param("scenario")
+	if scenario == "" {
+		return fmt.Errorf("scenario name not specified")
+	}
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	stats, err := mcc.executor.GetContractRunStats(c.QueryParam("group"), scenario, limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, stats)
+}
+
 // specAwareExecutor returns a spec-enhanced copy of the executor when SpecContent is provided
 // in the contract request. Otherwise the original executor is returned unchanged.
 // This is request-scoped so concurrent requests each get their own executor copy.
@@ -231,6 +386,20 @@ func specAwareExecutor(base *contract.ProducerExecutor, contractReq *types.Produ
 	return base.WithOpenAPISpec(doc, router)
 }
 
+// renderContractResponse returns contract response as JSON or in the junit, html, markdown or sarif format
+// of the report query parameter
+func renderContractResponse(c web.APIContext, suite string, res *types.ProducerContractResponse) error {
+	format := c.QueryParam("report")
+	if format == "" {
+		return c.JSON(http.StatusOK, res)
+	}
+	b, contentType, err := report.Render(format, suite, res)
+	if err != nil {
+		return err
+	}
+	return c.Blob(http.StatusOK, contentType, b)
+}
+
 func buildContractRequest(c web.APIContext) (*types.ProducerContractRequest, error) {
 	b, _, err := utils.ReadAll(c.Request().Body)
 	if err != nil {
@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/bhatti/api-mock-service/internal/repository"
 	"github.com/bhatti/api-mock-service/internal/types"
@@ -25,6 +26,71 @@ func Test_InitializeSwaggerStructsForMockContractScenarioController(t *testing.T
 	_ = apiScenarioContractResponseBody{}
 	_ = postProducerContractHistoryParams{}
 	_ = postProducerContractGroupScenarioParams{}
+	_ = getContractRunsParams{}
+	_ = getContractRunParams{}
+	_ = compareContractRunsParams{}
+	_ = getContractStatsParams{}
+	_ = contractRunsResponseBody{}
+	_ = contractRunResponseBody{}
+	_ = contractRunComparisonResponseBody{}
+	_ = contractStatsResponseBody{}
+}
+
+func Test_ShouldListCompareAndGetStatsOfContractRuns(t *testing.T) {
+	// GIVEN recorded runs and controller for producer contracts
+	config := types.BuildTestConfig()
+	mockScenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	runRepository, err := repository.NewFileContractRunRepository(&types.Configuration{DataDir: t.TempDir()})
+	require.NoError(t, err)
+	started := time.Now().UTC()
+	require.NoError(t, runRepository.Save(&types.ContractRun{ID: "run1", Group: "todos", Label: "v1", StartedAt: started,
+		Scenarios: map[string]*types.ScenarioOutcome{
+			"get_todo": {Executions: 1, Failed: 1, LatencyMillis: 20, Failures: []string{"status: expected 200, got 502"}}}}))
+	require.NoError(t, runRepository.Save(&types.ContractRun{ID: "run2", Group: "todos", Label: "v2", StartedAt: started.Add(time.Minute),
+		Scenarios: map[string]*types.ScenarioOutcome{"get_todo": {Executions: 1, Passed: 1, LatencyMillis: 20}}}))
+	executor := contract.NewProducerExecutor(mockScenarioRepository, groupConfigRepository, web.NewStubHTTPClient()).
+		WithRunRepository(runRepository)
+	ctrl := NewProducerContractController(executor, web.NewStubWebServer())
+
+	// WHEN listing runs of group
+	ctx := web.NewStubContext(&http.Request{})
+	ctx.Params["group"] = "todos"
+	require.NoError(t, ctrl.getContractRuns(ctx))
+	// THEN it should return most recent run first
+	runs := ctx.Result.([]*types.ContractRunSummary)
+	require.Len(t, runs, 2)
+	require.Equal(t, "run2", runs[0].ID)
+
+	// WHEN getting a run
+	ctx = web.NewStubContext(&http.Request{})
+	ctx.Params["id"] = "run1"
+	require.NoError(t, ctrl.getContractRun(ctx))
+	// THEN it should return outcome of scenarios
+	require.Equal(t, 1, ctx.Result.(*types.ContractRun).Scenarios["get_todo"].Failed)
+
+	// WHEN comparing runs
+	ctx = web.NewStubContext(&http.Request{})
+	ctx.Params["base"] = "run1"
+	ctx.Params["head"] = "run2"
+	require.NoError(t, ctrl.compareContractRuns(ctx))
+	// THEN fixed scenarios should be reported
+	require.Equal(t, []string{"get_todo"}, ctx.Result.(*types.ContractRunComparison).Fixed)
+
+	// WHEN getting stats of scenario
+	ctx = web.NewStubContext(&http.Request{})
+	ctx.Params["scenario"] = "get_todo"
+	require.NoError(t, ctrl.getContractStats(ctx))
+	// THEN it should return success rate and top failures
+	stats := ctx.Result.(*contract.ContractValidationStats)
+	require.Equal(t, 50.0, stats.SuccessRate)
+	require.Equal(t, []string{"status: expected 200, got 502 (1 times)"}, stats.Top5Failures)
+
+	// AND comparing without runs should fail
+	ctx = web.NewStubContext(&http.Request{})
+	require.Error(t, ctrl.compareContractRuns(ctx))
 }
 
 func Test_ShouldFailPostContractScenarioWithoutMethod(t *testing.T) {
@@ -311,6 +377,46 @@ func Test_ShouldPostContractScenarioWithGroup(t *testing.T) {
 	require.Equal(t, 0, len(res.Errors))
 }
 
+func Test_ShouldPostContractScenarioWithJUnitReport(t *testing.T) {
+	config := types.BuildTestConfig()
+	// GIVEN repository and controller for mock scenario
+	mockScenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(types.BuildTestConfig())
+	require.NoError(t, err)
+	// AND a valid scenario
+	scenario, err := saveTestScenario("../../fixtures/get_todo.yaml", mockScenarioRepository)
+	require.NoError(t, err)
+
+	client := web.NewStubHTTPClient()
+	client.AddMapping("GET", "https://localhost/todos/10", web.NewStubHTTPResponse(500, `{}`))
+	executor := contract.NewProducerExecutor(mockScenarioRepository, groupConfigRepository, client)
+	ctrl := NewProducerContractController(executor, web.NewStubWebServer())
+
+	data, err := json.Marshal(types.NewProducerContractRequest("https://localhost", 1, 0))
+	require.NoError(t, err)
+	u, err := url.Parse("http://localhost:8080/_contracts/todos?report=junit")
+	require.NoError(t, err)
+	ctx := web.NewStubContext(&http.Request{
+		Body:   io.NopCloser(bytes.NewReader(data)),
+		Method: "POST",
+		URL:    u,
+		Header: map[string][]string{},
+	})
+	ctx.Params["group"] = scenario.Group
+	ctx.Params["report"] = "junit"
+
+	// WHEN running contracts of group with junit report
+	err = ctrl.postProducerContractGroupScenario(ctx)
+
+	// THEN failed execution should be reported as junit test case
+	require.NoError(t, err)
+	junit := string(ctx.Result.([]byte))
+	require.Contains(t, junit, "<testsuites")
+	require.Contains(t, junit, `failures="1"`)
+	require.Contains(t, junit, "<failure ")
+}
+
 func Test_ShouldPostContractScenarioWithMethodNamePath(t *testing.T) {
 	config := types.BuildTestConfig()
 	// GIVEN repository and controller for mock scenario
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/bhatti/api-mock-service/internal/contract"
+	"github.com/bhatti/api-mock-service/internal/web"
+)
+
+// DriftController structure
+type DriftController struct {
+	driftStore *contract.DriftStore
+}
+
+// NewDriftController instantiates controller for drift report of shadow mode
+func NewDriftController(
+	driftStore *contract.DriftStore,
+	webserver web.Server) *DriftController {
+	ctrl := &DriftController{
+		driftStore: driftStore,
+	}
+
+	webserver.GET("/_drift", ctrl.getDrift)
+	webserver.DELETE("/_drift", ctrl.deleteDrift)
+	return ctrl
+}
+
+// ********************************* HTTP Handlers ***********************************
+
+// swagger:route GET /_drift drift getDrift
+// Returns scenarios whose mock response no longer matches the upstream response observed in shadow mode.
+// responses:
+//
+//	200: driftResponse
+func (dc *DriftController) getDrift(c web.APIContext) (err error) {
+	all := c.QueryParam("all") == "true"
+	return c.JSON(http.StatusOK, dc.driftStore.List(c.QueryParam("group"), !all))
+}
+
+// swagger:route DELETE /_drift drift deleteDrift
+// Clears drift observed in shadow mode for the group or all groups.
+// responses:
+//
+//	200: emptyResponse
+func (dc *DriftController) deleteDrift(c web.APIContext) (err error) {
+	dc.driftStore.Clear(c.QueryParam("group"))
+	return c.NoContent(http.StatusOK)
+}
+
+// ********************************* Swagger types ***********************************
+
+// swagger:parameters getDrift deleteDrift
+// The params for drift report
+type driftParams struct {
+	// in:query
+	Group string `json:"group"`
+	// in:query
+	All bool `json:"all"`
+}
+
+// Drift of scenarios observed in shadow mode
+// swagger:response driftResponse
+type driftResponseBody struct {
+	// in:body
+	Body []*contract.ScenarioDrift
+}
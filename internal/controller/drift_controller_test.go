@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/bhatti/api-mock-service/internal/contract"
+	"github.com/bhatti/api-mock-service/internal/web"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldGetAndClearDrift(t *testing.T) {
+	_ = driftParams{}
+	_ = driftResponseBody{}
+	// GIVEN drift store with drifted and matching scenarios
+	driftStore := contract.NewDriftStore()
+	driftStore.Record(&contract.ScenarioDrift{Group: "todos", ScenarioName: "get-todo", Drifted: true})
+	driftStore.Record(&contract.ScenarioDrift{Group: "todos", ScenarioName: "list-todos"})
+	driftStore.Record(&contract.ScenarioDrift{Group: "users", ScenarioName: "get-user", Drifted: true})
+	webServer := web.NewStubWebServer()
+	ctrl := NewDriftController(driftStore, webServer)
+
+	// WHEN fetching drift report for the group
+	ctx := web.NewStubContext(&http.Request{})
+	ctx.Params["group"] = "todos"
+	err := ctrl.getDrift(ctx)
+
+	// THEN it should return only drifted scenarios
+	require.NoError(t, err)
+	drifts := ctx.Result.([]*contract.ScenarioDrift)
+	require.Len(t, drifts, 1)
+	require.Equal(t, "get-todo", drifts[0].ScenarioName)
+
+	// WHEN fetching all checked scenarios
+	ctx.Params["all"] = "true"
+	err = ctrl.getDrift(ctx)
+
+	// THEN it should include scenarios without drift
+	require.NoError(t, err)
+	require.Len(t, ctx.Result.([]*contract.ScenarioDrift), 2)
+
+	// WHEN clearing drift of the group
+	err = ctrl.deleteDrift(ctx)
+
+	// THEN only other groups should remain
+	require.NoError(t, err)
+	require.Len(t, driftStore.List("", false), 1)
+}
@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/bhatti/api-mock-service/internal/contract"
+	"github.com/bhatti/api-mock-service/internal/fuzz"
+	"github.com/bhatti/api-mock-service/internal/repository"
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/bhatti/api-mock-service/internal/utils"
+	"github.com/bhatti/api-mock-service/internal/web"
+	"gopkg.in/yaml.v3"
+)
+
+// WorkflowController structure for workflows of producer contracts
+type WorkflowController struct {
+	workflowRepository repository.WorkflowRepository
+	executor           *contract.ProducerExecutor
+}
+
+// NewWorkflowController instantiates controller for saving and executing workflows
+func NewWorkflowController(
+	workflowRepository repository.WorkflowRepository,
+	executor *contract.ProducerExecutor,
+	webserver web.Server) *WorkflowController {
+	ctrl := &WorkflowController{
+		workflowRepository: workflowRepository,
+		executor:           executor,
+	}
+
+	webserver.GET("/_workflows", ctrl.getWorkflowGroups)
+	webserver.GET("/_workflows/:group", ctrl.getWorkflow)
+	webserver.PUT("/_workflows/:group", ctrl.putWorkflow)
+	webserver.DELETE("/_workflows/:group", ctrl.deleteWorkflow)
+	webserver.POST("/_contracts/workflows/:group", ctrl.postWorkflowContract)
+	return ctrl
+}
+
+// ********************************* HTTP Handlers ***********************************
+
+// getWorkflowGroups handler
+// swagger:route GET /_workflows workflow getWorkflowGroups
+// Returns groups with workflows
+// responses:
+//
+//	200: workflowGroupsResponse
+func (wc *WorkflowController) getWorkflowGroups(c web.APIContext) error {
+	return c.JSON(http.StatusOK, wc.workflowRepository.GetGroups())
+}
+
+// getWorkflow handler
+// swagger:route GET /_workflows/{group} workflow getWorkflow
+// Returns workflow of the group
+// responses:
+//
+//	200: workflowResponse
+func (wc *WorkflowController) getWorkflow(c web.APIContext) error {
+	group := c.Param("group")
+	if group == "" {
+		return fmt.Errorf("workflow group not specified in %s", c.Request().URL)
+	}
+	workflow, err := wc.workflowRepository.Load(group)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, workflow)
+}
+
+// putWorkflow handler
+// swagger:route PUT /_workflows/{group} workflow putWorkflow
+// Saves workflow of the group in YAML or JSON format
+// responses:
+//
+//	200: putWorkflowResponse
+func (wc *WorkflowController) putWorkflow(c web.APIContext) (err error) {
+	group := c.Param("group")
+	if group == "" {
+		return fmt.Errorf("workflow group not specified in %s", c.Request().URL)
+	}
+	var data []byte
+	data, c.Request().Body, err = utils.ReadAll(c.Request().Body)
+	if err != nil {
+		return err
+	}
+	workflow := &types.Workflow{}
+	// JSON is parsed as YAML
+	if err = yaml.Unmarshal(data, workflow); err != nil {
+		return err
+	}
+	if err = wc.workflowRepository.Save(group, workflow); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// deleteWorkflow handler
+// swagger:route DELETE /_workflows/{group} workflow deleteWorkflow
+// Deletes workflow of the group
+// responses:
+//
+//	200: putWorkflowResponse
+func (wc *WorkflowController) deleteWorkflow(c web.APIContext) error {
+	group := c.Param("group")
+	if group == "" {
+		return fmt.Errorf("workflow group not specified in %s", c.Request().URL)
+	}
+	if err := wc.workflowRepository.Delete(group); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// postWorkflowContract handler
+// swagger:route POST /_contracts/workflows/{group} producer-contract postWorkflowContract
+// Executes workflow of the group against the producer and returns the result of each step in workflow.
+// Add ?report=junit|html|markdown|sarif to return a report instead of JSON.
+// responses:
+//
+//	200: apiScenarioContractResponse
+func (wc *WorkflowController) postWorkflowContract(c web.APIContext) error {
+	group := c.Param("group")
+	if group == "" {
+		return fmt.Errorf("workflow group not specified in %s", c.Request().URL)
+	}
+	workflow, err := wc.workflowRepository.Load(group)
+	if err != nil {
+		return err
+	}
+	workflow.Group = group
+	contractReq, err := buildContractRequest(c)
+	if err != nil {
+		return err
+	}
+	dataTemplate := fuzz.NewDataTemplateRequest(false, 1, 1)
+	exec := specAwareExecutor(wc.executor, contractReq, dataTemplate)
+	res := exec.ExecuteWorkflow(context.Background(), c.Request(), workflow, dataTemplate, contractReq)
+	return renderContractResponse(c, group+"-workflow", res)
+}
+
+// ********************************* Swagger types ***********************************
+
+// The params for workflow of a group
+// swagger:parameters getWorkflow deleteWorkflow
+type workflowGroupParams struct {
+	// in:path
+	Group string `json:"group"`
+}
+
+// The params for saving workflow
+// swagger:parameters putWorkflow
+type putWorkflowParams struct {
+	// in:path
+	Group string `json:"group"`
+	// in:body
+	Body types.Workflow
+}
+
+// The params for executing workflow
+// swagger:parameters postWorkflowContract
+type postWorkflowContractParams struct {
+	// in:path
+	Group string `json:"group"`
+	// in:body
+	Body types.ProducerContractRequest
+}
+
+// Groups with workflows
+// swagger:response workflowGroupsResponse
+type workflowGroupsResponseBody struct {
+	// in:body
+	Body []string
+}
+
+// Workflow body of a group
+// swagger:response workflowResponse
+type workflowResponseBody struct {
+	// in:body
+	Body types.Workflow
+}
+
+// Empty body for saving or deleting workflow
+// swagger:response putWorkflowResponse
+type putWorkflowResponseBody struct {
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/contract"
+	"github.com/bhatti/api-mock-service/internal/repository"
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/bhatti/api-mock-service/internal/web"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_InitializeSwaggerStructsForWorkflowController(t *testing.T) {
+	_ = workflowGroupParams{}
+	_ = putWorkflowParams{}
+	_ = postWorkflowContractParams{}
+	_ = workflowGroupsResponseBody{}
+	_ = workflowResponseBody{}
+	_ = putWorkflowResponseBody{}
+}
+
+func Test_ShouldSaveAndExecuteWorkflow(t *testing.T) {
+	// GIVEN scenarios of a group and a producer
+	config := types.BuildTestConfig()
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	workflowRepository, err := repository.NewFileWorkflowRepository(&types.Configuration{DataDir: t.TempDir()})
+	require.NoError(t, err)
+	group := fmt.Sprintf("workflow_%d", time.Now().UnixNano())
+	for _, scenario := range []*types.APIScenario{
+		{Method: types.Post, Name: "create_todo", Path: "/todos", Group: group,
+			Response: types.APIResponse{StatusCode: 200}},
+		{Method: types.Get, Name: "get_todo", Path: "/todos/{todo_id}", Group: group,
+			Response: types.APIResponse{StatusCode: 200}},
+	} {
+		require.NoError(t, scenarioRepository.Save(scenario))
+	}
+	client := web.NewStubHTTPClient()
+	client.AddMapping("POST", "https://localhost/todos", web.NewStubHTTPResponse(200, `{"todo": {"id": 7}}`))
+	client.AddMapping("GET", "https://localhost/todos/7", web.NewStubHTTPResponse(200, `{"id": 7}`))
+	executor := contract.NewProducerExecutor(scenarioRepository, groupConfigRepository, client)
+	ctrl := NewWorkflowController(workflowRepository, executor, web.NewStubWebServer())
+
+	// WHEN saving workflow in YAML
+	body := `
+name: todos
+steps:
+  - name: create
+    scenario: create_todo
+    extract:
+      todo_id: $.todo.id
+  - name: get
+    scenario: get_todo
+    depends_on: [create]
+    assertions:
+      - NumPropertyGE contents.id 7
+`
+	ctx := web.NewStubContext(&http.Request{Body: io.NopCloser(bytes.NewReader([]byte(body)))})
+	ctx.Params["group"] = group
+	// THEN it should succeed
+	require.NoError(t, ctrl.putWorkflow(ctx))
+
+	// AND should return saved workflow
+	ctx = web.NewStubContext(&http.Request{})
+	ctx.Params["group"] = group
+	require.NoError(t, ctrl.getWorkflow(ctx))
+	require.Equal(t, []string{"create"}, ctx.Result.(*types.Workflow).Steps[1].DependsOn)
+	ctx = web.NewStubContext(&http.Request{})
+	require.NoError(t, ctrl.getWorkflowGroups(ctx))
+	require.Equal(t, []string{group}, ctx.Result)
+
+	// AND should execute saved workflow with results per step
+	ctx = web.NewStubContext(&http.Request{
+		Body: io.NopCloser(bytes.NewReader([]byte(`{"base_url": "https://localhost"}`)))})
+	ctx.Params["group"] = group
+	require.NoError(t, ctrl.postWorkflowContract(ctx))
+	res := ctx.Result.(*types.ProducerContractResponse)
+	require.Len(t, res.Workflow, 2)
+	require.Equal(t, types.OutcomePassed, res.Workflow[0].Status)
+	require.Equal(t, types.OutcomePassed, res.Workflow[1].Status, res.Workflow[1].Error)
+	require.Equal(t, 2, res.Succeeded)
+
+	// AND should delete workflow
+	ctx = web.NewStubContext(&http.Request{})
+	ctx.Params["group"] = group
+	require.NoError(t, ctrl.deleteWorkflow(ctx))
+	require.Error(t, ctrl.getWorkflow(ctx))
+}
+
+func Test_ShouldNotSaveInvalidWorkflow(t *testing.T) {
+	// GIVEN a workflow controller
+	workflowRepository, err := repository.NewFileWorkflowRepository(&types.Configuration{DataDir: t.TempDir()})
+	require.NoError(t, err)
+	ctrl := NewWorkflowController(workflowRepository, nil, web.NewStubWebServer())
+	// WHEN saving workflow with unknown dependency
+	body := `{"steps": [{"name": "get", "scenario": "get_todo", "depends_on": ["create"]}]}`
+	ctx := web.NewStubContext(&http.Request{Body: io.NopCloser(bytes.NewReader([]byte(body)))})
+	ctx.Params["group"] = "todos"
+	// THEN it should fail
+	require.ErrorContains(t, ctrl.putWorkflow(ctx), "unknown step create")
+}
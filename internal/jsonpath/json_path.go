@@ -0,0 +1,202 @@
+package jsonpath
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A path addresses a value in a decoded JSON document, where string elements are object keys,
+// int elements are array indexes and Wildcard matches every element of an array.
+
+// WildcardIndex matches every element of an array such as [*] in $.items[*].price
+type WildcardIndex struct{}
+
+// Wildcard element of a path
+var Wildcard = WildcardIndex{}
+
+var indexSuffixRegex = regexp.MustCompile(`^(.*)\[(\d+|\*)\]$`)
+
+// Parse parses dotted path such as $.user.role, $.items[0].price or items[*].name
+func Parse(expr string) []any {
+	expr = strings.TrimPrefix(strings.TrimPrefix(expr, "$"), ".")
+	path := make([]any, 0)
+	if expr == "" {
+		return path
+	}
+	for _, segment := range strings.Split(expr, ".") {
+		var indexes []any
+		for {
+			match := indexSuffixRegex.FindStringSubmatch(segment)
+			if len(match) != 3 {
+				break
+			}
+			if match[2] == "*" {
+				indexes = append([]any{Wildcard}, indexes...)
+			} else {
+				i, _ := strconv.Atoi(match[2])
+				indexes = append([]any{i}, indexes...)
+			}
+			segment = match[1]
+		}
+		if segment != "" {
+			path = append(path, segment)
+		}
+		path = append(path, indexes...)
+	}
+	return path
+}
+
+// Append returns a copy of path with elem appended so that sibling paths don't share the backing array
+func Append(path []any, elem any) []any {
+	next := make([]any, len(path), len(path)+1)
+	copy(next, path)
+	return append(next, elem)
+}
+
+// Expand returns concrete paths of the document that match the path with wildcards
+func Expand(doc any, path []any) (paths [][]any) {
+	expand(doc, path, make([]any, 0, len(path)), &paths)
+	return
+}
+
+func expand(node any, rest []any, prefix []any, paths *[][]any) {
+	if len(rest) == 0 {
+		*paths = append(*paths, prefix)
+		return
+	}
+	switch key := rest[0].(type) {
+	case string:
+		if obj, ok := node.(map[string]any); ok {
+			if child, exists := obj[key]; exists {
+				expand(child, rest[1:], Append(prefix, key), paths)
+			}
+		}
+	case int:
+		if arr, ok := node.([]any); ok && key < len(arr) {
+			expand(arr[key], rest[1:], Append(prefix, key), paths)
+		}
+	case WildcardIndex:
+		if arr, ok := node.([]any); ok {
+			for i, child := range arr {
+				expand(child, rest[1:], Append(prefix, i), paths)
+			}
+		}
+	}
+}
+
+// ValueAt returns value of JSON document at path of keys and indexes or nil if it doesn't exist
+func ValueAt(doc any, path []any) any {
+	current := doc
+	for _, elem := range path {
+		switch key := elem.(type) {
+		case string:
+			obj, ok := current.(map[string]any)
+			if !ok {
+				return nil
+			}
+			current = obj[key]
+		case int:
+			arr, ok := current.([]any)
+			if !ok || key >= len(arr) {
+				return nil
+			}
+			current = arr[key]
+		default:
+			return nil
+		}
+	}
+	return current
+}
+
+// SetValueAt sets value of JSON document at path of keys and indexes and returns false if parent doesn't exist
+func SetValueAt(doc any, path []any, value any) bool {
+	if len(path) == 0 {
+		return false
+	}
+	parent := ValueAt(doc, path[:len(path)-1])
+	switch key := path[len(path)-1].(type) {
+	case string:
+		if obj, ok := parent.(map[string]any); ok {
+			obj[key] = value
+			return true
+		}
+	case int:
+		if arr, ok := parent.([]any); ok && key < len(arr) {
+			arr[key] = value
+			return true
+		}
+	}
+	return false
+}
+
+// Put sets value at path, creating missing objects and setting every element for wildcards, and returns
+// the updated document
+func Put(doc any, path []any, value any) any {
+	if len(path) == 0 {
+		return value
+	}
+	switch key := path[0].(type) {
+	case string:
+		obj, ok := doc.(map[string]any)
+		if !ok {
+			if doc != nil {
+				return doc
+			}
+			obj = make(map[string]any)
+		}
+		obj[key] = Put(obj[key], path[1:], value)
+		return obj
+	case int:
+		if arr, ok := doc.([]any); ok && key < len(arr) {
+			arr[key] = Put(arr[key], path[1:], value)
+		}
+	case WildcardIndex:
+		if arr, ok := doc.([]any); ok {
+			for i := range arr {
+				arr[i] = Put(arr[i], path[1:], value)
+			}
+		}
+	}
+	return doc
+}
+
+// RemoveValueAt removes object field of JSON document at path and returns false if it doesn't exist
+func RemoveValueAt(doc any, path []any) bool {
+	if len(path) == 0 {
+		return false
+	}
+	key, ok := path[len(path)-1].(string)
+	if !ok {
+		return false
+	}
+	obj, ok := ValueAt(doc, path[:len(path)-1]).(map[string]any)
+	if !ok {
+		return false
+	}
+	if _, exists := obj[key]; !exists {
+		return false
+	}
+	delete(obj, key)
+	return true
+}
+
+// Format renders a path as dotted field names with array indexes, e.g. items[0].price
+func Format(path []any) string {
+	var sb strings.Builder
+	for _, elem := range path {
+		switch key := elem.(type) {
+		case string:
+			if sb.Len() > 0 {
+				sb.WriteString(".")
+			}
+			sb.WriteString(key)
+		case int:
+			sb.WriteString(fmt.Sprintf("[%d]", key))
+		case WildcardIndex:
+			sb.WriteString("[*]")
+		}
+	}
+	return sb.String()
+}
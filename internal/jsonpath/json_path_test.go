@@ -0,0 +1,73 @@
+package jsonpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldGetSetAndRemoveValuesAtJSONPath(t *testing.T) {
+	// GIVEN a decoded JSON document with nested objects and arrays
+	doc := map[string]any{
+		"order": map[string]any{
+			"items": []any{map[string]any{"price": 10.0}},
+		},
+	}
+	path := Append([]any{"order", "items", 0}, "price")
+
+	// WHEN reading, setting and removing values
+	// THEN values should be addressed by keys and indexes
+	require.Equal(t, 10.0, ValueAt(doc, path))
+	require.Nil(t, ValueAt(doc, []any{"order", "items", 1, "price"}))
+	require.True(t, SetValueAt(doc, path, 5.0))
+	require.Equal(t, 5.0, ValueAt(doc, path))
+	require.False(t, SetValueAt(doc, []any{"order", "missing", "price"}, 1))
+	require.False(t, RemoveValueAt(doc, []any{"order", "items", 0}))
+	require.True(t, RemoveValueAt(doc, path))
+	require.False(t, RemoveValueAt(doc, path))
+	require.Equal(t, "order.items[0].price", Format(path))
+}
+
+func Test_ShouldNotShareBackingArrayOfAppendedPaths(t *testing.T) {
+	// GIVEN a parent path
+	parent := []any{"order"}
+	// WHEN appending siblings
+	first := Append(parent, "id")
+	second := Append(parent, "items")
+	// THEN siblings should keep their own elements
+	require.Equal(t, []any{"order", "id"}, first)
+	require.Equal(t, []any{"order", "items"}, second)
+}
+
+func Test_ShouldParseDottedJSONPaths(t *testing.T) {
+	// GIVEN dotted paths with indexes and wildcards
+	// WHEN parsing paths
+	// THEN keys, indexes and wildcards should be returned
+	require.Equal(t, []any{"user", "role"}, Parse("$.user.role"))
+	require.Equal(t, []any{"items", 0, "price"}, Parse("$.items[0].price"))
+	require.Equal(t, []any{"items", Wildcard, "tags", 1}, Parse("items[*].tags[1]"))
+	require.Equal(t, []any{0, "id"}, Parse("$[0].id"))
+	require.Equal(t, []any{}, Parse("$"))
+	require.Equal(t, "items[*].tags[1]", Format(Parse("items[*].tags[1]")))
+}
+
+func Test_ShouldExpandAndPutValuesAtWildcardPaths(t *testing.T) {
+	// GIVEN a document with an array of objects
+	doc := map[string]any{"items": []any{map[string]any{"price": 1.0}, map[string]any{"price": 2.0}, "x"}}
+
+	// WHEN expanding a wildcard path
+	paths := Expand(doc, Parse("$.items[*].price"))
+
+	// THEN only existing values should be addressed
+	require.Equal(t, [][]any{{"items", 0, "price"}, {"items", 1, "price"}}, paths)
+
+	// AND WHEN putting values with wildcards and missing parents
+	res := Put(doc, Parse("$.items[*].price"), 0.0)
+	res = Put(res, Parse("$.meta.source"), "test")
+
+	// THEN every element and missing objects should be set
+	require.Equal(t, 0.0, ValueAt(res, []any{"items", 1, "price"}))
+	require.Equal(t, "x", ValueAt(res, []any{"items", 2}))
+	require.Equal(t, "test", ValueAt(res, []any{"meta", "source"}))
+	require.Equal(t, "root", Put(nil, []any{}, "root"))
+}
@@ -0,0 +1,267 @@
+package proxy
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/elazarl/goproxy"
+	log "github.com/sirupsen/logrus"
+)
+
+// CACertFile name of the public CA certificate under the CA directory
+const CACertFile = "ca_cert.pem"
+
+// CAKeyFile name of the CA private key under the CA directory
+const CAKeyFile = "ca_key.pem"
+
+// DefaultCAValidityDays validity of generated CA when not configured
+const DefaultCAValidityDays = 365
+
+// caLock serializes generation of CA files between proxy and controllers
+var caLock sync.Mutex
+
+// CertificateAuthority signs MITM leaf certificates with a CA that is unique to the installation
+type CertificateAuthority struct {
+	cert    tls.Certificate
+	x509    *x509.Certificate
+	certPEM []byte
+	leaves  map[string]*tls.Certificate
+	lock    sync.Mutex
+}
+
+// CADir returns directory of CA files under data dir
+func CADir(config *types.Configuration) string {
+	return filepath.Join(config.DataDir, "ca")
+}
+
+// LoadOrCreateCertificateAuthority loads CA from data dir or generates a new one on first start; an expired CA
+// is not replaced because clients trust it, so it must be replaced explicitly with rotate-ca
+func LoadOrCreateCertificateAuthority(config *types.Configuration) (*CertificateAuthority, error) {
+	caLock.Lock()
+	defer caLock.Unlock()
+	ca, err := loadCertificateAuthority(CADir(config))
+	if errors.Is(err, os.ErrNotExist) {
+		return createCertificateAuthority(config)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ca, ca.checkExpiry(config)
+}
+
+// LoadCertificateAuthority loads CA from data dir without generating it and returns NotFoundError if it doesn't exist
+func LoadCertificateAuthority(config *types.Configuration) (*CertificateAuthority, error) {
+	caLock.Lock()
+	defer caLock.Unlock()
+	ca, err := loadCertificateAuthority(CADir(config))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, types.NewNotFoundError(fmt.Sprintf("proxy CA doesn't exist under %s", CADir(config)))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ca, ca.checkExpiry(config)
+}
+
+// RotateCertificateAuthority replaces CA of the installation; proxy must be restarted to use it
+func RotateCertificateAuthority(config *types.Configuration) (*CertificateAuthority, error) {
+	caLock.Lock()
+	defer caLock.Unlock()
+	return createCertificateAuthority(config)
+}
+
+// PEM returns public certificate in PEM format
+func (ca *CertificateAuthority) PEM() []byte {
+	return ca.certPEM
+}
+
+// DER returns public certificate in DER format
+func (ca *CertificateAuthority) DER() []byte {
+	return ca.x509.Raw
+}
+
+// NotAfter returns expiration of the CA
+func (ca *CertificateAuthority) NotAfter() time.Time {
+	return ca.x509.NotAfter
+}
+
+// Fetch implements goproxy.CertStorage and caches leaf certificates by host until they expire
+func (ca *CertificateAuthority) Fetch(hostname string, gen func() (*tls.Certificate, error)) (*tls.Certificate, error) {
+	ca.lock.Lock()
+	defer ca.lock.Unlock()
+	if leaf := ca.leaves[hostname]; leaf != nil && leaf.Leaf != nil && time.Now().Before(leaf.Leaf.NotAfter) {
+		return leaf, nil
+	}
+	leaf, err := gen()
+	if err != nil {
+		return nil, err
+	}
+	if leaf.Leaf, err = x509.ParseCertificate(leaf.Certificate[0]); err != nil {
+		return nil, err
+	}
+	ca.leaves[hostname] = leaf
+	return leaf, nil
+}
+
+// IssueServerCertificate issues certificate signed by the CA for hosts such as names and IP addresses of the mock port
+func (ca *CertificateAuthority) IssueServerCertificate(hosts []string) (*tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := crand.Int(crand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"API Mock Service"},
+			CommonName:   hosts[0],
+		},
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    ca.x509.NotAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	derBytes, err := x509.CreateCertificate(crand.Reader, &template, ca.x509, &key.PublicKey, ca.cert.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	leaf, err := x509.ParseCertificate(derBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Certificate{
+		Certificate: [][]byte{derBytes, ca.x509.Raw},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}, nil
+}
+
+// checkExpiry returns error if the CA has expired
+func (ca *CertificateAuthority) checkExpiry(config *types.Configuration) error {
+	if time.Now().Before(ca.x509.NotAfter) {
+		return nil
+	}
+	log.WithFields(log.Fields{
+		"Component": "CertificateAuthority",
+		"Dir":       CADir(config),
+		"NotAfter":  ca.x509.NotAfter,
+	}).Errorf("proxy CA has expired, run rotate-ca to replace it")
+	return fmt.Errorf("proxy CA under %s expired at %s, run rotate-ca to replace it",
+		CADir(config), ca.x509.NotAfter.Format(time.RFC3339))
+}
+
+// connectAction returns MITM action that signs leaf certificates with the CA
+func (ca *CertificateAuthority) connectAction() *goproxy.ConnectAction {
+	return &goproxy.ConnectAction{Action: goproxy.ConnectMitm, TLSConfig: goproxy.TLSConfigFromCA(&ca.cert)}
+}
+
+func loadCertificateAuthority(dir string) (*CertificateAuthority, error) {
+	certPEM, err := os.ReadFile(filepath.Join(dir, CACertFile))
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(filepath.Join(dir, CAKeyFile))
+	if err != nil {
+		return nil, err
+	}
+	return newCertificateAuthority(certPEM, keyPEM)
+}
+
+func newCertificateAuthority(certPEM []byte, keyPEM []byte) (*CertificateAuthority, error) {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA key pair due to %w", err)
+	}
+	x509Cert, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate due to %w", err)
+	}
+	return &CertificateAuthority{
+		cert:    cert,
+		x509:    x509Cert,
+		certPEM: certPEM,
+		leaves:  make(map[string]*tls.Certificate),
+	}, nil
+}
+
+func createCertificateAuthority(config *types.Configuration) (*CertificateAuthority, error) {
+	validityDays := config.ProxyCA.ValidityDays
+	if validityDays <= 0 {
+		validityDays = DefaultCAValidityDays
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := crand.Int(crand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	hostname, _ := os.Hostname()
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"API Mock Service"},
+			CommonName:   fmt.Sprintf("API Mock Service Proxy CA (%s)", hostname),
+		},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Duration(validityDays) * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	derBytes, err := x509.CreateCertificate(crand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes})
+
+	dir := CADir(config)
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(filepath.Join(dir, CAKeyFile), keyPEM, 0600); err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(filepath.Join(dir, CACertFile), certPEM, 0644); err != nil {
+		return nil, err
+	}
+	log.WithFields(log.Fields{
+		"Component": "CertificateAuthority",
+		"Dir":       dir,
+		"NotAfter":  template.NotAfter,
+	}).Infof("generated proxy CA")
+	return newCertificateAuthority(certPEM, keyPEM)
+}
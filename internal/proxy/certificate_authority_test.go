@@ -0,0 +1,182 @@
+package proxy
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/elazarl/goproxy"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldCreateAndLoadCertificateAuthority(t *testing.T) {
+	// GIVEN config with empty data dir
+	config := types.BuildTestConfig()
+	config.DataDir = t.TempDir()
+	config.ProxyCA.ValidityDays = 30
+
+	// WHEN loading CA for the first time
+	ca, err := LoadOrCreateCertificateAuthority(config)
+
+	// THEN it should generate a CA valid for configured days
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(ca.DER())
+	require.NoError(t, err)
+	require.True(t, cert.IsCA)
+	require.WithinDuration(t, time.Now().Add(30*24*time.Hour), ca.NotAfter(), time.Minute)
+	block, _ := pem.Decode(ca.PEM())
+	require.NotNil(t, block)
+	require.Equal(t, ca.DER(), block.Bytes)
+	// AND private key should only be readable by owner
+	info, err := os.Stat(filepath.Join(CADir(config), CAKeyFile))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// WHEN loading CA again
+	loaded, err := LoadOrCreateCertificateAuthority(config)
+
+	// THEN it should reuse the same CA
+	require.NoError(t, err)
+	require.Equal(t, ca.DER(), loaded.DER())
+
+	// WHEN rotating CA
+	rotated, err := RotateCertificateAuthority(config)
+
+	// THEN it should generate a different CA
+	require.NoError(t, err)
+	require.NotEqual(t, ca.DER(), rotated.DER())
+	loaded, err = LoadOrCreateCertificateAuthority(config)
+	require.NoError(t, err)
+	require.Equal(t, rotated.DER(), loaded.DER())
+}
+
+func Test_ShouldNotReplaceExpiredCertificateAuthority(t *testing.T) {
+	// GIVEN an expired CA under data dir
+	config := types.BuildTestConfig()
+	config.DataDir = t.TempDir()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	require.NoError(t, err)
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "expired"},
+		NotBefore:             time.Now().Add(-48 * time.Hour),
+		NotAfter:              time.Now().Add(-24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	derBytes, err := x509.CreateCertificate(crand.Reader, &template, &template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(CADir(config), 0700))
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
+	require.NoError(t, os.WriteFile(filepath.Join(CADir(config), CACertFile), certPEM, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(CADir(config), CAKeyFile),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}), 0600))
+
+	// WHEN loading CA
+	_, err = LoadOrCreateCertificateAuthority(config)
+
+	// THEN it should fail and ask for rotation
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "rotate-ca")
+	_, err = LoadCertificateAuthority(config)
+	require.Error(t, err)
+	// AND the expired CA should not be replaced
+	saved, err := os.ReadFile(filepath.Join(CADir(config), CACertFile))
+	require.NoError(t, err)
+	require.Equal(t, certPEM, saved)
+
+	// WHEN rotating CA
+	_, err = RotateCertificateAuthority(config)
+	require.NoError(t, err)
+
+	// THEN it should load again
+	_, err = LoadOrCreateCertificateAuthority(config)
+	require.NoError(t, err)
+}
+
+func Test_ShouldNotLoadMissingCertificateAuthority(t *testing.T) {
+	// GIVEN config with empty data dir
+	config := types.BuildTestConfig()
+	config.DataDir = t.TempDir()
+
+	// WHEN loading CA without generating it
+	_, err := LoadCertificateAuthority(config)
+
+	// THEN it should return not found
+	var notFoundErr *types.NotFoundError
+	require.ErrorAs(t, err, &notFoundErr)
+}
+
+func Test_ShouldCacheLeafCertificatesByHost(t *testing.T) {
+	// GIVEN a CA
+	config := types.BuildTestConfig()
+	config.DataDir = t.TempDir()
+	ca, err := LoadOrCreateCertificateAuthority(config)
+	require.NoError(t, err)
+	generated := 0
+	gen := func() (*tls.Certificate, error) {
+		generated++
+		return signLeafForTest(ca)
+	}
+
+	// WHEN fetching leaf certificates for same host twice and another host once
+	first, err := ca.Fetch("api.example.com", gen)
+	require.NoError(t, err)
+	second, err := ca.Fetch("api.example.com", gen)
+	require.NoError(t, err)
+	_, err = ca.Fetch("other.example.com", gen)
+	require.NoError(t, err)
+
+	// THEN leaf should be generated once per host
+	require.Same(t, first, second)
+	require.Equal(t, 2, generated)
+	// AND leaf should be signed by the CA
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.x509)
+	_, err = first.Leaf.Verify(x509.VerifyOptions{DNSName: "api.example.com", Roots: pool})
+	require.NoError(t, err)
+}
+
+func signLeafForTest(ca *CertificateAuthority) (*tls.Certificate, error) {
+	tlsConfig, err := ca.connectAction().TLSConfig("api.example.com:443", &goproxy.ProxyCtx{Proxy: goproxy.NewProxyHttpServer()})
+	if err != nil {
+		return nil, err
+	}
+	return &tlsConfig.Certificates[0], nil
+}
+
+func Test_ShouldIssueServerCertificateForMockPort(t *testing.T) {
+	// GIVEN a CA
+	config := types.BuildTestConfig()
+	config.DataDir = t.TempDir()
+	ca, err := LoadOrCreateCertificateAuthority(config)
+	require.NoError(t, err)
+
+	// WHEN issuing certificate for names and addresses of the mock port
+	cert, err := ca.IssueServerCertificate([]string{"localhost", "127.0.0.1", "mock.local"})
+
+	// THEN certificate should be trusted by clients that trust the CA
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(ca.PEM())
+	for _, host := range []string{"localhost", "127.0.0.1", "mock.local"} {
+		_, err = cert.Leaf.Verify(x509.VerifyOptions{DNSName: host, Roots: roots})
+		require.NoError(t, err, host)
+	}
+	_, err = cert.Leaf.Verify(x509.VerifyOptions{DNSName: "example.com", Roots: roots})
+	require.Error(t, err)
+	require.Len(t, cert.Certificate, 2)
+}
@@ -2,12 +2,8 @@ package proxy
 
 import (
 	"bytes"
-	crand "crypto/rand"
-	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
-	"crypto/x509/pkix"
-	"encoding/pem"
 	"errors"
 	"fmt"
 	"github.com/bhatti/api-mock-service/internal/contract"
@@ -18,10 +14,7 @@ import (
 	"github.com/elazarl/goproxy"
 	log "github.com/sirupsen/logrus"
 	"io"
-	"math/big"
-	"math/rand"
 	"net/http"
-	"os"
 	"regexp"
 	"strings"
 	"time"
@@ -51,6 +44,7 @@ type Handler struct {
 	fixtureRepository     repository.APIFixtureRepository
 	groupConfigRepository repository.GroupConfigRepository
 	adapter               web.Adapter
+	etags                 *contract.ETagStore
 }
 
 // NewProxyHandler instantiates controller for updating api-scenarios
@@ -69,14 +63,23 @@ func NewProxyHandler(
 		fixtureRepository:     fixtureRepository,
 		groupConfigRepository: groupConfigRepository,
 		adapter:               adapter,
+		etags:                 contract.NewETagStore(),
 	}
 }
 
 // Start runs the proxy server on a given port
 func (h *Handler) Start() error {
+	ca, err := LoadOrCreateCertificateAuthority(h.config)
+	if err != nil {
+		return fmt.Errorf("failed to load proxy CA due to %w", err)
+	}
 	proxy := goproxy.NewProxyHttpServer()
+	proxy.CertStore = ca
 
-	proxy.OnRequest(h.proxyCondition()).HandleConnect(goproxy.AlwaysMitm)
+	proxy.OnRequest(h.proxyCondition()).HandleConnect(goproxy.FuncHttpsHandler(
+		func(host string, _ *goproxy.ProxyCtx) (*goproxy.ConnectAction, string) {
+			return ca.connectAction(), host
+		}))
 	proxy.OnRequest(h.proxyCondition()).DoFunc(h.handleRequest)
 	proxy.OnResponse(h.proxyCondition()).DoFunc(h.handleResponse)
 	proxy.Verbose = false
@@ -84,13 +87,22 @@ func (h *Handler) Start() error {
 		return nil
 	}
 	proxy.Tr.TLSClientConfig = acceptAllCerts
+	if _, err = web.ConfigureUpstreamProxy(h.config, proxy.Tr); err != nil {
+		return err
+	}
+	// tunnels that are not intercepted are dialed through upstream proxy as well
+	connectDial, err := upstreamConnectDial(h.config, proxy)
+	if err != nil {
+		return err
+	}
+	if connectDial != nil {
+		proxy.ConnectDial = connectDial
+	}
+	if err = web.CheckHostOverridesWithUpstreamProxy(h.config, h.config.HostOverrides); err != nil {
+		return err
+	}
+	web.ConfigureHostOverrides(h.config.HostOverrides, proxy.Tr)
 	//http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-	//err := saveProxyCert()
-	//if err != nil {
-	//	log.WithFields(log.Fields{
-	//		"Error": err,
-	//	}).Warnf("failed to create proxy cert")
-	//}
 	return http.ListenAndServe(fmt.Sprintf(":%d", h.config.ProxyPort), proxy)
 }
 
@@ -111,7 +123,8 @@ func (h *Handler) handleRequest(req *http.Request, ctx *goproxy.ProxyCtx) (*http
 func (h *Handler) doHandleRequest(req *http.Request, ctx *goproxy.ProxyCtx) (*http.Request, *http.Response, error) {
 	ctx.UserData = time.Now()
 	var err error
-	_, req.Body, err = utils.ReadAll(req.Body)
+	var reqBody []byte
+	reqBody, req.Body, err = utils.ReadAll(req.Body)
 	if err != nil {
 		log.WithFields(log.Fields{
 			"Path":   req.URL,
@@ -126,6 +139,12 @@ func (h *Handler) doHandleRequest(req *http.Request, ctx *goproxy.ProxyCtx) (*ht
 		_ = req.Body.(utils.ResetReader).Reset()
 	}
 
+	// rewrite request before signing, playback and forwarding
+	if groupConfig, err := h.groupConfigRepository.Load(types.RequestGroup(req)); err == nil {
+		reqBody = groupConfig.RewriteRequest(req, reqBody)
+		req.Body = utils.NopCloser(bytes.NewReader(reqBody))
+	}
+
 	oldAuth := req.Header.Get(types.AuthorizationHeader)
 	awsAuthSig4, awsInfo, err := h.authAdapter.HandleAuth(req)
 
@@ -189,6 +208,7 @@ func (h *Handler) doHandleRequest(req *http.Request, ctx *goproxy.ProxyCtx) (*ht
 		h.scenarioRepository,
 		h.fixtureRepository,
 		h.groupConfigRepository,
+		h.etags,
 	)
 	if err != nil {
 		return req, nil, err
@@ -210,9 +230,13 @@ func (h *Handler) doHandleRequest(req *http.Request, ctx *goproxy.ProxyCtx) (*ht
 
 	resp.StatusCode = matchedScenario.Response.StatusCode
 	resp.Status = http.StatusText(matchedScenario.Response.StatusCode)
+	respBody = contract.EncodeResponseBody(req.Header, respHeader, resp.StatusCode, respBody)
 	buf := bytes.NewBuffer(respBody)
 	resp.ContentLength = int64(buf.Len())
 	resp.Body = io.NopCloser(buf)
+	if latency := contract.ScenarioLatency(h.groupConfigRepository, matchedScenario); latency != nil && latency.BytesPerSecond > 0 {
+		resp.Body = io.NopCloser(utils.NewThrottledReader(buf, latency.BytesPerSecond, nil))
+	}
 	return req, resp, nil
 }
 
@@ -268,20 +292,28 @@ func (h *Handler) doHandleResponse(resp *http.Response, ctx *goproxy.ProxyCtx) (
 		return resp, err
 	}
 
-	scenario, resContentType, err := saveMockResponse(
-		h.config,
-		resp.Request.URL,
-		resp.Request,
-		reqBytes,
-		resBytes,
-		resp.Header,
-		resp.StatusCode,
-		resp.Proto,
-		getStartTime(ctx),
-		time.Now(),
-		h.scenarioRepository)
-	if err != nil {
-		return resp, err
+	if groupConfig, err := h.groupConfigRepository.Load(types.RequestGroup(resp.Request)); err == nil {
+		resBytes = rewriteResponse(groupConfig, resp.Request.URL, resp.Header, resBytes)
+	}
+
+	var scenario *types.APIScenario
+	resContentType := resp.Header.Get(types.ContentTypeHeader)
+	if shouldRecord(h.config, resp.Request.URL, resp.Request.Method, resp.StatusCode, resp.Header, reqBytes, resBytes) {
+		scenario, resContentType, err = saveMockResponse(
+			h.config,
+			resp.Request.URL,
+			resp.Request,
+			reqBytes,
+			resBytes,
+			resp.Header,
+			resp.StatusCode,
+			resp.Proto,
+			getStartTime(ctx),
+			time.Now(),
+			h.scenarioRepository)
+		if err != nil {
+			return resp, err
+		}
 	}
 	resp.Body = utils.NopCloser(bytes.NewReader(resBytes))
 	resp.Header["Access-Control-Allow-Origin"] = []string{h.config.CORS}
@@ -339,43 +371,3 @@ func getStartTime(ctx *goproxy.ProxyCtx) time.Time {
 	}
 	return time.Now()
 }
-
-func saveProxyCert() error {
-	cert, err := tls.X509KeyPair(goproxy.CA_CERT, goproxy.CA_KEY)
-	if err != nil {
-		return err
-	}
-	ca, err := x509.ParseCertificate(cert.Certificate[0])
-	if err != nil {
-		return err
-	}
-	start := time.Unix(time.Now().Unix()-2592000, 0) // 2592000  = 30 day
-	end := time.Unix(time.Now().Unix()+31536000, 0)  // 31536000 = 365 day
-	serial := big.NewInt(rand.Int63())
-	template := x509.Certificate{
-		SerialNumber: serial,
-		Issuer:       ca.Subject,
-		Subject: pkix.Name{
-			Organization: []string{"GoProxy untrusted MITM proxy Inc"},
-		},
-		NotBefore:             start,
-		NotAfter:              end,
-		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
-		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
-		BasicConstraintsValid: true,
-	}
-	caPrivKey, err := rsa.GenerateKey(crand.Reader, 4096)
-	if err != nil {
-		return err
-	}
-	derBytes, err := x509.CreateCertificate(crand.Reader, &template, &template, &caPrivKey.PublicKey, caPrivKey)
-	if err != nil {
-		return err
-	}
-	certPEM := new(bytes.Buffer)
-	err = pem.Encode(certPEM, &pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
-	if err != nil {
-		return err
-	}
-	return os.WriteFile("cert.pem", certPEM.Bytes(), 0644)
-}
@@ -3,17 +3,19 @@ package proxy
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"github.com/bhatti/api-mock-service/internal/repository"
 	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/bhatti/api-mock-service/internal/utils"
 	"github.com/bhatti/api-mock-service/internal/web"
 	"github.com/elazarl/goproxy"
 	"github.com/stretchr/testify/require"
 	"io"
 	"net/http"
 	"net/url"
-	"os"
 	"strings"
 	"testing"
+	"time"
 )
 
 func Test_ShouldNotStartProxyServer(t *testing.T) {
@@ -330,13 +332,6 @@ func Test_ShouldHandleProxyCondition(t *testing.T) {
 	require.False(t, proxyCond(&http.Request{URL: u}, nil))
 }
 
-func Test_SaveProxyCert(t *testing.T) {
-	err := saveProxyCert()
-	require.NoError(t, err)
-	err = os.Remove("cert.pem")
-	require.NoError(t, err)
-}
-
 func Test_ShouldHandleProxyResponseWithoutResponseBody(t *testing.T) {
 	config := types.BuildTestConfig()
 	// GIVEN a mock scenario repository
@@ -396,3 +391,62 @@ func Test_ShouldHandleProxyResponseWithRequestAndResponseBody(t *testing.T) {
 	res = handler.handleResponse(res, &goproxy.ProxyCtx{})
 	require.NotNil(t, res)
 }
+
+func Test_ShouldRecordRewrittenRequestBodyInProxyPath(t *testing.T) {
+	config := types.BuildTestConfig()
+	// GIVEN a group with request and response rewrite rules
+	scenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	fixtureRepository, err := repository.NewFileFixtureRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	require.NoError(t, groupConfigRepository.Save("rewrite_mitm", &types.GroupConfig{
+		RewriteRules: []*types.RewriteRule{
+			{Name: "role", SetJSONPaths: map[string]any{"$.role": "admin"}},
+			{Name: "title", Phase: types.RewriteResponsePhase, SetJSONPaths: map[string]any{"$.title": "rewritten"}},
+		},
+	}))
+	handler := NewProxyHandler(config,
+		web.NewAuthAdapter(config), scenarioRepository, fixtureRepository, groupConfigRepository, web.NewWebServerAdapter())
+	path := fmt.Sprintf("/rewrite_mitm/users%d", time.Now().UnixNano())
+	u, err := url.Parse("http://localhost:8080" + path)
+	require.NoError(t, err)
+	req := &http.Request{
+		URL:    u,
+		Method: "POST",
+		Header: http.Header{types.MockGroup: []string{"rewrite_mitm"}, types.ContentTypeHeader: []string{"application/json"}},
+		Body:   io.NopCloser(bytes.NewReader([]byte(`{"name":"jane"}`))),
+	}
+	ctx := &goproxy.ProxyCtx{}
+
+	// WHEN handling request and the transport reads the rewritten body
+	req, res := handler.handleRequest(req, ctx)
+	require.Nil(t, res)
+	sent, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"jane","role":"admin"}`, string(sent))
+	// AND upstream returns a gzip encoded response
+	encoded, err := utils.EncodeBody("gzip", []byte(`{"id":1,"title":"live"}`))
+	require.NoError(t, err)
+	res = handler.handleResponse(&http.Response{
+		StatusCode: 200,
+		Request:    req,
+		Body:       io.NopCloser(bytes.NewReader(encoded)),
+		Header: http.Header{types.ContentTypeHeader: []string{"application/json"},
+			types.ContentEncodingHeader: []string{"gzip"}},
+	}, ctx)
+
+	// THEN response body should be decoded and rewritten
+	resBody, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":1,"title":"rewritten"}`, string(resBody))
+	require.Equal(t, "", res.Header.Get(types.ContentEncodingHeader))
+	// AND recorded scenario should have rewritten request body
+	keys := scenarioRepository.LookupAllByPath(path)
+	require.Len(t, keys, 1)
+	scenario, err := scenarioRepository.Lookup(keys[0], nil)
+	require.NoError(t, err)
+	require.Contains(t, scenario.Request.Contents, "admin")
+	require.Contains(t, scenario.Response.Contents, "rewritten")
+}
@@ -1,12 +1,14 @@
 package proxy
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"github.com/bhatti/api-mock-service/internal/utils"
 	log "github.com/sirupsen/logrus"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/bhatti/api-mock-service/internal/repository"
@@ -37,6 +39,12 @@ func NewRecorder(
 	}
 }
 
+// CertificateAuthority returns CA of the installation that signs MITM certificates of the proxy without
+// generating it, so that downloading the certificate doesn't create or replace the CA
+func (r *Recorder) CertificateAuthority() (*CertificateAuthority, error) {
+	return LoadCertificateAuthority(r.config)
+}
+
 // Handle records request
 func (r *Recorder) Handle(c web.APIContext) (err error) {
 	started := time.Now()
@@ -55,13 +63,19 @@ func (r *Recorder) Handle(c web.APIContext) (err error) {
 		return err
 	}
 
+	// rewrite copy of the request targeting remote url based on rules of the group
+	req := c.Request().Clone(context.Background())
+	req.URL = u
+	groupConfig, _ := r.groupConfigRepository.Load(types.RequestGroup(req))
+	reqBody = groupConfig.RewriteRequest(req, reqBody)
+
 	status, httpVersion, resBody, resHeaders, err := r.client.Handle(
 		context.Background(),
-		mockURL,
-		c.Request().Method,
-		c.Request().Header,
+		req.URL.String(),
+		req.Method,
+		req.Header,
 		nil,
-		c.Request().Body,
+		utils.NopCloser(bytes.NewReader(reqBody)),
 	)
 	if err != nil {
 		return err
@@ -71,25 +85,32 @@ func (r *Recorder) Handle(c web.APIContext) (err error) {
 	if err != nil {
 		return err
 	}
+	resBytes = rewriteResponse(groupConfig, req.URL, resHeaders, resBytes)
 
-	scenario, resContentType, err := saveMockResponse(
-		r.config,
-		u,
-		c.Request(),
-		reqBody,
-		resBytes,
-		resHeaders,
-		status,
-		httpVersion,
-		started,
-		time.Now(),
-		r.scenarioRepository)
-	if err != nil {
-		return err
+	// faults and delays of the group are injected whether or not the request is recorded
+	group := types.RequestGroup(req)
+	resContentType := http.Header(resHeaders).Get(types.ContentTypeHeader)
+	if shouldRecord(r.config, req.URL, req.Method, status, resHeaders, reqBody, resBytes) {
+		scenario, contentType, err := saveMockResponse(
+			r.config,
+			req.URL,
+			req,
+			reqBody,
+			resBytes,
+			resHeaders,
+			status,
+			httpVersion,
+			started,
+			time.Now(),
+			r.scenarioRepository)
+		if err != nil {
+			return err
+		}
+		group, resContentType = scenario.Group, contentType
 	}
 
 	// Embedding this check for chaos settings
-	if groupConfig, err := r.groupConfigRepository.Load(scenario.Group); err == nil {
+	if groupConfig, err := r.groupConfigRepository.Load(group); err == nil {
 		resHeaders[types.MockChaosEnabled] = []string{fmt.Sprintf("%v", groupConfig.ChaosEnabled)}
 		status := groupConfig.GetHTTPStatus()
 		if status >= 300 {
@@ -99,7 +120,7 @@ func (r *Recorder) Handle(c web.APIContext) (err error) {
 		if delay > 0 {
 			log.WithFields(log.Fields{
 				"Component":   "Recorder",
-				"Group":       scenario.Group,
+				"Group":       group,
 				"GroupConfig": groupConfig,
 				"Delay":       delay,
 			}).Infof("artificial sleep wait")
@@ -110,6 +131,30 @@ func (r *Recorder) Handle(c web.APIContext) (err error) {
 	return c.Blob(status, resContentType, resBytes)
 }
 
+// rewriteResponse decodes compressed response body so that body rules of the group can match it
+func rewriteResponse(groupConfig *types.GroupConfig, u *url.URL, headers http.Header, body []byte) []byte {
+	if groupConfig == nil || len(groupConfig.RewriteRules) == 0 {
+		return body
+	}
+	if encoding := headers.Get(types.ContentEncodingHeader); encoding != "" {
+		if decoded, err := utils.DecodeBody(encoding, body); err == nil {
+			body = decoded
+			headers.Del(types.ContentEncodingHeader)
+			if headers.Get(types.ContentLengthHeader) != "" {
+				headers.Set(types.ContentLengthHeader, strconv.Itoa(len(body)))
+			}
+		} else {
+			log.WithFields(log.Fields{
+				"Component": "Recorder",
+				"Path":      u,
+				"Encoding":  encoding,
+				"Error":     err,
+			}).Warnf("failed to decode response body for rewrite rules")
+		}
+	}
+	return groupConfig.RewriteResponse(u, headers, body)
+}
+
 func saveMockResponse(
 	config *types.Configuration,
 	u *url.URL,
@@ -123,6 +168,30 @@ func saveMockResponse(
 	ended time.Time,
 	scenarioRepository repository.APIScenarioRepository) (scenario *types.APIScenario, resContentType string, err error) {
 
+	// store decoded body so that playback can encode it based on Accept-Encoding of the client
+	if encoding := http.Header(resHeaders).Get(types.ContentEncodingHeader); encoding != "" {
+		if decoded, err := utils.DecodeBody(encoding, resBody); err == nil {
+			resBody = decoded
+			resHeaders = http.Header(resHeaders).Clone()
+			delete(resHeaders, types.ContentEncodingHeader)
+		} else {
+			log.WithFields(log.Fields{
+				"Component": "Recorder",
+				"Path":      u,
+				"Encoding":  encoding,
+				"Error":     err,
+			}).Warnf("failed to decode recorded response body")
+		}
+	}
+
+	// redact secrets before persisting without changing the live request/response
+	reqHeaders := config.Redaction.RedactHeaders(req.Header)
+	resHeaders = config.Redaction.RedactHeaders(resHeaders)
+	reqBody = config.Redaction.RedactBody(reqBody)
+	resBody = config.Redaction.RedactBody(resBody)
+	queryParams := config.Redaction.RedactParams(req.URL.Query())
+	postParams := config.Redaction.RedactParams(req.PostForm)
+
 	scenario, err = types.BuildScenarioFromHTTP(
 		config,
 		"Recorded",
@@ -133,9 +202,9 @@ func saveMockResponse(
 		resHTTPVersion,
 		reqBody,
 		resBody,
-		req.URL.Query(),
-		req.PostForm,
-		req.Header,
+		queryParams,
+		postParams,
+		reqHeaders,
 		"",
 		resHeaders,
 		"",
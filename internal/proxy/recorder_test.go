@@ -11,6 +11,7 @@ import (
 
 	"github.com/bhatti/api-mock-service/internal/repository"
 	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/bhatti/api-mock-service/internal/utils"
 	"github.com/bhatti/api-mock-service/internal/web"
 	"github.com/stretchr/testify/require"
 )
@@ -310,3 +311,198 @@ func Test_ShouldRecordRealPostProxyRequests(t *testing.T) {
 	saved := ctx.Result.([]byte)
 	require.Contains(t, string(saved), "id")
 }
+
+func Test_ShouldRedactSecretsWhenSavingMockResponse(t *testing.T) {
+	config := types.BuildTestConfig()
+	config.Redaction = types.RedactionConfig{Rules: []*types.RedactionRule{
+		{Name: "auth", Headers: []string{"X-Api-Token"}},
+		{Name: "email", Detector: types.EmailDetector},
+	}}
+	// GIVEN a mock scenario repository
+	mockScenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	u, err := url.Parse("http://localhost:8080/redact/users?email=jane@example.com")
+	require.NoError(t, err)
+	req := &http.Request{
+		URL:    u,
+		Method: "POST",
+		Header: http.Header{"X-Api-Token": []string{"secret"}},
+	}
+	resHeaders := http.Header{types.ContentTypeHeader: []string{"application/json"}}
+
+	// WHEN saving mock response
+	scenario, _, err := saveMockResponse(
+		config,
+		u,
+		req,
+		[]byte(`{"email":"jane@example.com"}`),
+		[]byte(`{"id":1,"email":"jane@example.com"}`),
+		resHeaders,
+		200,
+		"",
+		time.Now(),
+		time.Now().Add(time.Second),
+		mockScenarioRepository)
+
+	// THEN it should redact secrets in saved scenario without changing live request
+	require.NoError(t, err)
+	require.Contains(t, scenario.Request.Headers["X-Api-Token"], types.RedactedMask)
+	require.NotContains(t, scenario.Request.Contents, "jane@example.com")
+	require.NotContains(t, scenario.Response.Contents, "jane@example.com")
+	require.Equal(t, "secret", req.Header.Get("X-Api-Token"))
+}
+
+func Test_ShouldNotRecordFilteredProxyRequests(t *testing.T) {
+	config := types.BuildTestConfig()
+	config.RecordingFilter = types.RecordingFilterConfig{
+		Exclude: []*types.RecordingFilterRule{{Name: "skip-health-check", Paths: []string{"/health/**"}}},
+	}
+	// GIVEN repository and recorder with recording filter
+	mockScenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	client := web.NewStubHTTPClient()
+	client.AddMapping("GET", "https://jsonplaceholder.typicode.com/health/live", web.NewStubHTTPResponse(200, "ok"))
+	recorder := NewRecorder(config, client, mockScenarioRepository, groupConfigRepository)
+	u, err := url.Parse("http://localhost:8080")
+	require.NoError(t, err)
+	ctx := web.NewStubContext(&http.Request{
+		Method: "GET",
+		URL:    u,
+		Header: map[string][]string{
+			types.MockURL: {"https://jsonplaceholder.typicode.com/health/live"},
+		},
+	})
+	before := SkippedRecordings()["skip-health-check"]
+
+	// WHEN invoking GET proxy API
+	err = recorder.Handle(ctx)
+
+	// THEN it should return response without recording it
+	require.NoError(t, err)
+	require.Equal(t, "ok", string(ctx.Result.([]byte)))
+	require.Equal(t, before+1, SkippedRecordings()["skip-health-check"])
+	require.Len(t, mockScenarioRepository.LookupAllByPath("/health/live"), 0)
+}
+
+func Test_ShouldInjectFaultsForFilteredProxyRequests(t *testing.T) {
+	config := types.BuildTestConfig()
+	config.RecordingFilter = types.RecordingFilterConfig{
+		Exclude: []*types.RecordingFilterRule{{Name: "skip-chaos-health-check", Paths: []string{"/chaos_health/**"}}},
+	}
+	// GIVEN recorder with recording filter and a group that always injects faults
+	mockScenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	require.NoError(t, groupConfigRepository.Save("chaos_health", &types.GroupConfig{
+		ChaosEnabled:           true,
+		MeanTimeBetweenFailure: 1e12,
+		HTTPErrors:             []int{503},
+	}))
+	client := web.NewStubHTTPClient()
+	client.AddMapping("GET", "https://jsonplaceholder.typicode.com/chaos_health/live", web.NewStubHTTPResponse(200, "ok"))
+	recorder := NewRecorder(config, client, mockScenarioRepository, groupConfigRepository)
+	u, err := url.Parse("http://localhost:8080")
+	require.NoError(t, err)
+	ctx := web.NewStubContext(&http.Request{
+		Method: "GET",
+		URL:    u,
+		Header: map[string][]string{
+			types.MockURL:   {"https://jsonplaceholder.typicode.com/chaos_health/live"},
+			types.MockGroup: {"chaos_health"},
+		},
+	})
+
+	// WHEN invoking GET proxy API for a filtered path
+	err = recorder.Handle(ctx)
+
+	// THEN it should inject the fault of the group without recording it
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "503 - injected fault from recorder")
+	require.Len(t, mockScenarioRepository.LookupAllByPath("/chaos_health/live"), 0)
+}
+
+func Test_ShouldRewriteProxyRequestsBeforeRecording(t *testing.T) {
+	config := types.BuildTestConfig()
+	// GIVEN repository and recorder with rewrite rules for the group
+	mockScenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	groupConfigRepository, err := repository.NewFileGroupConfigRepository(config)
+	require.NoError(t, err)
+	require.NoError(t, groupConfigRepository.Save("rewrite_todos", &types.GroupConfig{
+		RewriteRules: []*types.RewriteRule{
+			{Name: "staging", URLPattern: `^https://prod\.example\.com/(.*)$`, URLReplacement: "https://staging.example.com/$1",
+				RemoveQuery: []string{"utm_*"}},
+			{Name: "title", Phase: types.RewriteResponsePhase,
+				SetJSONPaths: map[string]any{"$.title": "rewritten title"}},
+		},
+	}))
+	client := web.NewStubHTTPClient()
+	client.AddMapping("GET", "https://staging.example.com/rewrite/todos?id=3", web.NewStubHTTPResponse(200,
+		`{"id": 3, "title": "staging title"}`))
+	recorder := NewRecorder(config, client, mockScenarioRepository, groupConfigRepository)
+	u, err := url.Parse("http://localhost:8080")
+	require.NoError(t, err)
+	ctx := web.NewStubContext(&http.Request{
+		Method: "GET",
+		URL:    u,
+		Header: map[string][]string{
+			types.MockURL: {"https://prod.example.com/rewrite/todos?id=3&utm_source=mail"},
+		},
+	})
+
+	// WHEN invoking GET proxy API
+	err = recorder.Handle(ctx)
+
+	// THEN it should call rewritten url and return rewritten response
+	require.NoError(t, err)
+	require.Contains(t, string(ctx.Result.([]byte)), "rewritten title")
+	// AND record rewritten traffic
+	scenarios := mockScenarioRepository.LookupAllByPath("/rewrite/todos")
+	require.Len(t, scenarios, 1)
+	scenario, err := mockScenarioRepository.LookupByName(scenarios[0].Name, nil)
+	require.NoError(t, err)
+	require.Equal(t, "https://staging.example.com", scenario.BaseURL)
+	require.NotContains(t, scenario.Request.QueryParams, "utm_source")
+	require.Contains(t, scenario.Request.QueryParams, "id")
+}
+
+func Test_ShouldDecodeCompressedBodyWhenSavingMockResponse(t *testing.T) {
+	config := types.BuildTestConfig()
+	config.DataDir = t.TempDir()
+	// GIVEN a mock scenario repository and gzip response
+	mockScenarioRepository, err := repository.NewFileAPIScenarioRepository(config)
+	require.NoError(t, err)
+	u, err := url.Parse("http://localhost:8080/compressed/users")
+	require.NoError(t, err)
+	req := &http.Request{URL: u, Method: "GET", Header: http.Header{}}
+	body := `{"id":1,"name":"jane"}`
+	encoded, err := utils.EncodeBody("gzip", []byte(body))
+	require.NoError(t, err)
+	resHeaders := http.Header{
+		types.ContentTypeHeader:     []string{"application/json"},
+		types.ContentEncodingHeader: []string{"gzip"},
+	}
+
+	// WHEN saving mock response
+	scenario, _, err := saveMockResponse(
+		config,
+		u,
+		req,
+		nil,
+		encoded,
+		resHeaders,
+		200,
+		"",
+		time.Now(),
+		time.Now().Add(time.Second),
+		mockScenarioRepository)
+
+	// THEN it should save decoded body without content encoding
+	require.NoError(t, err)
+	require.JSONEq(t, body, scenario.Response.Contents)
+	require.Empty(t, scenario.Response.Headers.Get(types.ContentEncodingHeader))
+	require.Equal(t, "gzip", resHeaders.Get(types.ContentEncodingHeader))
+}
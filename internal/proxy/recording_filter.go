@@ -0,0 +1,56 @@
+package proxy
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// recordingSkips keeps counts of requests that were not recorded by rule name for both recorder and proxy handler
+var recordingSkips = struct {
+	lock   sync.RWMutex
+	counts map[string]int64
+}{counts: make(map[string]int64)}
+
+// SkippedRecordings returns counts of requests that were not recorded by name of filter rule
+func SkippedRecordings() map[string]int64 {
+	recordingSkips.lock.RLock()
+	defer recordingSkips.lock.RUnlock()
+	res := make(map[string]int64, len(recordingSkips.counts))
+	for k, v := range recordingSkips.counts {
+		res[k] = v
+	}
+	return res
+}
+
+// shouldRecord checks recording filters and counts skipped requests
+func shouldRecord(
+	config *types.Configuration,
+	u *url.URL,
+	method string,
+	status int,
+	resHeaders http.Header,
+	reqBody []byte,
+	resBody []byte) bool {
+	reason := config.RecordingFilter.SkipReason(
+		method, u, status, resHeaders.Get(types.ContentTypeHeader), len(reqBody), len(resBody))
+	if reason == "" {
+		return true
+	}
+	recordingSkips.lock.Lock()
+	recordingSkips.counts[reason]++
+	count := recordingSkips.counts[reason]
+	recordingSkips.lock.Unlock()
+	log.WithFields(log.Fields{
+		"Component": "RecordingFilter",
+		"URL":       u,
+		"Method":    method,
+		"Status":    status,
+		"Rule":      reason,
+		"Skipped":   count,
+	}).Debugf("skipped recording")
+	return false
+}
@@ -0,0 +1,57 @@
+package proxy
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/bhatti/api-mock-service/internal/web"
+	"github.com/elazarl/goproxy"
+	xproxy "golang.org/x/net/proxy"
+)
+
+// upstreamConnectDial returns dialer of CONNECT tunnels that are not intercepted so that they go through the
+// upstream proxy with its credentials, or nil when no upstream proxy is configured
+func upstreamConnectDial(
+	config *types.Configuration,
+	proxy *goproxy.ProxyHttpServer) (func(network, addr string) (net.Conn, error), error) {
+	proxyURL, err := web.UpstreamProxyURL(config)
+	if err != nil || proxyURL == nil {
+		return nil, err
+	}
+	var dial func(network, addr string) (net.Conn, error)
+	switch strings.ToLower(proxyURL.Scheme) {
+	case "socks5", "socks5h":
+		dialer, err := xproxy.FromURL(proxyURL, xproxy.Direct)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create socks5 dialer for upstream proxy due to %w", err)
+		}
+		dial = dialer.Dial
+	default:
+		dial = proxy.NewConnectDialToProxyWithHandler(proxyURL.String(), func(req *http.Request) {
+			req.Header.Set("User-Agent", config.UserAgent)
+			if proxyURL.User != nil {
+				password, _ := proxyURL.User.Password()
+				credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+				req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+			}
+		})
+	}
+	if dial == nil {
+		return nil, fmt.Errorf("failed to create dialer for upstream proxy %s", proxyURL.Redacted())
+	}
+	bypass := config.UpstreamProxy.Bypass
+	return func(network, addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			host = addr
+		}
+		if web.BypassUpstreamProxy(bypass, host) {
+			return net.Dial(network, addr)
+		}
+		return dial(network, addr)
+	}, nil
+}
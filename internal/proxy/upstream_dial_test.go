@@ -0,0 +1,94 @@
+package proxy
+
+import (
+	"bufio"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/elazarl/goproxy"
+	"github.com/stretchr/testify/require"
+)
+
+// startConnectProxy starts a proxy that accepts CONNECT requests and sends received requests to the channel
+func startConnectProxy(t *testing.T) (net.Listener, chan *http.Request) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	received := make(chan *http.Request, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		req, err := http.ReadRequest(bufio.NewReader(conn))
+		if err != nil {
+			return
+		}
+		received <- req
+		_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+	}()
+	return l, received
+}
+
+func Test_ShouldDialConnectTunnelsThroughUpstreamProxy(t *testing.T) {
+	// GIVEN an upstream http proxy with credentials
+	l, received := startConnectProxy(t)
+	defer func() { _ = l.Close() }()
+	config := types.BuildTestConfig()
+	config.UpstreamProxy = types.UpstreamProxyConfig{
+		URL:      "http://" + l.Addr().String(),
+		Username: "user",
+		Password: "secret",
+	}
+	dial, err := upstreamConnectDial(config, goproxy.NewProxyHttpServer())
+	require.NoError(t, err)
+	require.NotNil(t, dial)
+
+	// WHEN dialing a tunnel that is not intercepted
+	conn, err := dial("tcp", "api.example.com:443")
+
+	// THEN it should be sent to upstream proxy as CONNECT with credentials
+	require.NoError(t, err)
+	_ = conn.Close()
+	req := <-received
+	require.Equal(t, http.MethodConnect, req.Method)
+	require.Equal(t, "api.example.com:443", req.Host)
+	require.Equal(t, "Basic "+base64.StdEncoding.EncodeToString([]byte("user:secret")),
+		req.Header.Get("Proxy-Authorization"))
+}
+
+func Test_ShouldDialBypassedConnectTunnelsDirectly(t *testing.T) {
+	// GIVEN an upstream proxy that bypasses local addresses and a local server
+	target, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = target.Close() }()
+	config := types.BuildTestConfig()
+	config.UpstreamProxy = types.UpstreamProxyConfig{URL: "http://127.0.0.1:1", Bypass: []string{"127.0.0.0/8"}}
+	dial, err := upstreamConnectDial(config, goproxy.NewProxyHttpServer())
+	require.NoError(t, err)
+
+	// WHEN dialing a bypassed address
+	conn, err := dial("tcp", target.Addr().String())
+
+	// THEN it should connect directly
+	require.NoError(t, err)
+	_ = conn.Close()
+}
+
+func Test_ShouldCreateSocks5ConnectDialer(t *testing.T) {
+	config := types.BuildTestConfig()
+	config.UpstreamProxy = types.UpstreamProxyConfig{URL: "socks5://127.0.0.1:1080", Username: "user", Password: "secret"}
+	dial, err := upstreamConnectDial(config, goproxy.NewProxyHttpServer())
+	require.NoError(t, err)
+	require.NotNil(t, dial)
+
+	// AND no dialer should be created without upstream proxy
+	config.UpstreamProxy = types.UpstreamProxyConfig{}
+	config.ProxyURL = ""
+	dial, err = upstreamConnectDial(config, goproxy.NewProxyHttpServer())
+	require.NoError(t, err)
+	require.Nil(t, dial)
+}
@@ -0,0 +1,155 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+)
+
+// chartBar is a horizontal bar of latency chart
+type chartBar struct {
+	Label string
+	Value float64
+	Width float64
+	Y     int
+}
+
+// latencyChart is an SVG bar chart of latencies in millis
+type latencyChart struct {
+	Title  string
+	Bars   []chartBar
+	Height int
+}
+
+const (
+	chartRowHeight = 22
+	chartBarWidth  = 450
+)
+
+func newLatencyChart(title string, labels []string, values map[string]float64) *latencyChart {
+	maxValue := 0.0
+	for _, v := range values {
+		if v > maxValue {
+			maxValue = v
+		}
+	}
+	chart := &latencyChart{Title: title, Height: len(labels)*chartRowHeight + 10}
+	for i, label := range labels {
+		width := 0.0
+		if maxValue > 0 {
+			width = values[label] / maxValue * chartBarWidth
+		}
+		chart.Bars = append(chart.Bars, chartBar{Label: label, Value: values[label], Width: width, Y: i*chartRowHeight + 5})
+	}
+	return chart
+}
+
+type htmlTestCase struct {
+	TestCase
+	Messages []string
+}
+
+type htmlReport struct {
+	Suite      string
+	Tests      int
+	Passed     int
+	Failed     int
+	Flaky      int
+	Mismatched int
+	Cases      []htmlTestCase
+	Coverage   *types.CoverageSummary
+	Charts     []*latencyChart
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"pct": func(v float64) string { return fmt.Sprintf("%.1f%%", v) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Contract Report: {{.Suite}}</title>
+<style>
+body { font-family: -apple-system, Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ddd; padding: 4px 10px; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+.pass { color: #1a7f37; } .fail { color: #cf222e; } .flaky { color: #9a6700; }
+pre { white-space: pre-wrap; margin: 0; }
+.bar { fill: #0969da; } .label { font-size: 12px; }
+</style>
+</head>
+<body>
+<h1>Contract Report: {{.Suite}}</h1>
+<table>
+<tr><th>Tests</th><th>Passed</th><th>Failed</th><th>Flaky</th><th>Mismatched</th></tr>
+<tr><td>{{.Tests}}</td><td class="pass">{{.Passed}}</td><td class="fail">{{.Failed}}</td><td class="flaky">{{.Flaky}}</td><td>{{.Mismatched}}</td></tr>
+</table>
+{{with .Coverage}}
+<h2>Coverage</h2>
+<table>
+<tr><th>Coverage</th><th>Covered paths</th><th>Total paths</th></tr>
+<tr><td>{{pct .Coverage}}</td><td>{{.CoveredPaths}}</td><td>{{.TotalPaths}}</td></tr>
+</table>
+{{if .MethodCoverage}}<table><tr><th>Method</th><th>Coverage</th></tr>{{range $m, $v := .MethodCoverage}}<tr><td>{{$m}}</td><td>{{pct $v}}</td></tr>{{end}}</table>{{end}}
+{{if .UncoveredPaths}}<p>Uncovered paths:</p><ul>{{range .UncoveredPaths}}<li><code>{{.}}</code></li>{{end}}</ul>{{end}}
+{{end}}
+{{range .Charts}}
+<h2>{{.Title}}</h2>
+<svg width="900" height="{{.Height}}" role="img" aria-label="{{.Title}}">
+{{range .Bars}}<text class="label" x="0" y="{{.Y}}" dy="13">{{.Label}}</text>
+<rect class="bar" x="300" y="{{.Y}}" height="16" width="{{printf "%.1f" .Width}}"></rect>
+<text class="label" x="760" y="{{.Y}}" dy="13">{{printf "%.1f" .Value}} ms</text>
+{{end}}</svg>
+{{end}}
+<h2>Tests</h2>
+<table>
+<tr><th>Test</th><th>Status</th><th>Latency (ms)</th><th>Details</th></tr>
+{{range .Cases}}<tr>
+<td>{{.Name}}</td>
+{{if .Failed}}<td class="fail">fail</td>{{else if .Flaky}}<td class="flaky">flaky</td>{{else}}<td class="pass">pass</td>{{end}}
+<td>{{.LatencyMillis}}</td>
+<td>{{if .Flaky}}<pre>attempt {{.Attempts}} passed after: {{.FlakyFailure}}</pre>{{end}}{{if .Failed}}<pre>{{.Failure}}</pre>{{with .Detail}}{{if .URL}}<div><code>{{.URL}}</code></div>{{end}}{{end}}{{if .Messages}}<ul>{{range .Messages}}<li>{{.}}</li>{{end}}</ul>{{end}}{{end}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+func renderHTML(suite string, res *types.ProducerContractResponse, cases []TestCase) ([]byte, error) {
+	report := htmlReport{Suite: suite, Tests: len(cases), Mismatched: res.Mismatched, Coverage: res.Coverage}
+	for _, tc := range cases {
+		if tc.Failed() {
+			report.Failed++
+		} else if tc.Flaky() {
+			report.Flaky++
+		}
+		report.Cases = append(report.Cases, htmlTestCase{TestCase: tc, Messages: FailureMessages(tc.Detail)})
+	}
+	report.Passed = report.Tests - report.Failed - report.Flaky
+	if names, latencies := scenarioLatencies(cases); len(res.Latencies) > 0 {
+		report.Charts = append(report.Charts, newLatencyChart("Average latency by scenario", names, latencies))
+	}
+	if res.Load != nil {
+		for _, q := range []struct {
+			title string
+			value func(l types.LoadLatency) float64
+		}{
+			{"Load p50 latency", func(l types.LoadLatency) float64 { return l.P50 }},
+			{"Load p90 latency", func(l types.LoadLatency) float64 { return l.P90 }},
+			{"Load p99 latency", func(l types.LoadLatency) float64 { return l.P99 }},
+		} {
+			values := make(map[string]float64)
+			for name, stats := range res.Load.Scenarios {
+				values[name] = q.value(stats.Latency)
+			}
+			report.Charts = append(report.Charts, newLatencyChart(q.title, sortedKeys(values), values))
+		}
+	}
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, report); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,97 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     float64          `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Time       float64         `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	// FlakyFailure follows surefire for tests that passed on rerun
+	FlakyFailure *junitFailure `xml:"flakyFailure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+func renderJUnit(suite string, res *types.ProducerContractResponse, cases []TestCase) ([]byte, error) {
+	testSuite := junitTestSuite{
+		Name:  suite,
+		Tests: len(cases),
+	}
+	for _, name := range sortedKeys(res.URLs) {
+		testSuite.Properties = append(testSuite.Properties, junitProperty{
+			Name: "url", Value: fmt.Sprintf("%s (%dx)", name, res.URLs[name])})
+	}
+	// mismatched scenarios were never executed so they are reported as a property instead of skipped test cases
+	if res.Mismatched > 0 {
+		testSuite.Properties = append(testSuite.Properties, junitProperty{
+			Name: "mismatched", Value: fmt.Sprintf("%d", res.Mismatched)})
+	}
+	if res.Coverage != nil {
+		testSuite.Properties = append(testSuite.Properties, junitProperty{
+			Name: "coverage", Value: fmt.Sprintf("%.1f%%", res.Coverage.Coverage)})
+	}
+	for _, tc := range cases {
+		secs := float64(tc.LatencyMillis) / 1000
+		testSuite.Time += secs
+		testCase := junitTestCase{Name: tc.Name, ClassName: suite + "." + tc.Scenario, Time: secs}
+		if tc.Failed() {
+			testSuite.Failures++
+			failureType := "ContractFailure"
+			if tc.Detail != nil {
+				failureType = "ContractValidationError"
+			}
+			body := append([]string{tc.Failure}, FailureMessages(tc.Detail)...)
+			testCase.Failure = &junitFailure{Message: tc.Failure, Type: failureType, Body: strings.Join(body, "\n")}
+		} else if tc.Flaky() {
+			testCase.FlakyFailure = &junitFailure{Message: tc.FlakyFailure, Type: "ContractFailure",
+				Body: fmt.Sprintf("passed on attempt %d after: %s", tc.Attempts, tc.FlakyFailure)}
+		}
+		testSuite.Cases = append(testSuite.Cases, testCase)
+	}
+	suites := junitTestSuites{
+		Name:     suite,
+		Tests:    testSuite.Tests,
+		Failures: testSuite.Failures,
+		Time:     testSuite.Time,
+		Suites:   []junitTestSuite{testSuite},
+	}
+	b, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
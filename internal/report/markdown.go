@@ -0,0 +1,64 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+)
+
+func renderMarkdown(suite string, res *types.ProducerContractResponse, cases []TestCase) []byte {
+	var sb strings.Builder
+	failed, flaky := 0, 0
+	for _, tc := range cases {
+		if tc.Failed() {
+			failed++
+		} else if tc.Flaky() {
+			flaky++
+		}
+	}
+	sb.WriteString(fmt.Sprintf("# Contract Report: %s\n\n", suite))
+	sb.WriteString("| Tests | Passed | Failed | Flaky | Mismatched |\n|------:|-------:|-------:|------:|-----------:|\n")
+	sb.WriteString(fmt.Sprintf("| %d | %d | %d | %d | %d |\n\n", len(cases), len(cases)-failed-flaky, failed, flaky, res.Mismatched))
+
+	sb.WriteString("| Test | Status | Latency (ms) |\n|------|--------|-------------:|\n")
+	for _, tc := range cases {
+		status := "✅ pass"
+		if tc.Failed() {
+			status = "❌ fail"
+		} else if tc.Flaky() {
+			status = "⚠️ flaky"
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %d |\n", escapeMarkdown(tc.Name), status, tc.LatencyMillis))
+	}
+
+	if failed > 0 {
+		sb.WriteString("\n## Failures\n")
+		for _, tc := range cases {
+			if !tc.Failed() {
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("\n### %s\n\n", escapeMarkdown(tc.Name)))
+			if tc.Detail != nil && tc.Detail.URL != "" {
+				sb.WriteString(fmt.Sprintf("URL: `%s`\n\n", tc.Detail.URL))
+			}
+			sb.WriteString(fmt.Sprintf("```\n%s\n```\n", tc.Failure))
+			for _, message := range FailureMessages(tc.Detail) {
+				sb.WriteString(fmt.Sprintf("- %s\n", escapeMarkdown(message)))
+			}
+		}
+	}
+
+	if res.Coverage != nil {
+		sb.WriteString(fmt.Sprintf("\n## Coverage\n\n%.1f%% (%d/%d paths)\n",
+			res.Coverage.Coverage, res.Coverage.CoveredPaths, res.Coverage.TotalPaths))
+		for _, path := range res.Coverage.UncoveredPaths {
+			sb.WriteString(fmt.Sprintf("- uncovered: `%s`\n", path))
+		}
+	}
+	return []byte(sb.String())
+}
+
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
@@ -0,0 +1,188 @@
+package report
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+)
+
+// Report formats of contract runs
+const (
+	JUnit    = "junit"
+	HTML     = "html"
+	Markdown = "markdown"
+	SARIF    = "sarif"
+)
+
+// Formats supported by Render
+var Formats = []string{JUnit, HTML, Markdown, SARIF}
+
+// TestCase is an execution of a scenario in a contract run
+type TestCase struct {
+	// Name of the execution such as get_todo_0
+	Name string
+	// Scenario name without execution index
+	Scenario string
+	// Iteration is the execution index of the scenario
+	Iteration int
+	// Failure message if execution failed
+	Failure string
+	// Detail of contract validation failure
+	Detail *types.ContractValidationDetail
+	// LatencyMillis of the execution
+	LatencyMillis int64
+	// FlakyFailure is the failure of an earlier attempt if execution passed on retry
+	FlakyFailure string
+	// Attempts of the execution with retry policy
+	Attempts int
+}
+
+// Failed returns true if execution failed
+func (tc TestCase) Failed() bool {
+	return tc.Failure != ""
+}
+
+var executionSuffix = regexp.MustCompile(`_(\d+|load|dry)$`)
+
+// Flaky returns true if execution passed on retry
+func (tc TestCase) Flaky() bool {
+	return tc.FlakyFailure != ""
+}
+
+// BuildTestCases converts results, errors, flaky errors and latencies of contract response into test cases sorted by name
+func BuildTestCases(res *types.ProducerContractResponse) (cases []TestCase) {
+	names := make(map[string]bool)
+	for name := range res.Results {
+		if !strings.HasSuffix(name, "_coverage") {
+			names[name] = true
+		}
+	}
+	for name := range res.Errors {
+		names[name] = true
+	}
+	for name := range res.Latencies {
+		names[name] = true
+	}
+	for name := range res.FlakyErrors {
+		names[name] = true
+	}
+	for name := range names {
+		iteration := 0
+		if m := executionSuffix.FindStringSubmatch(name); m != nil {
+			iteration, _ = strconv.Atoi(m[1])
+		}
+		cases = append(cases, TestCase{
+			Name:          name,
+			Scenario:      executionSuffix.ReplaceAllString(name, ""),
+			Iteration:     iteration,
+			Failure:       res.Errors[name],
+			Detail:        res.ErrorDetails[name],
+			LatencyMillis: res.Latencies[name],
+			FlakyFailure:  res.FlakyErrors[name],
+			Attempts:      res.Attempts[name],
+		})
+	}
+	sort.Slice(cases, func(i, j int) bool {
+		return cases[i].Name < cases[j].Name
+	})
+	return
+}
+
+// Render formats contract response of the suite (group) as junit, html, markdown or sarif and returns it
+// with its content type.
+func Render(format string, suite string, res *types.ProducerContractResponse) ([]byte, string, error) {
+	cases := BuildTestCases(res)
+	switch strings.ToLower(format) {
+	case JUnit:
+		b, err := renderJUnit(suite, res, cases)
+		return b, "application/xml", err
+	case HTML:
+		b, err := renderHTML(suite, res, cases)
+		return b, "text/html; charset=utf-8", err
+	case Markdown, "md":
+		return renderMarkdown(suite, res, cases), "text/markdown; charset=utf-8", nil
+	case SARIF:
+		b, err := renderSARIF(cases)
+		return b, "application/sarif+json", err
+	default:
+		return nil, "", fmt.Errorf("unsupported report format '%s', supported formats are %v", format, Formats)
+	}
+}
+
+// failureItem is a diff field or schema violation of failed execution
+type failureItem struct {
+	rule    string
+	message string
+}
+
+// failureItems describes status, missing fields, mismatches and schema violations of the detail
+func failureItems(detail *types.ContractValidationDetail) (items []failureItem) {
+	if detail == nil {
+		return
+	}
+	add := func(rule string, format string, args ...any) {
+		items = append(items, failureItem{rule: rule, message: fmt.Sprintf(format, args...)})
+	}
+	if detail.ExpectedStatusCode > 0 && detail.StatusCode != detail.ExpectedStatusCode {
+		add("status-mismatch", "status: expected %d, got %d", detail.ExpectedStatusCode, detail.StatusCode)
+	}
+	for _, field := range detail.MissingFields {
+		add("missing-field", "missing field: %s", field)
+	}
+	for _, field := range detail.ExtraFields {
+		add("extra-field", "extra field: %s", field)
+	}
+	for _, field := range sortedKeys(detail.TypeMismatches) {
+		add("type-mismatch", "type mismatch: %s %s", field, detail.TypeMismatches[field])
+	}
+	for _, field := range sortedKeys(detail.ValueMismatches) {
+		m := detail.ValueMismatches[field]
+		add("value-mismatch", "value mismatch: %s expected %v, got %v", field, m.Expected, m.Actual)
+	}
+	for _, header := range sortedKeys(detail.HeaderMismatches) {
+		m := detail.HeaderMismatches[header]
+		add("header-mismatch", "header mismatch: %s expected %v, got %v", header, m.Expected, m.Actual)
+	}
+	for _, violation := range detail.SchemaViolations {
+		add("schema-violation", "schema violation: %s %s", violation.Field, violation.Message)
+	}
+	return
+}
+
+// FailureMessages describes status, missing fields, mismatches and schema violations of the detail
+func FailureMessages(detail *types.ContractValidationDetail) (messages []string) {
+	for _, item := range failureItems(detail) {
+		messages = append(messages, item.message)
+	}
+	return
+}
+
+// scenarioLatencies returns average latency in millis by scenario
+func scenarioLatencies(cases []TestCase) (names []string, latencies map[string]float64) {
+	latencies = make(map[string]float64)
+	counts := make(map[string]int)
+	for _, tc := range cases {
+		if _, ok := counts[tc.Scenario]; !ok {
+			names = append(names, tc.Scenario)
+		}
+		latencies[tc.Scenario] += float64(tc.LatencyMillis)
+		counts[tc.Scenario]++
+	}
+	for name, n := range counts {
+		latencies[name] /= float64(n)
+	}
+	return
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
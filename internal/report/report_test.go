@@ -0,0 +1,164 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldBuildTestCasesFromContractResponse(t *testing.T) {
+	// GIVEN a contract response with passed, failed and empty executions
+	res := newTestContractResponse()
+
+	// WHEN building test cases
+	cases := BuildTestCases(res)
+
+	// THEN each execution should be a test case except coverage results
+	require.Len(t, cases, 3)
+	require.Equal(t, "create_todo_0", cases[0].Name)
+	require.Equal(t, "create_todo", cases[0].Scenario)
+	require.True(t, cases[0].Failed())
+	require.NotNil(t, cases[0].Detail)
+	require.Equal(t, "get_todo_0", cases[1].Name)
+	require.False(t, cases[1].Failed())
+	require.Equal(t, int64(120), cases[1].LatencyMillis)
+	require.Equal(t, "get_todo_1", cases[2].Name)
+}
+
+func Test_ShouldRenderJUnitReport(t *testing.T) {
+	// GIVEN a contract response
+	res := newTestContractResponse()
+	res.AddMismatched()
+
+	// WHEN rendering junit
+	b, contentType, err := Render(JUnit, "todos", res)
+
+	// THEN failures should include validation details
+	require.NoError(t, err)
+	require.Equal(t, "application/xml", contentType)
+	suites := junitTestSuites{}
+	require.NoError(t, xml.Unmarshal(b, &suites))
+	require.Equal(t, 3, suites.Tests)
+	require.Equal(t, 1, suites.Failures)
+	require.Len(t, suites.Suites[0].Cases, 3)
+	failure := suites.Suites[0].Cases[0].Failure
+	require.NotNil(t, failure)
+	require.Equal(t, "ContractValidationError", failure.Type)
+	require.Contains(t, failure.Body, "missing field: id")
+	require.Contains(t, failure.Body, "value mismatch: title expected buy milk, got sell milk")
+	require.Contains(t, failure.Body, "schema violation: completed expected boolean")
+	require.Equal(t, 0.12, suites.Suites[0].Cases[1].Time)
+	require.Equal(t, "todos.get_todo", suites.Suites[0].Cases[1].ClassName)
+	// AND mismatched scenarios should not be counted as skipped without test cases
+	require.NotContains(t, string(b), "skipped=")
+	require.Contains(t, suites.Suites[0].Properties, junitProperty{Name: "mismatched", Value: "1"})
+}
+
+func Test_ShouldRenderSARIFReport(t *testing.T) {
+	// GIVEN a contract response
+	res := newTestContractResponse()
+
+	// WHEN rendering sarif
+	b, _, err := Render(SARIF, "todos", res)
+
+	// THEN each diff field and schema violation should be a result
+	require.NoError(t, err)
+	log := sarifLog{}
+	require.NoError(t, json.Unmarshal(b, &log))
+	require.Equal(t, "2.1.0", log.Version)
+	var rules []string
+	for _, result := range log.Runs[0].Results {
+		rules = append(rules, result.RuleID)
+		require.Equal(t, "https://api.example.com/todos", result.Locations[0].LogicalLocations[0].FullyQualifiedName)
+	}
+	require.Equal(t, []string{"status-mismatch", "missing-field", "value-mismatch", "schema-violation"}, rules)
+}
+
+func Test_ShouldRenderHTMLAndMarkdownReports(t *testing.T) {
+	// GIVEN a contract response with coverage
+	res := newTestContractResponse()
+	res.Coverage = &types.CoverageSummary{TotalPaths: 4, CoveredPaths: 3, Coverage: 75, UncoveredPaths: []string{"DELETE /todos/{id}"}}
+
+	// WHEN rendering html
+	b, contentType, err := Render(HTML, "todos", res)
+
+	// THEN it should embed coverage, latency chart and escaped failures
+	require.NoError(t, err)
+	require.Equal(t, "text/html; charset=utf-8", contentType)
+	html := string(b)
+	require.Contains(t, html, "<svg")
+	require.Contains(t, html, "Average latency by scenario")
+	require.Contains(t, html, "75.0%")
+	require.Contains(t, html, "DELETE /todos/{id}")
+	require.Contains(t, html, "status 500 &lt;html&gt;")
+
+	// WHEN rendering markdown
+	b, _, err = Render(Markdown, "todos", res)
+
+	// THEN it should summarize tests, failures and coverage
+	require.NoError(t, err)
+	md := string(b)
+	require.Contains(t, md, "| 3 | 2 | 1 | 0 | 0 |")
+	require.Contains(t, md, "### create_todo_0")
+	require.Contains(t, md, "- missing field: id")
+	require.True(t, strings.Contains(md, "75.0% (3/4 paths)"))
+
+	// AND unknown formats should fail
+	_, _, err = Render("pdf", "todos", res)
+	require.Error(t, err)
+}
+
+func Test_ShouldReportExecutionsThatPassedOnRetryAsFlaky(t *testing.T) {
+	// GIVEN a contract response with an execution that passed on retry
+	res := newTestContractResponse()
+	res.AddFlaky("list_todos_0", nil, fmt.Errorf("status 502"))
+	res.AddAttempts("list_todos_0", 2)
+
+	// WHEN rendering junit
+	b, _, err := Render(JUnit, "todos", res)
+
+	// THEN flaky execution should pass with its flaky failure
+	require.NoError(t, err)
+	suites := junitTestSuites{}
+	require.NoError(t, xml.Unmarshal(b, &suites))
+	require.Equal(t, 1, suites.Failures)
+	flaky := suites.Suites[0].Cases[3]
+	require.Equal(t, "list_todos_0", flaky.Name)
+	require.Nil(t, flaky.Failure)
+	require.NotNil(t, flaky.FlakyFailure)
+	require.Equal(t, "passed on attempt 2 after: status 502", flaky.FlakyFailure.Body)
+
+	// WHEN rendering markdown
+	b, _, err = Render(Markdown, "todos", res)
+
+	// THEN flaky execution should be counted separately
+	require.NoError(t, err)
+	require.Contains(t, string(b), "| 4 | 2 | 1 | 1 | 0 |")
+	require.Contains(t, string(b), "| list_todos_0 | ⚠️ flaky | 0 |")
+}
+
+func newTestContractResponse() *types.ProducerContractResponse {
+	res := types.NewProducerContractResponse()
+	res.Add("get_todo_0", map[string]any{"id": 1}, nil)
+	res.AddLatency("get_todo_0", 120)
+	res.Add("get_todo_1", nil, nil)
+	res.AddLatency("get_todo_1", 80)
+	res.AddResult("get_todo_coverage", map[string]any{"coverage": 1})
+	res.Add("create_todo_0", nil, fmt.Errorf("status 500 <html>"))
+	res.AddLatency("create_todo_0", 300)
+	res.SetErrorDetail("create_todo_0", &types.ContractValidationDetail{
+		Scenario:           "create_todo",
+		URL:                "https://api.example.com/todos",
+		StatusCode:         500,
+		ExpectedStatusCode: 201,
+		MissingFields:      []string{"id"},
+		ValueMismatches:    map[string]types.ValueMismatch{"title": {Expected: "buy milk", Actual: "sell milk"}},
+		SchemaViolations:   []types.SchemaViolation{{Field: "completed", Message: "expected boolean"}},
+	})
+	return res
+}
@@ -0,0 +1,104 @@
+package report
+
+import (
+	"encoding/json"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
+	Kind               string `json:"kind"`
+}
+
+var sarifRules = []sarifRule{
+	{ID: "contract-failure", ShortDescription: sarifMessage{Text: "Contract execution failed"}},
+	{ID: "status-mismatch", ShortDescription: sarifMessage{Text: "Response status differs from contract"}},
+	{ID: "missing-field", ShortDescription: sarifMessage{Text: "Response is missing field of contract"}},
+	{ID: "extra-field", ShortDescription: sarifMessage{Text: "Response has field not in contract"}},
+	{ID: "type-mismatch", ShortDescription: sarifMessage{Text: "Response field type differs from contract"}},
+	{ID: "value-mismatch", ShortDescription: sarifMessage{Text: "Response field value differs from contract"}},
+	{ID: "header-mismatch", ShortDescription: sarifMessage{Text: "Response header differs from contract"}},
+	{ID: "schema-violation", ShortDescription: sarifMessage{Text: "Response violates OpenAPI schema"}},
+}
+
+func renderSARIF(cases []TestCase) ([]byte, error) {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "api-mock-service",
+			InformationURI: "https://github.com/bhatti/api-mock-service",
+			Rules:          sarifRules,
+		}},
+		Results: make([]sarifResult, 0),
+	}
+	for _, tc := range cases {
+		if !tc.Failed() {
+			continue
+		}
+		location := sarifLocation{LogicalLocations: []sarifLogicalLocation{{Name: tc.Name, Kind: "function"}}}
+		items := failureItems(tc.Detail)
+		if tc.Detail != nil {
+			location.LogicalLocations[0].FullyQualifiedName = tc.Detail.URL
+		}
+		if len(items) == 0 {
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    "contract-failure",
+				Level:     "error",
+				Message:   sarifMessage{Text: tc.Name + ": " + tc.Failure},
+				Locations: []sarifLocation{location},
+			})
+			continue
+		}
+		for _, item := range items {
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    item.rule,
+				Level:     "error",
+				Message:   sarifMessage{Text: tc.Name + ": " + item.message},
+				Locations: []sarifLocation{location},
+			})
+		}
+	}
+	return json.MarshalIndent(sarifLog{Schema: sarifSchema, Version: "2.1.0", Runs: []sarifRun{run}}, "", "  ")
+}
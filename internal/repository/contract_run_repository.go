@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"github.com/bhatti/api-mock-service/internal/types"
+)
+
+// ContractRunRepository defines data store for recorded producer contract runs
+type ContractRunRepository interface {
+	// Save saves contract run
+	Save(run *types.ContractRun) error
+
+	// Load loads contract run by id
+	Load(id string) (*types.ContractRun, error)
+
+	// List returns most recent runs of the group (all groups if empty) up to limit
+	List(group string, limit int) ([]*types.ContractRun, error)
+
+	// Delete removes contract run
+	Delete(id string) error
+}
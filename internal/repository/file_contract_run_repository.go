@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const contractRunExt = ".json"
+
+// FileContractRunRepository implements storage for contract runs using local files
+type FileContractRunRepository struct {
+	dir string
+}
+
+// NewFileContractRunRepository creates new instance for ContractRunRepository
+func NewFileContractRunRepository(
+	config *types.Configuration,
+) (*FileContractRunRepository, error) {
+	dir := filepath.Join(config.DataDir, "contract_runs")
+	if err := mkdir(dir); err != nil {
+		return nil, err
+	}
+	return &FileContractRunRepository{
+		dir: dir,
+	}, nil
+}
+
+// Save saves contract run
+func (cr *FileContractRunRepository) Save(run *types.ContractRun) error {
+	if run.ID == "" {
+		return fmt.Errorf("contract run id is not specified")
+	}
+	b, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(cr.buildName(run.ID), b, 0644)
+}
+
+// Load loads contract run by id
+func (cr *FileContractRunRepository) Load(id string) (*types.ContractRun, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return nil, fmt.Errorf("invalid contract run id '%s'", id)
+	}
+	b, err := os.ReadFile(cr.buildName(id))
+	if err != nil {
+		return nil, err
+	}
+	run := &types.ContractRun{}
+	if err = json.Unmarshal(b, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// List returns most recent runs of the group (all groups if empty) up to limit
+func (cr *FileContractRunRepository) List(group string, limit int) (runs []*types.ContractRun, err error) {
+	files, err := os.ReadDir(cr.dir)
+	if err != nil {
+		return nil, err
+	}
+	runs = make([]*types.ContractRun, 0)
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), contractRunExt) {
+			continue
+		}
+		run, err := cr.Load(strings.TrimSuffix(file.Name(), contractRunExt))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"Component": "FileContractRunRepository",
+				"File":      file.Name(),
+				"Error":     err,
+			}).Warnf("failed to load contract run")
+			continue
+		}
+		if group == "" || run.Group == group {
+			runs = append(runs, run)
+		}
+	}
+	sort.SliceStable(runs, func(i, j int) bool {
+		if runs[i].StartedAt.Equal(runs[j].StartedAt) {
+			return runs[i].ID > runs[j].ID
+		}
+		return runs[i].StartedAt.After(runs[j].StartedAt)
+	})
+	if limit > 0 && len(runs) > limit {
+		runs = runs[:limit]
+	}
+	return
+}
+
+// Delete removes contract run
+func (cr *FileContractRunRepository) Delete(id string) error {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return fmt.Errorf("invalid contract run id '%s'", id)
+	}
+	return os.Remove(cr.buildName(id))
+}
+
+func (cr *FileContractRunRepository) buildName(id string) string {
+	return filepath.Join(cr.dir, id+contractRunExt)
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldSaveListAndDeleteContractRuns(t *testing.T) {
+	// GIVEN a contract run repository
+	runRepository, err := NewFileContractRunRepository(&types.Configuration{DataDir: t.TempDir()})
+	require.NoError(t, err)
+	started := time.Now().UTC().Truncate(time.Second)
+	for i, group := range []string{"todos", "users", "todos"} {
+		// WHEN saving runs
+		err = runRepository.Save(&types.ContractRun{
+			ID:        string(rune('a' + i)),
+			Group:     group,
+			Label:     "abc123",
+			StartedAt: started.Add(time.Duration(i) * time.Minute),
+			Scenarios: map[string]*types.ScenarioOutcome{"get_todo": {Executions: 1, Passed: 1}},
+		})
+		// THEN it should succeed
+		require.NoError(t, err)
+	}
+
+	// AND should load saved run
+	run, err := runRepository.Load("b")
+	require.NoError(t, err)
+	require.Equal(t, "users", run.Group)
+	require.Equal(t, 1, run.Scenarios["get_todo"].Passed)
+
+	// AND should list most recent runs of group
+	runs, err := runRepository.List("todos", 0)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	require.Equal(t, "c", runs[0].ID)
+	runs, err = runRepository.List("", 1)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+
+	// AND should delete run
+	require.NoError(t, runRepository.Delete("c"))
+	_, err = runRepository.Load("c")
+	require.Error(t, err)
+	_, err = runRepository.Load("../c")
+	require.Error(t, err)
+}
@@ -37,6 +37,14 @@ func (cr *FileMockFixtureRepository) Get(
 	return os.ReadFile(fileName)
 }
 
+// Open contents by id for streaming
+func (cr *FileMockFixtureRepository) Open(
+	method types.MethodType,
+	name string,
+	path string) (*os.File, error) {
+	return os.Open(cr.buildFileName(method, name, path))
+}
+
 // GetFixtureNames returns list of fixture names for given Method and Path
 func (cr *FileMockFixtureRepository) GetFixtureNames(
 	method types.MethodType,
@@ -55,6 +55,9 @@ func (gcr *FileGroupConfigRepository) Save(name string, gc *types.GroupConfig) (
 	if name == "" {
 		return fmt.Errorf("oapi spec name is not specified")
 	}
+	if err = gc.Validate(); err != nil {
+		return err
+	}
 	b, err := json.MarshalIndent(gc, "", "  ")
 	if err != nil {
 		return err
@@ -75,6 +78,9 @@ func (gcr *FileGroupConfigRepository) Load(name string) (*types.GroupConfig, err
 	if err != nil {
 		return nil, err
 	}
+	if err = gc.Validate(); err != nil {
+		return nil, err
+	}
 	return gc, nil
 }
 
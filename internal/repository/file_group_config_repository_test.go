@@ -38,3 +38,18 @@ func Test_ShouldSaveAndGetGroupConfig(t *testing.T) {
 	vars = groupConfigRepository.Variables("")
 	require.Equal(t, 2, len(vars))
 }
+
+func Test_ShouldNotSaveGroupConfigWithUnsupportedJSONPatch(t *testing.T) {
+	// GIVEN a contents repository
+	groupConfigRepository, err := NewFileGroupConfigRepository(&types.Configuration{DataDir: "../../mock_tests"})
+	require.NoError(t, err)
+	gc := &types.GroupConfig{RewriteRules: []*types.RewriteRule{
+		{Name: "bad", JSONPatch: []*types.JSONPatchOperation{{Op: "delete", Path: "/id"}}},
+	}}
+	// WHEN saving group config with unsupported op
+	err = groupConfigRepository.Save("bad_json_patch", gc)
+	// THEN it should fail
+	require.Error(t, err)
+	_, err = groupConfigRepository.Load("bad_json_patch")
+	require.Error(t, err)
+}
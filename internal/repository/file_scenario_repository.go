@@ -25,12 +25,14 @@ import (
 type FileAPIScenarioRepository struct {
 	mutex            sync.RWMutex
 	keysByMethodPath map[string]map[string]*types.APIKeyData
-	config           *types.Configuration
-	contractDir      string
-	historyDir       string
-	varsDir          string
-	maxHistory       int
-	debug            bool
+	// latencies caches p50 and p95 of recorded latencies by method, group and path until scenarios change
+	latencies   map[string][2]int64
+	config      *types.Configuration
+	contractDir string
+	historyDir  string
+	varsDir     string
+	maxHistory  int
+	debug       bool
 }
 
 // NewFileAPIScenarioRepository creates new instance for api scenarios
@@ -60,6 +62,7 @@ func NewFileAPIScenarioRepository(
 		varsDir:          varsDir,
 		debug:            config.Debug,
 		keysByMethodPath: make(map[string]map[string]*types.APIKeyData),
+		latencies:        make(map[string][2]int64),
 	}
 
 	err = repo.visit(func(keyData *types.APIKeyData) bool {
@@ -198,7 +201,11 @@ func (sr *FileAPIScenarioRepository) LoadRaw(
 func (sr *FileAPIScenarioRepository) Delete(
 	method types.MethodType, scenarioName string, path string) error {
 	fileName := sr.buildFileName(method, scenarioName, path)
-	return os.Remove(fileName)
+	if err := os.Remove(fileName); err != nil {
+		return err
+	}
+	sr.removeKeyData(&types.APIKeyData{Method: method, Name: scenarioName, Path: path})
+	return nil
 }
 
 // ListScenarioKeyData returns keys for all scenarios
@@ -249,6 +256,28 @@ func (sr *FileAPIScenarioRepository) LookupAllByPath(path string) []*types.APIKe
 	return res
 }
 
+// LatencyPercentiles returns p50 and p95 of recorded latencies of all scenarios with same method, group and path
+func (sr *FileAPIScenarioRepository) LatencyPercentiles(keyData *types.APIKeyData) (p50 int64, p95 int64) {
+	key := string(keyData.Method) + ":" + keyData.Group + ":" + keyData.Path
+	sr.mutex.RLock()
+	cached, ok := sr.latencies[key]
+	sr.mutex.RUnlock()
+	if ok {
+		return cached[0], cached[1]
+	}
+	var samples []int64
+	for _, next := range sr.LookupAllByPath(keyData.Path) {
+		if next.Method == keyData.Method && next.Group == keyData.Group {
+			samples = append(samples, next.LatencyMillis)
+		}
+	}
+	p50, p95 = types.LatencyPercentiles(samples)
+	sr.mutex.Lock()
+	sr.latencies[key] = [2]int64{p50, p95}
+	sr.mutex.Unlock()
+	return
+}
+
 // LookupAllByGroup finds matching scenarios by group
 func (sr *FileAPIScenarioRepository) LookupAllByGroup(group string) []*types.APIKeyData {
 	sr.mutex.RLock()
@@ -408,6 +437,9 @@ func (sr *FileAPIScenarioRepository) SaveHistory(
 	for name := range web.IgnoredRequestHeaders {
 		delete(scenario.Request.Headers, name)
 	}
+	if sr.config != nil {
+		sr.config.Redaction.RedactScenario(scenario)
+	}
 	if u, err := scenario.GetURL(url); err == nil {
 		url = u.String()
 	}
@@ -613,11 +645,25 @@ func mkdir(dir string) error {
 	return nil
 }
 
+func (sr *FileAPIScenarioRepository) removeKeyData(keyData *types.APIKeyData) {
+	sr.mutex.Lock()
+	defer func() {
+		sr.mutex.Unlock()
+	}()
+	sr.latencies = make(map[string][2]int64)
+	keyMap := sr.keysByMethodPath[keyData.PartialMethodPathKey()]
+	if existing := keyMap[keyData.MethodNamePathPrefixKey()]; existing != nil &&
+		types.NormalizePath(existing.Path, '/') == types.NormalizePath(keyData.Path, '/') {
+		delete(keyMap, keyData.MethodNamePathPrefixKey())
+	}
+}
+
 func (sr *FileAPIScenarioRepository) addKeyData(keyData *types.APIKeyData) {
 	sr.mutex.Lock()
 	defer func() {
 		sr.mutex.Unlock()
 	}()
+	sr.latencies = make(map[string][2]int64)
 	keyMap := sr.keysByMethodPath[keyData.PartialMethodPathKey()]
 	if keyMap == nil {
 		keyMap = make(map[string]*types.APIKeyData)
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+const workflowExt = ".yaml"
+
+// FileWorkflowRepository implements storage for workflows using local files
+type FileWorkflowRepository struct {
+	dir string
+}
+
+// NewFileWorkflowRepository creates new instance for WorkflowRepository
+func NewFileWorkflowRepository(
+	config *types.Configuration,
+) (*FileWorkflowRepository, error) {
+	dir := filepath.Join(config.DataDir, "workflows")
+	if err := mkdir(dir); err != nil {
+		return nil, err
+	}
+	return &FileWorkflowRepository{
+		dir: dir,
+	}, nil
+}
+
+// Save saves workflow of the group
+func (wr *FileWorkflowRepository) Save(group string, workflow *types.Workflow) error {
+	if group == "" {
+		return fmt.Errorf("workflow group is not specified")
+	}
+	workflow.Group = group
+	if err := workflow.Validate(); err != nil {
+		return err
+	}
+	b, err := yaml.Marshal(workflow)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(wr.buildName(group), b, 0644)
+}
+
+// Load loads workflow of the group
+func (wr *FileWorkflowRepository) Load(group string) (*types.Workflow, error) {
+	if group == "" {
+		return nil, fmt.Errorf("workflow group is not specified")
+	}
+	b, err := os.ReadFile(wr.buildName(group))
+	if err != nil {
+		return nil, err
+	}
+	workflow := &types.Workflow{}
+	if err = yaml.Unmarshal(b, workflow); err != nil {
+		return nil, err
+	}
+	return workflow, nil
+}
+
+// GetGroups returns groups with workflows
+func (wr *FileWorkflowRepository) GetGroups() []string {
+	files, err := os.ReadDir(wr.dir)
+	if err != nil {
+		return nil
+	}
+	groups := make([]string, 0)
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), workflowExt) {
+			continue
+		}
+		if group, err := url.PathUnescape(strings.TrimSuffix(file.Name(), workflowExt)); err == nil {
+			groups = append(groups, group)
+		}
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// Delete removes workflow of the group
+func (wr *FileWorkflowRepository) Delete(group string) error {
+	if group == "" {
+		return fmt.Errorf("workflow group is not specified")
+	}
+	return os.Remove(wr.buildName(group))
+}
+
+// buildName escapes group so that groups with slashes map to a single file
+func (wr *FileWorkflowRepository) buildName(group string) string {
+	return filepath.Join(wr.dir, url.PathEscape(group)+workflowExt)
+}
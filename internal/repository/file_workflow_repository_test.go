@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldSaveLoadAndDeleteWorkflows(t *testing.T) {
+	// GIVEN a workflow repository
+	workflowRepository, err := NewFileWorkflowRepository(&types.Configuration{DataDir: t.TempDir()})
+	require.NoError(t, err)
+
+	// WHEN saving workflow of a group with slashes
+	err = workflowRepository.Save("/orders/v1", &types.Workflow{
+		Name: "orders",
+		Steps: []*types.WorkflowStepDefinition{
+			{Name: "create", Scenario: "create_order", Extract: map[string]string{"order_id": "$.id"}},
+			{Name: "get", Scenario: "get_order", DependsOn: []string{"create"}},
+		},
+	})
+	// THEN it should succeed
+	require.NoError(t, err)
+	require.Equal(t, []string{"/orders/v1"}, workflowRepository.GetGroups())
+
+	// AND should load saved workflow
+	workflow, err := workflowRepository.Load("/orders/v1")
+	require.NoError(t, err)
+	require.Equal(t, "/orders/v1", workflow.Group)
+	require.Equal(t, "$.id", workflow.Steps[0].Extract["order_id"])
+	require.Equal(t, []string{"create"}, workflow.Steps[1].DependsOn)
+
+	// AND should not save invalid workflow
+	workflow.Steps[0].DependsOn = []string{"get"}
+	require.Error(t, workflowRepository.Save("/orders/v1", workflow))
+
+	// AND should delete workflow
+	require.NoError(t, workflowRepository.Delete("/orders/v1"))
+	_, err = workflowRepository.Load("/orders/v1")
+	require.Error(t, err)
+	require.Empty(t, workflowRepository.GetGroups())
+}
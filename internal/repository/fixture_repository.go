@@ -1,6 +1,10 @@
 package repository
 
-import "github.com/bhatti/api-mock-service/internal/types"
+import (
+	"os"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+)
 
 // APIFixtureRepository defines data store for content for mocking purpose
 type APIFixtureRepository interface {
@@ -11,6 +15,13 @@ type APIFixtureRepository interface {
 		path string,
 	) ([]byte, error)
 
+	// Open Content data by id for streaming
+	Open(
+		method types.MethodType,
+		name string,
+		path string,
+	) (*os.File, error)
+
 	// GetFixtureNames returns list of fixture names for given Method and Path
 	GetFixtureNames(
 		method types.MethodType,
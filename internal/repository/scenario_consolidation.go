@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// ConsolidateScenarios collapses scenarios of the group (or all groups if empty) into parameterized templates.
+// The templates are saved and source scenarios are removed only when apply is true.
+func ConsolidateScenarios(
+	scenarioRepository APIScenarioRepository,
+	group string,
+	minSize int,
+	apply bool,
+) (*types.ConsolidationReport, error) {
+	scenarios := make([]*types.APIScenario, 0)
+	for _, keyData := range scenarioRepository.ListScenarioKeyData(group) {
+		b, err := scenarioRepository.LoadRaw(keyData.Method, keyData.Name, keyData.Path)
+		if err != nil {
+			continue // stale key
+		}
+		scenario := &types.APIScenario{}
+		if err = yaml.Unmarshal(b, scenario); err != nil {
+			return nil, fmt.Errorf("failed to parse scenario %s due to %w", keyData.Name, err)
+		}
+		scenarios = append(scenarios, scenario)
+	}
+	report := &types.ConsolidationReport{
+		Group:    group,
+		Scanned:  len(scenarios),
+		Applied:  apply,
+		Clusters: types.ConsolidateScenarios(scenarios, minSize),
+	}
+	if !apply {
+		return report, nil
+	}
+	for _, cluster := range report.Clusters {
+		if err := scenarioRepository.Save(cluster.Scenario); err != nil {
+			return nil, fmt.Errorf("failed to save consolidated scenario %s due to %w", cluster.Path, err)
+		}
+		for _, source := range cluster.SourceScenarios() {
+			if err := scenarioRepository.Delete(source.Method, source.Name, source.Path); err != nil {
+				log.WithFields(log.Fields{
+					"Component": "ScenarioConsolidation",
+					"Scenario":  source.Name,
+					"Path":      source.Path,
+					"Error":     err,
+				}).Warnf("failed to remove consolidated scenario")
+			}
+		}
+		log.WithFields(log.Fields{
+			"Component": "ScenarioConsolidation",
+			"Path":      cluster.Path,
+			"Method":    cluster.Method,
+			"Sources":   len(cluster.Sources),
+		}).Infof("consolidated scenarios")
+	}
+	return report, nil
+}
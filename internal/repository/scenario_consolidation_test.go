@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldConsolidateAndReplaceRecordedScenarios(t *testing.T) {
+	// GIVEN a mock scenario repository with recorded scenarios
+	repo, err := NewFileAPIScenarioRepository(types.BuildTestConfig())
+	require.NoError(t, err)
+	for i := 1; i <= 3; i++ {
+		scenario := &types.APIScenario{
+			Method: types.Get,
+			Name:   fmt.Sprintf("recorded-consolidate-%d", i),
+			Path:   fmt.Sprintf("/api/consolidate/items/%d", i),
+			Group:  "consolidate-items",
+			Response: types.APIResponse{
+				StatusCode: 200,
+				Contents:   fmt.Sprintf(`{"id": %d, "name": "item%d", "kind": "book"}`, i, i),
+			},
+		}
+		require.NoError(t, repo.Save(scenario))
+	}
+
+	// WHEN consolidating without applying
+	report, err := ConsolidateScenarios(repo, "consolidate-items", 2, false)
+
+	// THEN it should report template without changing scenarios
+	require.NoError(t, err)
+	require.Len(t, report.Clusters, 1)
+	require.Equal(t, "/api/consolidate/items/:id", report.Clusters[0].Path)
+	require.Len(t, repo.LookupAllByPath("/api/consolidate/items/1"), 1)
+
+	// WHEN consolidating with apply
+	report, err = ConsolidateScenarios(repo, "consolidate-items", 2, true)
+
+	// THEN it should replace recorded scenarios with template
+	require.NoError(t, err)
+	require.True(t, report.Applied)
+	require.Len(t, repo.LookupAllByPath("/api/consolidate/items/1"), 0)
+	require.Len(t, repo.LookupAllByPath("/api/consolidate/items/:id"), 1)
+
+	// AND template should render response for a new id
+	scenario, err := repo.Lookup(&types.APIKeyData{
+		Method: types.Get,
+		Path:   "/api/consolidate/items/7",
+	}, map[string]any{"id": "7"})
+	require.NoError(t, err)
+	res := make(map[string]any)
+	require.NoError(t, json.Unmarshal([]byte(scenario.Response.Contents), &res))
+	require.Equal(t, float64(7), res["id"])
+	require.Equal(t, "book", res["kind"])
+	require.NotEmpty(t, res["name"])
+}
@@ -62,6 +62,9 @@ type APIScenarioRepository interface {
 	// LookupAllByPath finds matching scenarios by path
 	LookupAllByPath(path string) []*types.APIKeyData
 
+	// LatencyPercentiles returns p50 and p95 of recorded latencies of scenarios with same method, group and path
+	LatencyPercentiles(keyData *types.APIKeyData) (p50 int64, p95 int64)
+
 	// Lookup finds top matching scenario that hasn't been used recently
 	Lookup(target *types.APIKeyData, data map[string]any) (*types.APIScenario, error)
 
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"github.com/bhatti/api-mock-service/internal/types"
+)
+
+// WorkflowRepository defines data store for workflows of groups
+type WorkflowRepository interface {
+	// Save saves workflow of the group
+	Save(group string, workflow *types.Workflow) error
+
+	// Load loads workflow of the group
+	Load(group string) (*types.Workflow, error)
+
+	// GetGroups returns groups with workflows
+	GetGroups() []string
+
+	// Delete removes workflow of the group
+	Delete(group string) error
+}
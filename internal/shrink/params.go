@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: MIT
+
+package shrink
+
+import (
+	"context"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// paramsOf selects one of the parameter maps of a request, e.g. query params or headers.
+type paramsOf func(req *types.APIRequest) map[string]string
+
+// shrinkQueryParams removes query params that are not needed for the failure and shortens the rest.
+func shrinkQueryParams(ctx context.Context, scenario *types.APIScenario, det FailureDetector,
+	attempts *int, maxAttempts int, deadline time.Time) (*types.APIScenario, bool) {
+	return shrinkParams(ctx, scenario, det, attempts, maxAttempts, deadline, "QueryParam", true,
+		func(req *types.APIRequest) map[string]string { return req.QueryParams })
+}
+
+// shrinkHeaders removes headers that are not needed for the failure and shortens the rest.
+func shrinkHeaders(ctx context.Context, scenario *types.APIScenario, det FailureDetector,
+	attempts *int, maxAttempts int, deadline time.Time) (*types.APIScenario, bool) {
+	return shrinkParams(ctx, scenario, det, attempts, maxAttempts, deadline, "Header", true,
+		func(req *types.APIRequest) map[string]string { return req.Headers })
+}
+
+// shrinkPathParams shortens path params; they are never removed because the path needs them.
+func shrinkPathParams(ctx context.Context, scenario *types.APIScenario, det FailureDetector,
+	attempts *int, maxAttempts int, deadline time.Time) (*types.APIScenario, bool) {
+	return shrinkParams(ctx, scenario, det, attempts, maxAttempts, deadline, "PathParam", false,
+		func(req *types.APIRequest) map[string]string { return req.PathParams })
+}
+
+func shrinkParams(ctx context.Context, scenario *types.APIScenario, det FailureDetector,
+	attempts *int, maxAttempts int, deadline time.Time, kind string, removable bool,
+	params paramsOf) (*types.APIScenario, bool) {
+
+	if len(params(&scenario.Request)) == 0 {
+		return scenario, false
+	}
+
+	current := cloneScenario(scenario)
+	reduced := false
+
+	for _, name := range sortedKeys(params(&scenario.Request)) {
+		if time.Now().After(deadline) || *attempts >= maxAttempts {
+			break
+		}
+		if removable {
+			candidate := cloneScenario(current)
+			delete(params(&candidate.Request), name)
+
+			*attempts++
+			if det.TestScenario(ctx, candidate) != nil {
+				current = candidate
+				reduced = true
+				log.WithFields(log.Fields{"Component": "Shrink", kind: name}).
+					Debug("param removal kept")
+				continue
+			}
+		}
+
+		// param is needed for the failure, binary-search its minimal triggering length
+		value := params(&current.Request)[name]
+		lo, hi := 0, len(value)
+		if !removable {
+			lo = 1 // an empty path param would change the path
+		}
+		for lo < hi && *attempts < maxAttempts && !time.Now().After(deadline) {
+			mid := (lo + hi) / 2
+			candidate := cloneScenario(current)
+			params(&candidate.Request)[name] = value[:mid]
+
+			*attempts++
+			if det.TestScenario(ctx, candidate) != nil {
+				hi = mid
+				current = candidate
+				value = value[:mid]
+				reduced = true
+			} else {
+				lo = mid + 1
+			}
+		}
+	}
+	return current, reduced
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: MIT
+
+package shrink
+
+import (
+	"sort"
+
+	"github.com/bhatti/api-mock-service/internal/jsonpath"
+)
+
+// Paths of JSON bodies are addressed with keys and indexes as in jsonpath.ValueAt.
+
+// leafPaths returns paths of all scalar values in the body.
+func leafPaths(root any) (paths [][]any) {
+	walkPaths(root, nil, func(path []any, value any) {
+		switch value.(type) {
+		case map[string]any, []any:
+		default:
+			paths = append(paths, path)
+		}
+	})
+	return
+}
+
+// arrayPaths returns paths of all arrays in the body.
+func arrayPaths(root any) (paths [][]any) {
+	walkPaths(root, nil, func(path []any, value any) {
+		if _, ok := value.([]any); ok {
+			paths = append(paths, path)
+		}
+	})
+	return
+}
+
+func walkPaths(value any, path []any, visit func([]any, any)) {
+	if len(path) > 0 {
+		visit(path, value)
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		for _, k := range sortedKeys(v) {
+			walkPaths(v[k], jsonpath.Append(path, k), visit)
+		}
+	case []any:
+		for i, item := range v {
+			walkPaths(item, jsonpath.Append(path, i), visit)
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
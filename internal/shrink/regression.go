@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: MIT
+
+package shrink
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+)
+
+// RegressionTag tags scenarios that were captured from minimal failing inputs.
+const RegressionTag = "regression"
+
+// RegressionScenario converts a minimal failing scenario into a permanent regression scenario
+// of the same group, so that the failure is re-tested by every later contract run.
+func RegressionScenario(minimal *types.APIScenario, failure string) *types.APIScenario {
+	regression := cloneScenario(minimal)
+	if !strings.HasSuffix(regression.Name, "-"+RegressionTag) {
+		regression.Name = regression.Name + "-" + RegressionTag
+	}
+	regression.Tags = make([]string, 0, len(minimal.Tags)+1)
+	for _, tag := range minimal.Tags {
+		if tag != RegressionTag {
+			regression.Tags = append(regression.Tags, tag)
+		}
+	}
+	regression.Tags = append(regression.Tags, RegressionTag)
+	regression.Description = fmt.Sprintf("regression of %s: %s", minimal.Name, failure)
+	return regression
+}
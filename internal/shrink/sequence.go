@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: MIT
+
+package shrink
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// SequenceFailureDetector can execute a sequence of API calls and report whether it fails.
+// Return nil for sequences that pass or are not valid, non-nil for sequences that fail.
+type SequenceFailureDetector interface {
+	TestSequence(ctx context.Context, sequence []*types.APIScenario) error
+}
+
+// StepFailure is returned by a SequenceFailureDetector when a call of the sequence fails so that shrinking only
+// keeps candidates that fail at the same call as the original sequence rather than any failure
+type StepFailure struct {
+	// Step is index of the failing call in the tested sequence
+	Step int
+	// Scenario name of the failing call
+	Scenario string
+	// Err is the failure of the call
+	Err error
+}
+
+func (f *StepFailure) Error() string {
+	return f.Err.Error()
+}
+
+// Unwrap returns failure of the call
+func (f *StepFailure) Unwrap() error {
+	return f.Err
+}
+
+// SequenceResult holds the minimal sequence found by ShrinkSequence.
+type SequenceResult struct {
+	// Minimal is the shortest sequence that still triggers the failure.
+	Minimal []*types.APIScenario
+	// Err is the failure of the minimal sequence.
+	Err error
+	// Attempts is the number of candidate sequences evaluated.
+	Attempts int
+	// Reduced reports whether any reduction was achieved.
+	Reduced bool
+}
+
+// ShrinkSequence takes a known-failing sequence of API calls and returns the minimal sequence that
+// still fails according to detector. It tries two reduction strategies in order:
+//  1. Shortest failing prefix (calls after the failure are dropped)
+//  2. Removal of chunks of calls from half of the sequence down to single calls (delta debugging)
+//
+// When the original failure is a StepFailure, a candidate reproduces it only if it fails at the same call of
+// the original sequence with the same scenario.
+func ShrinkSequence(
+	ctx context.Context,
+	detector SequenceFailureDetector,
+	failing []*types.APIScenario,
+	opts ShrinkOptions,
+) (*SequenceResult, error) {
+	opts.defaults()
+
+	deadline := time.Now().Add(opts.Timeout)
+	current := append([]*types.APIScenario{}, failing...)
+	res := &SequenceResult{Minimal: current}
+
+	// Confirm the original actually fails
+	if res.Err = detector.TestSequence(ctx, current); res.Err == nil {
+		log.WithFields(log.Fields{"Component": "Shrink"}).
+			Warn("original sequence does not fail — nothing to shrink")
+		return res, nil
+	}
+	var target *StepFailure
+	_ = errors.As(res.Err, &target)
+	// origins are indexes of calls of the minimal sequence in the original sequence
+	origins := make([]int, len(current))
+	for i := range origins {
+		origins[i] = i
+	}
+	reproduces := func(candidateOrigins []int, err error) bool {
+		if err == nil {
+			return false
+		}
+		if target == nil {
+			return true
+		}
+		var failure *StepFailure
+		return errors.As(err, &failure) && failure.Scenario == target.Scenario &&
+			failure.Step < len(candidateOrigins) && candidateOrigins[failure.Step] == target.Step
+	}
+	exhausted := func() bool {
+		return time.Now().After(deadline) || res.Attempts >= opts.MaxAttempts || ctx.Err() != nil
+	}
+	try := func(candidate []*types.APIScenario, candidateOrigins []int) bool {
+		res.Attempts++
+		if err := detector.TestSequence(ctx, candidate); reproduces(candidateOrigins, err) {
+			res.Minimal = candidate
+			origins = candidateOrigins
+			res.Err = err
+			res.Reduced = true
+			return true
+		}
+		return false
+	}
+
+	for n := 1; n < len(res.Minimal) && !exhausted(); n++ {
+		if try(res.Minimal[:n:n], origins[:n:n]) {
+			break
+		}
+	}
+
+	for size := len(res.Minimal) / 2; size >= 1; size /= 2 {
+		for start := 0; start+size <= len(res.Minimal) && len(res.Minimal) > 1 && !exhausted(); {
+			candidate := make([]*types.APIScenario, 0, len(res.Minimal)-size)
+			candidate = append(candidate, res.Minimal[:start]...)
+			candidate = append(candidate, res.Minimal[start+size:]...)
+			candidateOrigins := make([]int, 0, len(origins)-size)
+			candidateOrigins = append(candidateOrigins, origins[:start]...)
+			candidateOrigins = append(candidateOrigins, origins[start+size:]...)
+			if try(candidate, candidateOrigins) {
+				log.WithFields(log.Fields{"Component": "Shrink", "Removed": size, "Remaining": len(candidate)}).
+					Debug("call removal kept")
+				continue
+			}
+			start += size
+		}
+	}
+	return res, nil
+}
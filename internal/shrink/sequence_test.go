@@ -0,0 +1,137 @@
+package shrink
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+// getAfterDeleteDetector fails when a get follows a delete that follows a create,
+// simulating a producer that still returns deleted resources. Sequences that get or
+// delete before create are not valid and pass.
+type getAfterDeleteDetector struct {
+	calls int
+}
+
+func (d *getAfterDeleteDetector) TestSequence(_ context.Context, sequence []*types.APIScenario) error {
+	d.calls++
+	created, deleted := false, false
+	for _, s := range sequence {
+		switch s.Name {
+		case "create":
+			created, deleted = true, false
+		case "delete":
+			if !created {
+				return nil
+			}
+			deleted = true
+		case "get":
+			if !created {
+				return nil
+			}
+			if deleted {
+				return fmt.Errorf("deleted resource returned")
+			}
+		}
+	}
+	return nil
+}
+
+// firstCallDetector reports a step failure when flaky is the first call of a sequence and when a get follows
+// a delete that follows a create, simulating a sequence with two unrelated failures.
+type firstCallDetector struct{}
+
+func (d *firstCallDetector) TestSequence(_ context.Context, sequence []*types.APIScenario) error {
+	created, deleted := false, false
+	for i, s := range sequence {
+		switch s.Name {
+		case "flaky":
+			if i == 0 {
+				return &StepFailure{Step: i, Scenario: s.Name, Err: fmt.Errorf("flaky failed")}
+			}
+		case "create":
+			created, deleted = true, false
+		case "delete":
+			if !created {
+				return nil
+			}
+			deleted = true
+		case "get":
+			if !created {
+				return nil
+			}
+			if deleted {
+				return &StepFailure{Step: i, Scenario: s.Name, Err: fmt.Errorf("deleted resource returned")}
+			}
+		}
+	}
+	return nil
+}
+
+func sequenceOf(names ...string) []*types.APIScenario {
+	sequence := make([]*types.APIScenario, len(names))
+	for i, name := range names {
+		sequence[i] = &types.APIScenario{Name: name}
+	}
+	return sequence
+}
+
+func sequenceNames(sequence []*types.APIScenario) []string {
+	names := make([]string, len(sequence))
+	for i, s := range sequence {
+		names[i] = s.Name
+	}
+	return names
+}
+
+func TestShrinkSequence_OriginalDoesNotFail_ReturnsAsIs(t *testing.T) {
+	result, err := ShrinkSequence(context.Background(), &getAfterDeleteDetector{},
+		sequenceOf("create", "get", "delete"), ShrinkOptions{})
+	require.NoError(t, err)
+	require.False(t, result.Reduced)
+	require.NoError(t, result.Err)
+	require.Equal(t, 0, result.Attempts)
+}
+
+func TestShrinkSequence_RemovesIrrelevantCalls(t *testing.T) {
+	// GIVEN a failing sequence with calls that don't contribute to the failure
+	detector := &getAfterDeleteDetector{}
+	failing := sequenceOf("list", "create", "get", "list", "update", "delete", "list", "get", "update", "list")
+	// WHEN shrinking the sequence
+	result, err := ShrinkSequence(context.Background(), detector, failing, ShrinkOptions{})
+	// THEN minimal sequence should only have calls that trigger the failure
+	require.NoError(t, err)
+	require.True(t, result.Reduced)
+	require.Equal(t, []string{"create", "delete", "get"}, sequenceNames(result.Minimal))
+	require.ErrorContains(t, result.Err, "deleted")
+	require.Equal(t, result.Attempts+1, detector.calls)
+	// AND original sequence should not be modified
+	require.Len(t, failing, 10)
+}
+
+func TestShrinkSequence_StopsAtMaxAttempts(t *testing.T) {
+	detector := &getAfterDeleteDetector{}
+	failing := sequenceOf("list", "create", "get", "list", "update", "delete", "list", "get", "update", "list")
+	result, err := ShrinkSequence(context.Background(), detector, failing, ShrinkOptions{MaxAttempts: 3})
+	require.NoError(t, err)
+	require.Equal(t, 3, result.Attempts)
+	require.Error(t, result.Err)
+}
+
+func TestShrinkSequence_KeepsFailureAtSameStep(t *testing.T) {
+	// GIVEN a sequence failing at get where removing calls can trigger an unrelated failure of flaky
+	failing := sequenceOf("create", "setup", "flaky", "delete", "get")
+	// WHEN shrinking the sequence
+	result, err := ShrinkSequence(context.Background(), &firstCallDetector{}, failing, ShrinkOptions{})
+	// THEN minimal sequence should still fail at get rather than at flaky
+	require.NoError(t, err)
+	require.True(t, result.Reduced)
+	require.Equal(t, []string{"create", "delete", "get"}, sequenceNames(result.Minimal))
+	var failure *StepFailure
+	require.ErrorAs(t, result.Err, &failure)
+	require.Equal(t, "get", failure.Scenario)
+	require.Equal(t, 2, failure.Step)
+}
@@ -11,6 +11,7 @@ import (
 	"math"
 	"time"
 
+	"github.com/bhatti/api-mock-service/internal/jsonpath"
 	"github.com/bhatti/api-mock-service/internal/types"
 	log "github.com/sirupsen/logrus"
 )
@@ -46,14 +47,20 @@ type Result struct {
 	Attempts int
 	// Reduced reports whether any reduction was achieved.
 	Reduced bool
+	// Reproduced reports whether the original scenario failed; otherwise Minimal is the original scenario
+	// and does not trigger the failure.
+	Reproduced bool
 }
 
 // Shrink takes a known-failing scenario and returns the minimal scenario that
-// still fails according to detector. It tries four reduction strategies in order:
-//  1. Field removal (delta debugging)
-//  2. String shortening (binary search on length)
-//  3. Array element removal
-//  4. Numeric reduction (exponential backoff from boundary values)
+// still fails according to detector. It tries these reduction strategies in order:
+//  1. Field removal (recursive delta debugging of nested objects)
+//  2. Query param removal and shortening
+//  3. Header removal and shortening
+//  4. Path param shortening
+//  5. String shortening (binary search on length)
+//  6. Array element removal
+//  7. Numeric reduction (exponential backoff from boundary values)
 func Shrink(
 	ctx context.Context,
 	detector FailureDetector,
@@ -77,13 +84,16 @@ func Shrink(
 
 	strategies := []func(context.Context, *types.APIScenario, FailureDetector, *int, int, time.Time) (*types.APIScenario, bool){
 		shrinkFields,
+		shrinkQueryParams,
+		shrinkHeaders,
+		shrinkPathParams,
 		shrinkStrings,
 		shrinkArrays,
 		shrinkNumerics,
 	}
 
 	for _, strategy := range strategies {
-		if time.Now().After(deadline) || attempts >= opts.MaxAttempts {
+		if time.Now().After(deadline) || attempts >= opts.MaxAttempts || ctx.Err() != nil {
 			break
 		}
 		candidate, didReduce := strategy(ctx, current, detector, &attempts, opts.MaxAttempts, deadline)
@@ -93,10 +103,11 @@ func Shrink(
 		}
 	}
 
-	return &Result{Minimal: current, Attempts: attempts, Reduced: reduced}, nil
+	return &Result{Minimal: current, Attempts: attempts, Reduced: reduced, Reproduced: true}, nil
 }
 
-// shrinkFields removes request body fields one at a time (delta debugging).
+// shrinkFields removes request body fields one at a time (delta debugging), starting with top-level fields
+// and descending into nested objects and objects of arrays that are still needed for the failure.
 func shrinkFields(ctx context.Context, scenario *types.APIScenario, det FailureDetector,
 	attempts *int, maxAttempts int, deadline time.Time) (*types.APIScenario, bool) {
 
@@ -108,29 +119,51 @@ func shrinkFields(ctx context.Context, scenario *types.APIScenario, det FailureD
 	current := cloneScenario(scenario)
 	reduced := false
 
-	for field := range body {
-		if time.Now().After(deadline) || *attempts >= maxAttempts {
-			break
+	// breadth-first queue of paths to objects whose fields are removed
+	queue := [][]any{nil}
+	for len(queue) > 0 {
+		objPath := queue[0]
+		queue = queue[1:]
+		obj, ok := jsonpath.ValueAt(parseBodyMap(current.Request.Contents), objPath).(map[string]any)
+		if !ok {
+			continue
 		}
-		candidate := cloneScenario(current)
-		candidateBody := parseBodyMap(candidate.Request.Contents)
-		delete(candidateBody, field)
-		candidate.Request.Contents = marshalBody(candidateBody)
-
-		*attempts++
-		if det.TestScenario(ctx, candidate) != nil {
-			// Still fails without this field — keep the removal
-			current = candidate
-			delete(body, field)
-			reduced = true
-			log.WithFields(log.Fields{"Component": "Shrink", "RemovedField": field}).
-				Debug("field removal kept")
+		for _, field := range sortedKeys(obj) {
+			if time.Now().After(deadline) || *attempts >= maxAttempts {
+				return current, reduced
+			}
+			path := jsonpath.Append(objPath, field)
+			candidate := cloneScenario(current)
+			candidateBody := parseBodyMap(candidate.Request.Contents)
+			jsonpath.RemoveValueAt(candidateBody, path)
+			candidate.Request.Contents = marshalBody(candidateBody)
+
+			*attempts++
+			if det.TestScenario(ctx, candidate) != nil {
+				// Still fails without this field — keep the removal
+				current = candidate
+				reduced = true
+				log.WithFields(log.Fields{"Component": "Shrink", "RemovedField": jsonpath.Format(path)}).
+					Debug("field removal kept")
+				continue
+			}
+			// field is needed for the failure, try removing its nested fields
+			switch child := obj[field].(type) {
+			case map[string]any:
+				queue = append(queue, path)
+			case []any:
+				for i, item := range child {
+					if _, isObj := item.(map[string]any); isObj {
+						queue = append(queue, jsonpath.Append(path, i))
+					}
+				}
+			}
 		}
 	}
 	return current, reduced
 }
 
-// shrinkStrings binary-searches string fields for the minimal triggering length.
+// shrinkStrings binary-searches string fields at any depth for the minimal triggering length.
 func shrinkStrings(ctx context.Context, scenario *types.APIScenario, det FailureDetector,
 	attempts *int, maxAttempts int, deadline time.Time) (*types.APIScenario, bool) {
 
@@ -142,8 +175,8 @@ func shrinkStrings(ctx context.Context, scenario *types.APIScenario, det Failure
 	current := cloneScenario(scenario)
 	reduced := false
 
-	for field, val := range body {
-		str, ok := val.(string)
+	for _, path := range leafPaths(body) {
+		str, ok := jsonpath.ValueAt(body, path).(string)
 		if !ok || len(str) <= 1 {
 			continue
 		}
@@ -153,7 +186,7 @@ func shrinkStrings(ctx context.Context, scenario *types.APIScenario, det Failure
 			mid := (lo + hi) / 2
 			candidate := cloneScenario(current)
 			candidateBody := parseBodyMap(candidate.Request.Contents)
-			candidateBody[field] = str[:mid]
+			jsonpath.SetValueAt(candidateBody, path, str[:mid])
 			candidate.Request.Contents = marshalBody(candidateBody)
 
 			*attempts++
@@ -161,7 +194,6 @@ func shrinkStrings(ctx context.Context, scenario *types.APIScenario, det Failure
 				// Shorter string still fails
 				hi = mid
 				current = candidate
-				body[field] = str[:mid]
 				str = str[:mid]
 				reduced = true
 			} else {
@@ -172,7 +204,7 @@ func shrinkStrings(ctx context.Context, scenario *types.APIScenario, det Failure
 	return current, reduced
 }
 
-// shrinkArrays removes array elements one at a time.
+// shrinkArrays removes array elements at any depth one at a time.
 func shrinkArrays(ctx context.Context, scenario *types.APIScenario, det FailureDetector,
 	attempts *int, maxAttempts int, deadline time.Time) (*types.APIScenario, bool) {
 
@@ -184,8 +216,8 @@ func shrinkArrays(ctx context.Context, scenario *types.APIScenario, det FailureD
 	current := cloneScenario(scenario)
 	reduced := false
 
-	for field, val := range body {
-		arr, ok := val.([]any)
+	for _, path := range arrayPaths(body) {
+		arr, ok := jsonpath.ValueAt(parseBodyMap(current.Request.Contents), path).([]any)
 		if !ok || len(arr) <= 1 {
 			continue
 		}
@@ -199,14 +231,13 @@ func shrinkArrays(ctx context.Context, scenario *types.APIScenario, det FailureD
 			shortened := make([]any, 0, len(arr)-1)
 			shortened = append(shortened, arr[:i]...)
 			shortened = append(shortened, arr[i+1:]...)
-			candidateBody[field] = shortened
+			jsonpath.SetValueAt(candidateBody, path, shortened)
 			candidate.Request.Contents = marshalBody(candidateBody)
 
 			*attempts++
 			if det.TestScenario(ctx, candidate) != nil {
 				current = candidate
 				arr = shortened
-				body[field] = shortened
 				reduced = true
 			}
 		}
@@ -214,7 +245,7 @@ func shrinkArrays(ctx context.Context, scenario *types.APIScenario, det FailureD
 	return current, reduced
 }
 
-// shrinkNumerics exponentially reduces large boundary values toward zero.
+// shrinkNumerics exponentially reduces large boundary values at any depth toward zero.
 func shrinkNumerics(ctx context.Context, scenario *types.APIScenario, det FailureDetector,
 	attempts *int, maxAttempts int, deadline time.Time) (*types.APIScenario, bool) {
 
@@ -226,14 +257,9 @@ func shrinkNumerics(ctx context.Context, scenario *types.APIScenario, det Failur
 	current := cloneScenario(scenario)
 	reduced := false
 
-	for field, val := range body {
-		var num float64
-		switch v := val.(type) {
-		case float64:
-			num = v
-		case int:
-			num = float64(v)
-		default:
+	for _, path := range leafPaths(body) {
+		num, ok := jsonpath.ValueAt(parseBodyMap(current.Request.Contents), path).(float64)
+		if !ok {
 			continue
 		}
 
@@ -246,13 +272,12 @@ func shrinkNumerics(ctx context.Context, scenario *types.APIScenario, det Failur
 			num /= 2
 			candidate := cloneScenario(current)
 			candidateBody := parseBodyMap(candidate.Request.Contents)
-			candidateBody[field] = num
+			jsonpath.SetValueAt(candidateBody, path, num)
 			candidate.Request.Contents = marshalBody(candidateBody)
 
 			*attempts++
 			if det.TestScenario(ctx, candidate) != nil {
 				current = candidate
-				body[field] = num
 				reduced = true
 			} else {
 				break // Can't reduce further — stop
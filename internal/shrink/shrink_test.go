@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/bhatti/api-mock-service/internal/jsonpath"
 	"github.com/bhatti/api-mock-service/internal/types"
 	"github.com/stretchr/testify/require"
 )
@@ -92,6 +94,7 @@ func TestShrink_OriginalDoesNotFail_ReturnsAsIs(t *testing.T) {
 	result, err := Shrink(context.Background(), &neverFailDetector{}, s, ShrinkOptions{})
 	require.NoError(t, err)
 	require.False(t, result.Reduced)
+	require.False(t, result.Reproduced)
 	require.Equal(t, 0, result.Attempts)
 }
 
@@ -170,3 +173,136 @@ func TestShrinkOptions_Defaults(t *testing.T) {
 	require.Equal(t, 100, opts.MaxAttempts)
 	require.Equal(t, int64(30), int64(opts.Timeout.Seconds()))
 }
+
+// detectorFunc adapts a function to FailureDetector.
+type detectorFunc func(s *types.APIScenario) error
+
+func (f detectorFunc) TestScenario(_ context.Context, s *types.APIScenario) error {
+	return f(s)
+}
+
+func TestShrink_NestedFields_RemovesAndShortensDeepFields(t *testing.T) {
+	// GIVEN a failure triggered by a deeply nested zip code longer than 3 chars
+	s := scenarioWithBody(`{"meta": "m", "order": {"id": 1, "items": [{"sku": "A"}, {"sku": "B"}],
+"shipping": {"note": "leave at door", "zip": "941051234"}}}`)
+	det := detectorFunc(func(s *types.APIScenario) error {
+		body := parseBodyMap(s.Request.Contents)
+		if zip, ok := jsonpath.ValueAt(body, []any{"order", "shipping", "zip"}).(string); ok && len(zip) > 3 {
+			return fmt.Errorf("zip triggers failure")
+		}
+		return nil
+	})
+
+	// WHEN shrinking
+	result, err := Shrink(context.Background(), det, s, ShrinkOptions{})
+
+	// THEN only the nested zip should remain with minimal length
+	require.NoError(t, err)
+	require.True(t, result.Reduced)
+	require.JSONEq(t, `{"order": {"shipping": {"zip": "9410"}}}`, result.Minimal.Request.Contents)
+}
+
+func TestShrink_NestedArrays_RemovesElementsAndObjectFields(t *testing.T) {
+	// GIVEN a failure triggered by any nested item with a negative price
+	s := scenarioWithBody(`{"order": {"items": [{"price": 1, "sku": "A"}, {"price": -5, "sku": "B"}, {"price": 2}]}}`)
+	det := detectorFunc(func(s *types.APIScenario) error {
+		items, _ := jsonpath.ValueAt(parseBodyMap(s.Request.Contents), []any{"order", "items"}).([]any)
+		for _, item := range items {
+			if obj, ok := item.(map[string]any); ok && obj["price"] != nil && obj["price"].(float64) < 0 {
+				return fmt.Errorf("negative price triggers failure")
+			}
+		}
+		return nil
+	})
+
+	// WHEN shrinking
+	result, err := Shrink(context.Background(), det, s, ShrinkOptions{})
+
+	// THEN only the item with negative price should remain
+	require.NoError(t, err)
+	require.JSONEq(t, `{"order": {"items": [{"price": -5}]}}`, result.Minimal.Request.Contents)
+}
+
+func TestShrink_Headers_RemovesAndShortensHeaders(t *testing.T) {
+	// GIVEN a failure triggered by a trace header longer than 2 chars
+	s := scenarioWithBody("")
+	s.Request.Headers = map[string]string{"Content-Type": "application/json", "X-Debug": "true", "X-Trace": "abcdefgh"}
+	det := detectorFunc(func(s *types.APIScenario) error {
+		if len(s.Request.Headers["X-Trace"]) > 2 {
+			return fmt.Errorf("trace header triggers failure")
+		}
+		return nil
+	})
+
+	// WHEN shrinking
+	result, err := Shrink(context.Background(), det, s, ShrinkOptions{})
+
+	// THEN only the shortened trace header should remain and the original should not change
+	require.NoError(t, err)
+	require.True(t, result.Reduced)
+	require.Equal(t, map[string]string{"X-Trace": "abc"}, result.Minimal.Request.Headers)
+	require.Len(t, s.Request.Headers, 3)
+}
+
+func TestShrink_QueryParams_RemovesAndShortensParams(t *testing.T) {
+	// GIVEN a failure triggered by a sort query param with a semicolon
+	s := scenarioWithBody(`{"name": "x"}`)
+	s.Request.QueryParams = map[string]string{"limit": "10", "sort": "name;drop table", "page": "2"}
+	det := detectorFunc(func(s *types.APIScenario) error {
+		if strings.Contains(s.Request.QueryParams["sort"], ";") {
+			return fmt.Errorf("sort triggers failure")
+		}
+		return nil
+	})
+
+	// WHEN shrinking
+	result, err := Shrink(context.Background(), det, s, ShrinkOptions{})
+
+	// THEN only the minimal sort param should remain along with an empty body
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"sort": "name;"}, result.Minimal.Request.QueryParams)
+	require.JSONEq(t, `{}`, result.Minimal.Request.Contents)
+}
+
+func TestShrink_PathParams_ShortensWithoutRemoving(t *testing.T) {
+	// GIVEN a failure triggered by an id path param longer than 2 chars
+	s := scenarioWithBody("")
+	s.Path = "/orders/:id"
+	s.Request.PathParams = map[string]string{"id": "123456789"}
+	det := detectorFunc(func(s *types.APIScenario) error {
+		if len(s.Request.PathParams["id"]) > 2 {
+			return fmt.Errorf("long id triggers failure")
+		}
+		return nil
+	})
+
+	// WHEN shrinking
+	result, err := Shrink(context.Background(), det, s, ShrinkOptions{})
+
+	// THEN id should be shortened but kept
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"id": "123"}, result.Minimal.Request.PathParams)
+}
+
+func TestRegressionScenario_TagsAndRenamesMinimalScenario(t *testing.T) {
+	// GIVEN a minimal failing scenario
+	minimal := scenarioWithBody(`{"zip": "9410"}`)
+	minimal.Group = "orders"
+	minimal.Tags = []string{"smoke"}
+
+	// WHEN converting it into a regression scenario
+	regression := RegressionScenario(minimal, "expected status 400 but was 500")
+
+	// THEN it should be renamed, tagged and described without changing the original
+	require.Equal(t, "test-scenario-regression", regression.Name)
+	require.Equal(t, "orders", regression.Group)
+	require.Equal(t, []string{"smoke", RegressionTag}, regression.Tags)
+	require.Contains(t, regression.Description, "expected status 400 but was 500")
+	require.Equal(t, minimal.Request.Contents, regression.Request.Contents)
+	require.Equal(t, []string{"smoke"}, minimal.Tags)
+
+	// AND converting a regression scenario again should not duplicate name or tag
+	again := RegressionScenario(regression, "still failing")
+	require.Equal(t, "test-scenario-regression", again.Name)
+	require.Equal(t, []string{"smoke", RegressionTag}, again.Tags)
+}
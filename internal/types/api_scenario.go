@@ -321,6 +321,17 @@ func (r APIRequest) Assert(
 	return nil
 }
 
+// templateVariables returns names of variables referenced by templates of path, params, headers and contents
+func (r APIRequest) templateVariables(path string) []string {
+	texts := []string{path, r.Contents}
+	for _, params := range []map[string]string{r.PathParams, r.QueryParams, r.PostParams, r.Headers} {
+		for _, v := range params {
+			texts = append(texts, v)
+		}
+	}
+	return templateVariableNames(texts...)
+}
+
 // AssertContentsPatternOrContent helper method
 func (r APIRequest) AssertContentsPatternOrContent() string {
 	if r.ExampleContents != "" {
@@ -340,6 +351,10 @@ type APIResponse struct {
 	Contents string `yaml:"contents" json:"contents"`
 	// ContentsFile for request
 	ContentsFile string `yaml:"contents_file" json:"contents_file"`
+	// Chunked sends contents file with chunked transfer encoding instead of Content-Length
+	Chunked bool `yaml:"chunked,omitempty" json:"chunked,omitempty"`
+	// Variants are alternative bodies by media type that are selected with Accept header
+	Variants []ResponseVariant `yaml:"variants,omitempty" json:"variants,omitempty"`
 	// Description for response optionally
 	Description string `yaml:"description" json:"description"`
 	// ExampleContents sample for response optionally
@@ -538,6 +553,8 @@ type APIScenario struct {
 	StateMachine *ScenarioStateMachine `yaml:"state_machine,omitempty" json:"state_machine,omitempty"`
 	// WaitMillisBeforeReply for response
 	WaitBeforeReply time.Duration `yaml:"wait_before_reply" json:"wait_before_reply"`
+	// Latency distribution and bandwidth throttle of response, used when WaitBeforeReply is not set
+	Latency *LatencyConfig `yaml:"latency,omitempty" json:"latency,omitempty"`
 	// StartTime of request
 	StartTime time.Time `yaml:"start_time" json:"start_time"`
 	// EndTime of request
@@ -564,6 +581,11 @@ func (api *APIScenario) ToKeyData() *APIKeyData {
 		AssertQueryParamsPattern: api.Request.AssertQueryParamsPattern,
 		AssertContentsPattern:    api.Request.AssertContentsPattern,
 		AssertHeadersPattern:     api.Request.AssertHeadersPattern,
+		LatencyMillis:            api.RecordedLatencyMillis(),
+		NextRequest:              api.NextRequest,
+		SharesVariables:          len(api.Response.AddSharedVariables) > 0 || len(api.Response.DeleteSharedVariables) > 0,
+		SharedVariables:          sharedVariableNames(api.Response.AddSharedVariables, api.Response.DeleteSharedVariables),
+		TemplateVariables:        api.Request.templateVariables(api.Path),
 	}
 }
 
@@ -795,6 +817,14 @@ func (api *APIScenario) GetMillisTime() int64 {
 	return api.GetEndTime().UnixMilli() - api.GetStartTime().UnixMilli()
 }
 
+// RecordedLatencyMillis returns duration between start and end of recording or 0 if not recorded
+func (api *APIScenario) RecordedLatencyMillis() int64 {
+	if api.StartTime.IsZero() || api.EndTime.IsZero() || !api.EndTime.After(api.StartTime) {
+		return 0
+	}
+	return api.GetMillisTime()
+}
+
 // GetEndTime helper method
 func (api *APIScenario) GetEndTime() time.Time {
 	if !api.EndTime.IsZero() {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/bhatti/api-mock-service/internal/fuzz"
@@ -36,6 +37,16 @@ type APIKeyData struct {
 	AssertHeadersPattern map[string]string `yaml:"assert_headers_pattern" json:"assert_headers_pattern"`
 	// AssertContentsPattern for request optionally
 	AssertContentsPattern string `yaml:"assert_contents_pattern" json:"assert_contents_pattern"`
+	// NextRequest name of scenario executed after this one
+	NextRequest string `yaml:"next_request,omitempty" json:"next_request,omitempty"`
+	// SharesVariables is set when scenario adds or deletes shared variables used by later scenarios
+	SharesVariables bool `yaml:"shares_variables,omitempty" json:"shares_variables,omitempty"`
+	// SharedVariables names of shared variables that are added or deleted by the scenario
+	SharedVariables []string `yaml:"shared_variables,omitempty" json:"shared_variables,omitempty"`
+	// TemplateVariables names of variables referenced by templates of the request
+	TemplateVariables []string `yaml:"template_variables,omitempty" json:"template_variables,omitempty"`
+	// LatencyMillis recorded for the API
+	LatencyMillis int64 `yaml:"latency_millis" json:"latency_millis"`
 	// LastUsageTime of key data
 	LastUsageTime int64
 	// RequestCount for the API
@@ -191,6 +202,16 @@ func MatchPathGroups(rawPath string, targetPath string) (res map[string]string)
 	return
 }
 
+// UsesAnyVariable returns true if request templates of the scenario reference any of given variables
+func (kd *APIKeyData) UsesAnyVariable(variables map[string]bool) bool {
+	for _, name := range kd.TemplateVariables {
+		if variables[name] {
+			return true
+		}
+	}
+	return false
+}
+
 // Validate scenario
 func (kd *APIKeyData) Validate() error {
 	if kd.Method == "" {
@@ -309,3 +330,40 @@ func toStringMap(arr []string) (res map[string]bool) {
 	}
 	return
 }
+
+var templateActionRegex = regexp.MustCompile(`{{[^}]*}}`)
+
+var templateFieldRegex = regexp.MustCompile(`\.([A-Za-z_]\w*)`)
+
+// templateVariableNames returns sorted names of fields referenced by template actions such as {{.id}}
+func templateVariableNames(texts ...string) []string {
+	names := make(map[string]bool)
+	for _, text := range texts {
+		for _, action := range templateActionRegex.FindAllString(text, -1) {
+			for _, match := range templateFieldRegex.FindAllStringSubmatch(action, -1) {
+				names[match[1]] = true
+			}
+		}
+	}
+	return sortedNames(names)
+}
+
+// sharedVariableNames returns sorted names of shared variables without the prefix of the response property
+func sharedVariableNames(props ...[]string) []string {
+	names := make(map[string]bool)
+	for _, arr := range props {
+		for _, propName := range arr {
+			n := strings.Index(propName, ".")
+			names[propName[n+1:]] = true
+		}
+	}
+	return sortedNames(names)
+}
+
+func sortedNames(names map[string]bool) (res []string) {
+	for name := range names {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return
+}
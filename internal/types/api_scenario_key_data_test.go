@@ -90,3 +90,37 @@ func Test_ShouldCompareTagsEqualsMockScenarioKeyData(t *testing.T) {
 	keyData2.Tags = []string{"tag1", "tag2", "tag3"}
 	require.Error(t, keyData1.Equals(keyData2))
 }
+
+func Test_ShouldBuildSharedAndTemplateVariablesOfKeyData(t *testing.T) {
+	// GIVEN a scenario that adds shared variables and another that references them in templates
+	producer := &APIScenario{
+		Method: Post,
+		Name:   "create-todo",
+		Path:   "/todos",
+		Response: APIResponse{
+			AddSharedVariables:    []string{"todo.id", "etag"},
+			DeleteSharedVariables: []string{"token"},
+		},
+	}
+	consumer := &APIScenario{
+		Method: Get,
+		Name:   "get-todo",
+		Path:   "/todos/{{.id}}",
+		Request: APIRequest{
+			QueryParams: map[string]string{"v": "{{ .version }}"},
+			Headers:     map[string]string{"If-Match": `{{ Trim .etag }}`},
+			Contents:    `{"owner": "fixed"}`,
+		},
+	}
+
+	// WHEN creating key data
+	producerKey := producer.ToKeyData()
+	consumerKey := consumer.ToKeyData()
+
+	// THEN shared variables and template references should be extracted
+	require.True(t, producerKey.SharesVariables)
+	require.Equal(t, []string{"etag", "id", "token"}, producerKey.SharedVariables)
+	require.Equal(t, []string{"etag", "id", "version"}, consumerKey.TemplateVariables)
+	require.True(t, consumerKey.UsesAnyVariable(map[string]bool{"etag": true}))
+	require.False(t, consumerKey.UsesAnyVariable(map[string]bool{"token": true}))
+}
@@ -0,0 +1,75 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// LastModifiedHeader canonical name
+const LastModifiedHeader = "Last-Modified"
+
+// ConditionalConfig for emulating conditional requests with ETag and Last-Modified validators
+type ConditionalConfig struct {
+	// Enabled adds validators to responses and honors If-None-Match, If-Modified-Since and If-Match
+	Enabled bool `json:"enabled" mapstructure:"enabled"`
+	// ETagTemplate renders ETag from request params such as {{.id}}-{{.version}}, otherwise hash of response body is used
+	ETagTemplate string `json:"etag_template" mapstructure:"etag_template"`
+	// WeakETag generates weak validators prefixed with W/
+	WeakETag bool `json:"weak_etag" mapstructure:"weak_etag"`
+	// LastModified in RFC 1123 format, otherwise end time of the recorded scenario or start time of the server is used
+	LastModified string `json:"last_modified" mapstructure:"last_modified"`
+}
+
+// IsEnabled returns true if conditional requests are emulated
+func (c *ConditionalConfig) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// FormatETag quotes opaque tag and adds weak prefix if configured
+func (c *ConditionalConfig) FormatETag(tag string) string {
+	tag = strings.Trim(strings.TrimSpace(tag), `"`)
+	if c != nil && c.WeakETag {
+		return `W/"` + tag + `"`
+	}
+	return `"` + tag + `"`
+}
+
+// BodyETag generates ETag from hash of the response body
+func (c *ConditionalConfig) BodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return c.FormatETag(hex.EncodeToString(sum[:])[0:16])
+}
+
+// LastModifiedTime returns configured modification time or else the default
+func (c *ConditionalConfig) LastModifiedTime(def time.Time) time.Time {
+	if c != nil && c.LastModified != "" {
+		if t, err := http.ParseTime(c.LastModified); err == nil {
+			return t.UTC()
+		}
+	}
+	return def.UTC().Truncate(time.Second)
+}
+
+// ETagMatches checks comma separated list of entity tags of If-Match or If-None-Match header against etag
+func ETagMatches(header string, etag string, weak bool) bool {
+	if etag == "" {
+		return false
+	}
+	for _, next := range strings.Split(header, ",") {
+		next = strings.TrimSpace(next)
+		if next == "*" {
+			return true
+		}
+		if weak {
+			if strings.TrimPrefix(next, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		} else if next == etag && !strings.HasPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
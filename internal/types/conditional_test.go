@@ -0,0 +1,38 @@
+package types
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldGenerateConditionalValidators(t *testing.T) {
+	var disabled *ConditionalConfig
+	require.False(t, disabled.IsEnabled())
+	conditional := &ConditionalConfig{Enabled: true}
+	require.True(t, conditional.IsEnabled())
+	etag := conditional.BodyETag([]byte("hello"))
+	require.Equal(t, etag, conditional.BodyETag([]byte("hello")))
+	require.NotEqual(t, etag, conditional.BodyETag([]byte("world")))
+	require.Equal(t, `"v1"`, conditional.FormatETag(`"v1"`))
+	conditional.WeakETag = true
+	require.Equal(t, `W/"v1"`, conditional.FormatETag("v1"))
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC)
+	require.Equal(t, now.Truncate(time.Second), conditional.LastModifiedTime(now))
+	conditional.LastModified = "Mon, 01 Jan 2024 00:00:00 GMT"
+	require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), conditional.LastModifiedTime(now))
+	require.Equal(t, "Mon, 01 Jan 2024 00:00:00 GMT", conditional.LastModifiedTime(now).Format(http.TimeFormat))
+}
+
+func Test_ShouldMatchETags(t *testing.T) {
+	require.True(t, ETagMatches(`"a", "b"`, `"b"`, false))
+	require.True(t, ETagMatches(`*`, `"b"`, false))
+	require.False(t, ETagMatches(`*`, ``, false))
+	require.False(t, ETagMatches(`"c"`, `"b"`, true))
+	require.True(t, ETagMatches(`W/"b"`, `"b"`, true))
+	require.False(t, ETagMatches(`W/"b"`, `"b"`, false))
+	require.False(t, ETagMatches(`"b"`, `W/"b"`, false))
+}
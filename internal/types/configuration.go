@@ -53,6 +53,66 @@ type Configuration struct {
 	APIKeyConfig APIKeyConfig     `yaml:"api_key_config" mapstructure:"api_key_config"`
 
 	TestEnvironments []string `yaml:"test_env" mapstructure:"test_env"`
+
+	// Redaction rules for secrets and PII before recorded scenarios and history are persisted
+	Redaction RedactionConfig `yaml:"redaction" mapstructure:"redaction"`
+	// RecordingFilter for including or excluding proxied requests from recording
+	RecordingFilter RecordingFilterConfig `yaml:"recording_filter" mapstructure:"recording_filter"`
+	// Shadow mode for calling both upstream and mock and recording drift between them
+	Shadow ShadowConfig `yaml:"shadow" mapstructure:"shadow"`
+	// ProxyCA for certificate authority generated per installation to sign MITM certificates
+	ProxyCA ProxyCAConfig `yaml:"proxy_ca" mapstructure:"proxy_ca"`
+	// UpstreamProxy for chaining outbound requests through an HTTP or SOCKS5 proxy
+	UpstreamProxy UpstreamProxyConfig `yaml:"upstream_proxy" mapstructure:"upstream_proxy"`
+	// HostOverrides maps hosts to IP, host or host:port that is dialed instead, keeping Host header and SNI
+	HostOverrides map[string]string `yaml:"host_overrides" mapstructure:"host_overrides"`
+	// LatencyReplay reproduces recorded latency on playback unless overridden by group config
+	LatencyReplay LatencyReplayConfig `yaml:"latency_replay" mapstructure:"latency_replay"`
+	// TLS for serving mock port over HTTPS with HTTP/2 and optional client certificates
+	TLS TLSConfig `yaml:"tls" mapstructure:"tls"`
+}
+
+// UpstreamProxyConfig configuration
+type UpstreamProxyConfig struct {
+	// URL of upstream proxy with http, https, socks5 or socks5h scheme, otherwise proxy_url is used
+	URL      string `yaml:"url" mapstructure:"url" env:"UPSTREAM_PROXY_URL"`
+	Username string `yaml:"username" mapstructure:"username" env:"UPSTREAM_PROXY_USERNAME"`
+	Password string `yaml:"password" mapstructure:"password" env:"UPSTREAM_PROXY_PASSWORD"`
+	// Bypass hosts, domains (.example.com or *.example.com), IPs or CIDRs that are called directly
+	Bypass []string `yaml:"bypass" mapstructure:"bypass" env:"UPSTREAM_PROXY_BYPASS"`
+}
+
+// ProxyCAConfig configuration
+type ProxyCAConfig struct {
+	// ValidityDays of generated CA certificate (default 365)
+	ValidityDays int `yaml:"validity_days" mapstructure:"validity_days" env:"PROXY_CA_VALIDITY_DAYS"`
+}
+
+// TLSConfig configuration of mock port
+type TLSConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled" env:"TLS_ENABLED"`
+	// CertFile and KeyFile of server certificate, otherwise certificate is issued by the CA of the installation
+	CertFile string `yaml:"cert_file" mapstructure:"cert_file" env:"TLS_CERT_FILE"`
+	KeyFile  string `yaml:"key_file" mapstructure:"key_file" env:"TLS_KEY_FILE"`
+	// Hosts of issued certificate (default localhost, 127.0.0.1, ::1 and hostname)
+	Hosts []string `yaml:"hosts" mapstructure:"hosts" env:"TLS_HOSTS"`
+	// ClientCAFile enables mTLS by verifying client certificates against CAs in the PEM file
+	ClientCAFile string `yaml:"client_ca_file" mapstructure:"client_ca_file" env:"TLS_CLIENT_CA_FILE"`
+	// ClientAuth is none, request, verify_if_given or require (default require when client_ca_file is set)
+	ClientAuth string `yaml:"client_auth" mapstructure:"client_auth" env:"TLS_CLIENT_AUTH"`
+	// H2C serves HTTP/2 without TLS (prior knowledge or upgrade) when TLS is disabled
+	H2C bool `yaml:"h2c" mapstructure:"h2c" env:"TLS_H2C"`
+}
+
+// ShadowConfig configuration
+type ShadowConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled" env:"SHADOW_ENABLED"`
+	// UpstreamURL base url of live service, otherwise X-Mock-Url header or base url of scenario is used
+	UpstreamURL string `yaml:"upstream_url" mapstructure:"upstream_url" env:"SHADOW_UPSTREAM_URL"`
+	// ServeMock returns mock response to the client instead of upstream response
+	ServeMock bool `yaml:"serve_mock" mapstructure:"serve_mock" env:"SHADOW_SERVE_MOCK"`
+	// IgnoreHeaders are not compared between upstream and mock responses
+	IgnoreHeaders []string `yaml:"ignore_headers" mapstructure:"ignore_headers" env:"SHADOW_IGNORE_HEADERS"`
 }
 
 // BasicAuthConfig config
@@ -175,6 +235,21 @@ func NewConfiguration(
 	viper.SetDefault("aws.resign_all_requests", "false")
 	viper.SetDefault("aws.resign_only_expired_date", "false")
 	viper.SetDefault("aws.debug", "false")
+
+	viper.SetDefault("upstream_proxy.url", "")
+	viper.SetDefault("upstream_proxy.username", "")
+	viper.SetDefault("upstream_proxy.password", "")
+
+	viper.SetDefault("latency_replay.mode", "")
+	viper.SetDefault("latency_replay.factor", 1)
+	viper.SetDefault("latency_replay.max_latency_millis", 0)
+
+	viper.SetDefault("tls.enabled", false)
+	viper.SetDefault("tls.cert_file", "")
+	viper.SetDefault("tls.key_file", "")
+	viper.SetDefault("tls.client_ca_file", "")
+	viper.SetDefault("tls.client_auth", "")
+	viper.SetDefault("tls.h2c", false)
 	viper.SetEnvPrefix("")
 	viper.AutomaticEnv()
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
@@ -224,6 +299,12 @@ func NewConfiguration(
 	if os.Getenv("AWS_RESIGN_ALL_REQUESTS") != "" {
 		config.AWS.ResignAllRequests = os.Getenv("AWS_RESIGN_ALL_REQUESTS") == "true"
 	}
+	if os.Getenv("UPSTREAM_PROXY_BYPASS") != "" {
+		config.UpstreamProxy.Bypass = strings.Split(os.Getenv("UPSTREAM_PROXY_BYPASS"), ",")
+	}
+	if os.Getenv("TLS_HOSTS") != "" {
+		config.TLS.Hosts = strings.Split(os.Getenv("TLS_HOSTS"), ",")
+	}
 	if os.Getenv("TEST_ENVS") != "" {
 		config.TestEnvironments = strings.Split(os.Getenv("TEST_ENVS"), ",")
 	}
@@ -18,6 +18,12 @@ const MockRecordModeEnabled = "true"
 // MockURL header
 const MockURL = "X-Mock-Url"
 
+// MockShadow header enables shadow mode for a request
+const MockShadow = "X-Mock-Shadow"
+
+// MockShadowServed header identifies whether upstream or mock response was served in shadow mode
+const MockShadowServed = "X-Mock-Shadow-Served"
+
 // MockScenarioHeader header
 const MockScenarioHeader = "X-Mock-Scenario"
 
@@ -36,12 +42,30 @@ const ContentTypeHeader = "Content-Type"
 // ContentLengthHeader header
 const ContentLengthHeader = "Content-Length"
 
+// ContentEncodingHeader header
+const ContentEncodingHeader = "Content-Encoding"
+
+// AcceptHeader header
+const AcceptHeader = "Accept"
+
+// AcceptEncodingHeader header
+const AcceptEncodingHeader = "Accept-Encoding"
+
+// VaryHeader header
+const VaryHeader = "Vary"
+
 // AuthorizationHeader constant
 const AuthorizationHeader = "Authorization"
 
 // APIKeyHeader constant
 const APIKeyHeader = "x-api-key"
 
+// MockClientCertSubject header with subject of client certificate presented over TLS
+const MockClientCertSubject = "X-Mock-Client-Cert-Subject"
+
+// MockClientCertCommonName header with common name of client certificate presented over TLS
+const MockClientCertCommonName = "X-Mock-Client-Cert-Common-Name"
+
 // MockRequestCount header
 const MockRequestCount = "X-Mock-Request-Count"
 
@@ -51,6 +75,9 @@ const MockResponseStatus = "X-Mock-Response-Status"
 // MockWaitBeforeReply header
 const MockWaitBeforeReply = "X-Mock-Wait-Before-Reply"
 
+// MockLatency header reports delay applied to mock response
+const MockLatency = "X-Mock-Latency"
+
 // ScenarioExt extension
 const ScenarioExt = ".yaml"
 
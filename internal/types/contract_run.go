@@ -0,0 +1,147 @@
+package types
+
+import (
+	"sort"
+	"time"
+)
+
+// Outcomes of scenario in a contract run
+const (
+	OutcomePassed = "passed"
+	OutcomeFailed = "failed"
+	OutcomeFlaky  = "flaky"
+)
+
+// Kinds of contract runs
+const (
+	RunKindContract  = "contract"
+	RunKindMutations = "mutations"
+	RunKindModel     = "model"
+	RunKindWorkflow  = "workflow"
+	RunKindLoad      = "load"
+)
+
+// ContractRun is a recorded producer contract run
+type ContractRun struct {
+	// ID of the run
+	ID string `yaml:"id" json:"id"`
+	// Group of scenarios or suite of the run
+	Group string `yaml:"group" json:"group"`
+	// Kind of run: contract, mutations, model, workflow or load
+	Kind string `yaml:"kind" json:"kind"`
+	// Label such as git sha or release of the producer
+	Label string `yaml:"label" json:"label,omitempty"`
+	// Environment such as staging
+	Environment string `yaml:"environment" json:"environment,omitempty"`
+	// BaseURL of the producer
+	BaseURL        string    `yaml:"base_url" json:"base_url,omitempty"`
+	StartedAt      time.Time `yaml:"started_at" json:"started_at"`
+	DurationMillis int64     `yaml:"duration_millis" json:"duration_millis"`
+	Succeeded      int       `yaml:"succeeded" json:"succeeded"`
+	Failed         int       `yaml:"failed" json:"failed"`
+	Mismatched     int       `yaml:"mismatched" json:"mismatched"`
+	// Scenarios outcome by scenario name
+	Scenarios map[string]*ScenarioOutcome `yaml:"scenarios" json:"scenarios"`
+}
+
+// ScenarioOutcome is the outcome of executions of a scenario in a contract run
+type ScenarioOutcome struct {
+	Executions    int     `yaml:"executions" json:"executions"`
+	Passed        int     `yaml:"passed" json:"passed"`
+	Failed        int     `yaml:"failed" json:"failed"`
+	LatencyMillis float64 `yaml:"latency_millis" json:"latency_millis"`
+	// Failures are reasons of failed executions
+	Failures []string `yaml:"failures" json:"failures,omitempty"`
+	// Flaky is set when scenario passed on retry within the run or of an earlier run with same label
+	Flaky bool `yaml:"flaky" json:"flaky,omitempty"`
+}
+
+// Status returns passed, failed or flaky
+func (o *ScenarioOutcome) Status() string {
+	if o.Flaky {
+		return OutcomeFlaky
+	}
+	if o.Failed > 0 {
+		return OutcomeFailed
+	}
+	return OutcomePassed
+}
+
+// ScenarioNames returns sorted names of scenarios
+func (r *ContractRun) ScenarioNames() []string {
+	names := make([]string, 0, len(r.Scenarios))
+	for name := range r.Scenarios {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ContractRunSummary summarizes a contract run without its scenarios
+type ContractRunSummary struct {
+	ID          string    `yaml:"id" json:"id"`
+	Group       string    `yaml:"group" json:"group"`
+	Kind        string    `yaml:"kind" json:"kind"`
+	Label       string    `yaml:"label" json:"label,omitempty"`
+	Environment string    `yaml:"environment" json:"environment,omitempty"`
+	StartedAt   time.Time `yaml:"started_at" json:"started_at"`
+	Scenarios   int       `yaml:"scenarios" json:"scenarios"`
+	Succeeded   int       `yaml:"succeeded" json:"succeeded"`
+	Failed      int       `yaml:"failed" json:"failed"`
+	Mismatched  int       `yaml:"mismatched" json:"mismatched"`
+	Flaky       int       `yaml:"flaky" json:"flaky"`
+}
+
+// Summary of the run
+func (r *ContractRun) Summary() *ContractRunSummary {
+	summary := &ContractRunSummary{
+		ID:          r.ID,
+		Group:       r.Group,
+		Kind:        r.Kind,
+		Label:       r.Label,
+		Environment: r.Environment,
+		StartedAt:   r.StartedAt,
+		Scenarios:   len(r.Scenarios),
+		Succeeded:   r.Succeeded,
+		Failed:      r.Failed,
+		Mismatched:  r.Mismatched,
+	}
+	for _, outcome := range r.Scenarios {
+		if outcome.Flaky {
+			summary.Flaky++
+		}
+	}
+	return summary
+}
+
+// LatencyRegression of a scenario between two runs
+type LatencyRegression struct {
+	Scenario   string  `yaml:"scenario" json:"scenario"`
+	BaseMillis float64 `yaml:"base_millis" json:"base_millis"`
+	HeadMillis float64 `yaml:"head_millis" json:"head_millis"`
+	// Increase in percent
+	Increase float64 `yaml:"increase" json:"increase"`
+}
+
+// ContractRunComparison compares head run against base run
+type ContractRunComparison struct {
+	Base *ContractRunSummary `yaml:"base" json:"base"`
+	Head *ContractRunSummary `yaml:"head" json:"head"`
+	// NewFailures failed in head but not in base
+	NewFailures []string `yaml:"new_failures" json:"new_failures"`
+	// Fixed failed in base but not in head
+	Fixed []string `yaml:"fixed" json:"fixed"`
+	// StillFailing failed in both runs
+	StillFailing []string `yaml:"still_failing" json:"still_failing"`
+	// Flaky in head run
+	Flaky []string `yaml:"flaky" json:"flaky"`
+	// Added and Removed scenarios of head run
+	Added              []string             `yaml:"added" json:"added"`
+	Removed            []string             `yaml:"removed" json:"removed"`
+	LatencyRegressions []*LatencyRegression `yaml:"latency_regressions" json:"latency_regressions"`
+}
+
+// Regressed returns true if head has new failures or latency regressions
+func (c *ContractRunComparison) Regressed() bool {
+	return len(c.NewFailures) > 0 || len(c.LatencyRegressions) > 0
+}
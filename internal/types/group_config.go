@@ -20,10 +20,30 @@ type GroupConfig struct {
 	MaxAdditionalLatencySecs float64 `json:"max_additional_latency_secs" mapstructure:"max_additional_latency_secs"`
 	// HTTPErrors to return for failure
 	HTTPErrors []int `json:"http_errors" mapstructure:"http_errors"`
+	// RewriteRules to change requests and responses in flight before recording and replay
+	RewriteRules []*RewriteRule `json:"rewrite_rules" mapstructure:"rewrite_rules"`
+	// Latency distribution and bandwidth throttle for scenarios of the group without their own latency
+	Latency *LatencyConfig `json:"latency" mapstructure:"latency"`
+	// LatencyReplay reproduces recorded latency on playback, overriding global latency_replay
+	LatencyReplay *LatencyReplayConfig `json:"latency_replay" mapstructure:"latency_replay"`
+	// Conditional emulates ETag, Last-Modified, 304 and 412 responses for scenarios of the group
+	Conditional *ConditionalConfig `json:"conditional" mapstructure:"conditional"`
+	// Invariants are assertions checked after each step of model-based producer contracts
+	Invariants []string `json:"invariants" mapstructure:"invariants"`
 	rnd        *rand.Rand
 	lock       sync.RWMutex
 }
 
+// Validate checks rewrite rules of the group config
+func (gc *GroupConfig) Validate() error {
+	for _, rule := range gc.RewriteRules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetHTTPStatus accessor
 func (gc *GroupConfig) GetHTTPStatus() int {
 	if !gc.checkInit() {
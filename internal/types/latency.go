@@ -0,0 +1,120 @@
+package types
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LatencyDistribution defines how delay of a mock response is sampled
+type LatencyDistribution string
+
+const (
+	// LatencyFixed always waits fixed_millis
+	LatencyFixed LatencyDistribution = "fixed"
+	// LatencyUniform waits between min_millis and max_millis
+	LatencyUniform LatencyDistribution = "uniform"
+	// LatencyNormal samples normal distribution with mean_millis and stddev_millis
+	LatencyNormal LatencyDistribution = "normal"
+	// LatencyLogNormal samples log-normal distribution with p50_millis and p95_millis
+	LatencyLogNormal LatencyDistribution = "lognormal"
+	// LatencyPercentileTable samples table of percentiles such as p50, p90 and p99
+	LatencyPercentileTable LatencyDistribution = "percentiles"
+)
+
+// LatencyConfig for delaying and throttling mock responses of a scenario or group
+type LatencyConfig struct {
+	// Distribution of delay: fixed, uniform, normal, lognormal or percentiles
+	Distribution LatencyDistribution `yaml:"distribution" json:"distribution" mapstructure:"distribution"`
+	// FixedMillis delay of fixed distribution
+	FixedMillis int64 `yaml:"fixed_millis,omitempty" json:"fixed_millis,omitempty" mapstructure:"fixed_millis"`
+	// MinMillis lower bound of uniform distribution and floor of other distributions
+	MinMillis int64 `yaml:"min_millis,omitempty" json:"min_millis,omitempty" mapstructure:"min_millis"`
+	// MaxMillis upper bound of uniform distribution and cap of other distributions
+	MaxMillis int64 `yaml:"max_millis,omitempty" json:"max_millis,omitempty" mapstructure:"max_millis"`
+	// MeanMillis of normal distribution
+	MeanMillis int64 `yaml:"mean_millis,omitempty" json:"mean_millis,omitempty" mapstructure:"mean_millis"`
+	// StdDevMillis of normal distribution
+	StdDevMillis int64 `yaml:"stddev_millis,omitempty" json:"stddev_millis,omitempty" mapstructure:"stddev_millis"`
+	// P50Millis median of log-normal distribution
+	P50Millis int64 `yaml:"p50_millis,omitempty" json:"p50_millis,omitempty" mapstructure:"p50_millis"`
+	// P95Millis 95th percentile of log-normal distribution
+	P95Millis int64 `yaml:"p95_millis,omitempty" json:"p95_millis,omitempty" mapstructure:"p95_millis"`
+	// Percentiles table such as {p50: 100, p90: 250, p99: 900} for percentiles distribution
+	Percentiles map[string]int64 `yaml:"percentiles,omitempty" json:"percentiles,omitempty" mapstructure:"percentiles"`
+	// JitterMillis adds uniform jitter of +/- millis to sampled delay
+	JitterMillis int64 `yaml:"jitter_millis,omitempty" json:"jitter_millis,omitempty" mapstructure:"jitter_millis"`
+	// BytesPerSecond throttles writing of response body when positive
+	BytesPerSecond int64 `yaml:"bytes_per_second,omitempty" json:"bytes_per_second,omitempty" mapstructure:"bytes_per_second"`
+}
+
+// HasDelay returns true if latency config delays the response
+func (l *LatencyConfig) HasDelay() bool {
+	return l != nil && (l.Distribution != "" || l.JitterMillis > 0)
+}
+
+// Sample returns delay drawn from the distribution with jitter, bounded by min and max millis
+func (l *LatencyConfig) Sample() time.Duration {
+	if !l.HasDelay() {
+		return 0
+	}
+	millis := 0.0
+	switch l.Distribution {
+	case LatencyFixed:
+		millis = float64(l.FixedMillis)
+	case LatencyUniform:
+		millis = float64(l.MinMillis)
+		if l.MaxMillis > l.MinMillis {
+			millis += rand.Float64() * float64(l.MaxMillis-l.MinMillis)
+		}
+	case LatencyNormal:
+		millis = float64(l.MeanMillis) + float64(l.StdDevMillis)*rand.NormFloat64()
+	case LatencyLogNormal:
+		if l.P50Millis > 0 {
+			millis = sampleLogNormal(float64(l.P50Millis), float64(l.P95Millis))
+		}
+	case LatencyPercentileTable:
+		millis = l.samplePercentiles(rand.Float64() * 100)
+	}
+	if l.JitterMillis > 0 {
+		millis += (rand.Float64()*2 - 1) * float64(l.JitterMillis)
+	}
+	if millis < float64(l.MinMillis) {
+		millis = float64(l.MinMillis)
+	}
+	if l.MaxMillis > 0 && millis > float64(l.MaxMillis) {
+		millis = float64(l.MaxMillis)
+	}
+	if millis <= 0 {
+		return 0
+	}
+	return time.Duration(millis * float64(time.Millisecond))
+}
+
+// samplePercentiles interpolates delay of percentile p between entries of the table,
+// using min_millis as p0 and max_millis (or highest entry) as p100
+func (l *LatencyConfig) samplePercentiles(p float64) float64 {
+	type point struct{ p, millis float64 }
+	points := []point{{0, float64(l.MinMillis)}}
+	for k, v := range l.Percentiles {
+		if pct, err := strconv.ParseFloat(strings.TrimPrefix(strings.ToLower(k), "p"), 64); err == nil && pct > 0 && pct < 100 {
+			points = append(points, point{pct, float64(v)})
+		}
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].p < points[j].p })
+	last := points[len(points)-1].millis
+	if l.MaxMillis > 0 {
+		last = math.Max(last, float64(l.MaxMillis))
+	}
+	points = append(points, point{100, last})
+	for i := 1; i < len(points); i++ {
+		if p <= points[i].p {
+			prev := points[i-1]
+			return prev.millis + (points[i].millis-prev.millis)*(p-prev.p)/(points[i].p-prev.p)
+		}
+	}
+	return last
+}
@@ -0,0 +1,100 @@
+package types
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// LatencyReplayMode defines how recorded latency is reproduced on playback
+type LatencyReplayMode string
+
+const (
+	// LatencyReplayRecorded replays exact recorded duration of the scenario
+	LatencyReplayRecorded LatencyReplayMode = "recorded"
+	// LatencyReplayScaled replays recorded duration multiplied by factor
+	LatencyReplayScaled LatencyReplayMode = "scaled"
+	// LatencyReplayDistribution samples log-normal distribution fitted from p50/p95 of all recordings of the scenario
+	LatencyReplayDistribution LatencyReplayMode = "distribution"
+)
+
+// LatencyReplayConfig for reproducing recorded latency instead of answering instantly
+type LatencyReplayConfig struct {
+	// Mode of replay: recorded, scaled or distribution; empty disables replay
+	Mode LatencyReplayMode `yaml:"mode" json:"mode" mapstructure:"mode" env:"LATENCY_REPLAY_MODE"`
+	// Factor to multiply replayed latency for scaled and distribution modes (default 1)
+	Factor float64 `yaml:"factor" json:"factor" mapstructure:"factor" env:"LATENCY_REPLAY_FACTOR"`
+	// MaxLatencyMillis caps replayed latency when positive
+	MaxLatencyMillis int64 `yaml:"max_latency_millis" json:"max_latency_millis" mapstructure:"max_latency_millis" env:"LATENCY_REPLAY_MAX_MILLIS"`
+}
+
+// Enabled returns true if latency replay is configured
+func (c *LatencyReplayConfig) Enabled() bool {
+	return c != nil && c.Mode != ""
+}
+
+// Delay returns latency to replay given recorded latency of the scenario and latencies of all its recordings
+func (c *LatencyReplayConfig) Delay(recordedMillis int64, samples []int64) time.Duration {
+	p50, p95 := LatencyPercentiles(samples)
+	return c.DelayWithPercentiles(recordedMillis, p50, p95)
+}
+
+// DelayWithPercentiles returns latency to replay given recorded latency of the scenario and p50/p95 of all its
+// recordings, e.g. cached by the scenario repository
+func (c *LatencyReplayConfig) DelayWithPercentiles(recordedMillis int64, p50 int64, p95 int64) time.Duration {
+	if !c.Enabled() {
+		return 0
+	}
+	factor := c.Factor
+	if factor <= 0 {
+		factor = 1
+	}
+	millis := float64(recordedMillis)
+	switch c.Mode {
+	case LatencyReplayScaled:
+		millis *= factor
+	case LatencyReplayDistribution:
+		if p50 > 0 {
+			millis = sampleLogNormal(float64(p50), float64(p95)) * factor
+		} else {
+			millis *= factor
+		}
+	}
+	if c.MaxLatencyMillis > 0 && millis > float64(c.MaxLatencyMillis) {
+		millis = float64(c.MaxLatencyMillis)
+	}
+	if millis <= 0 {
+		return 0
+	}
+	return time.Duration(millis * float64(time.Millisecond))
+}
+
+// LatencyPercentiles returns p50 and p95 of latencies in millis, ignoring non-positive values
+func LatencyPercentiles(samples []int64) (p50 int64, p95 int64) {
+	sorted := make([]int64, 0, len(samples))
+	for _, s := range samples {
+		if s > 0 {
+			sorted = append(sorted, s)
+		}
+	}
+	if len(sorted) == 0 {
+		return 0, 0
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return percentile(sorted, 0.50), percentile(sorted, 0.95)
+}
+
+func percentile(sorted []int64, p float64) int64 {
+	return sorted[int(math.Ceil(p*float64(len(sorted))))-1]
+}
+
+// sampleLogNormal draws from log-normal distribution whose median is p50 and 95th percentile is p95
+func sampleLogNormal(p50 float64, p95 float64) float64 {
+	mu := math.Log(p50)
+	sigma := 0.0
+	if p95 > p50 {
+		sigma = (math.Log(p95) - mu) / 1.6449
+	}
+	return math.Exp(mu + sigma*rand.NormFloat64())
+}
@@ -0,0 +1,63 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldReplayRecordedLatency(t *testing.T) {
+	// GIVEN latency replay configs
+	var disabled *LatencyReplayConfig
+	recorded := &LatencyReplayConfig{Mode: LatencyReplayRecorded}
+	scaled := &LatencyReplayConfig{Mode: LatencyReplayScaled, Factor: 0.5}
+	capped := &LatencyReplayConfig{Mode: LatencyReplayScaled, Factor: 10, MaxLatencyMillis: 500}
+
+	// WHEN computing delay for a recording of 200ms
+	// THEN it should honor mode, factor and cap
+	require.Equal(t, time.Duration(0), disabled.Delay(200, nil))
+	require.Equal(t, 200*time.Millisecond, recorded.Delay(200, nil))
+	require.Equal(t, 100*time.Millisecond, scaled.Delay(200, nil))
+	require.Equal(t, 500*time.Millisecond, capped.Delay(200, nil))
+	require.Equal(t, time.Duration(0), recorded.Delay(0, nil))
+}
+
+func Test_ShouldReplayLatencyDistribution(t *testing.T) {
+	// GIVEN latencies of all recordings of a scenario
+	samples := []int64{0, 100, 100, 100, 100, 100, 100, 100, 100, 100, 100}
+	config := &LatencyReplayConfig{Mode: LatencyReplayDistribution}
+
+	// WHEN computing percentiles
+	p50, p95 := LatencyPercentiles(samples)
+
+	// THEN zero latencies should be ignored
+	require.Equal(t, int64(100), p50)
+	require.Equal(t, int64(100), p95)
+	// AND distribution without spread should replay median
+	require.Equal(t, 100*time.Millisecond, config.Delay(300, samples))
+	// AND distribution without samples should replay recorded latency
+	require.Equal(t, 300*time.Millisecond, config.Delay(300, nil))
+
+	// WHEN sampling distribution with spread
+	samples = []int64{80, 90, 100, 110, 120, 130, 150, 200, 300, 400}
+	p50, p95 = LatencyPercentiles(samples)
+	require.Equal(t, int64(120), p50)
+	require.Equal(t, int64(400), p95)
+	below := 0
+	for i := 0; i < 1000; i++ {
+		if config.Delay(0, samples) <= 120*time.Millisecond {
+			below++
+		}
+	}
+	// THEN roughly half of samples should be below median
+	require.InDelta(t, 500, below, 100)
+}
+
+func Test_ShouldReturnRecordedLatencyOfScenario(t *testing.T) {
+	started := time.Now()
+	scenario := &APIScenario{StartTime: started, EndTime: started.Add(250 * time.Millisecond)}
+	require.Equal(t, int64(250), scenario.RecordedLatencyMillis())
+	require.Equal(t, int64(250), scenario.ToKeyData().LatencyMillis)
+	require.Equal(t, int64(0), (&APIScenario{EndTime: started}).RecordedLatencyMillis())
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldSampleLatencyDistributions(t *testing.T) {
+	// GIVEN latency configs with different distributions
+	var none *LatencyConfig
+	fixed := &LatencyConfig{Distribution: LatencyFixed, FixedMillis: 50}
+	uniform := &LatencyConfig{Distribution: LatencyUniform, MinMillis: 10, MaxMillis: 20}
+	normal := &LatencyConfig{Distribution: LatencyNormal, MeanMillis: 100, StdDevMillis: 10, MinMillis: 50, MaxMillis: 150}
+	logNormal := &LatencyConfig{Distribution: LatencyLogNormal, P50Millis: 100, P95Millis: 400, MaxMillis: 1000}
+	table := &LatencyConfig{Distribution: LatencyPercentileTable, Percentiles: map[string]int64{"p50": 100, "p99": 200}}
+	jitter := &LatencyConfig{Distribution: LatencyFixed, FixedMillis: 100, JitterMillis: 10}
+
+	// WHEN sampling delays
+	// THEN they should stay within bounds of the distribution
+	require.Equal(t, time.Duration(0), none.Sample())
+	require.Equal(t, 50*time.Millisecond, fixed.Sample())
+	for i := 0; i < 100; i++ {
+		require.True(t, uniform.Sample() >= 10*time.Millisecond && uniform.Sample() <= 20*time.Millisecond)
+		require.True(t, normal.Sample() >= 50*time.Millisecond && normal.Sample() <= 150*time.Millisecond)
+		require.True(t, logNormal.Sample() > 0 && logNormal.Sample() <= time.Second)
+		require.True(t, table.Sample() <= 200*time.Millisecond)
+		require.True(t, jitter.Sample() >= 90*time.Millisecond && jitter.Sample() <= 110*time.Millisecond)
+	}
+}
+
+func Test_ShouldInterpolatePercentileTable(t *testing.T) {
+	table := &LatencyConfig{Percentiles: map[string]int64{"p50": 100, "P90": 300, "p99": 1000}, MaxMillis: 2000}
+	require.Equal(t, 0.0, table.samplePercentiles(0))
+	require.Equal(t, 50.0, table.samplePercentiles(25))
+	require.Equal(t, 100.0, table.samplePercentiles(50))
+	require.Equal(t, 200.0, table.samplePercentiles(70))
+	require.Equal(t, 1000.0, table.samplePercentiles(99))
+	require.Equal(t, 2000.0, table.samplePercentiles(100))
+}
@@ -0,0 +1,235 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LoadConfig configures load mode of producer contracts
+type LoadConfig struct {
+	// DurationSecs of the load test
+	DurationSecs int `yaml:"duration_secs" json:"duration_secs"`
+	// TargetRPS is arrival rate of requests across virtual users, unlimited if zero
+	TargetRPS float64 `yaml:"target_rps" json:"target_rps,omitempty"`
+	// VirtualUsers sending requests concurrently (default 10)
+	VirtualUsers int `yaml:"virtual_users" json:"virtual_users,omitempty"`
+	// RampUpSecs to linearly reach virtual users and target rps
+	RampUpSecs int `yaml:"ramp_up_secs" json:"ramp_up_secs,omitempty"`
+	// Weights of scenarios by name, scenarios without weight default to 1 and weight 0 skips the scenario
+	Weights map[string]int `yaml:"weights" json:"weights,omitempty"`
+	// Thresholds of SLO
+	Thresholds LoadThresholds `yaml:"thresholds" json:"thresholds,omitempty"`
+}
+
+// LoadThresholds of SLO that must be met by load test, zero values are not checked
+type LoadThresholds struct {
+	P50Millis     float64 `yaml:"p50_millis" json:"p50_millis,omitempty"`
+	P90Millis     float64 `yaml:"p90_millis" json:"p90_millis,omitempty"`
+	P99Millis     float64 `yaml:"p99_millis" json:"p99_millis,omitempty"`
+	MaxErrorRate  float64 `yaml:"max_error_rate" json:"max_error_rate,omitempty"`
+	MinThroughput float64 `yaml:"min_throughput" json:"min_throughput,omitempty"`
+}
+
+// Duration of load test
+func (lc *LoadConfig) Duration() time.Duration {
+	return time.Duration(lc.DurationSecs) * time.Second
+}
+
+// RampUp duration of load test
+func (lc *LoadConfig) RampUp() time.Duration {
+	return time.Duration(lc.RampUpSecs) * time.Second
+}
+
+// Users returns number of virtual users
+func (lc *LoadConfig) Users() int {
+	if lc.VirtualUsers <= 0 {
+		return 10
+	}
+	return lc.VirtualUsers
+}
+
+// Weight returns weight of the scenario
+func (lc *LoadConfig) Weight(name string) int {
+	if w, ok := lc.Weights[name]; ok {
+		return w
+	}
+	return 1
+}
+
+// Validate load config
+func (lc *LoadConfig) Validate() error {
+	if lc.DurationSecs <= 0 {
+		return fmt.Errorf("load duration_secs must be positive")
+	}
+	if lc.TargetRPS < 0 || lc.VirtualUsers < 0 || lc.RampUpSecs < 0 {
+		return fmt.Errorf("load target_rps, virtual_users and ramp_up_secs cannot be negative")
+	}
+	if lc.RampUpSecs > lc.DurationSecs {
+		return fmt.Errorf("load ramp_up_secs %d exceeds duration_secs %d", lc.RampUpSecs, lc.DurationSecs)
+	}
+	return nil
+}
+
+// LoadLatency percentiles in milliseconds
+type LoadLatency struct {
+	Min  float64 `yaml:"min" json:"min"`
+	Mean float64 `yaml:"mean" json:"mean"`
+	P50  float64 `yaml:"p50" json:"p50"`
+	P90  float64 `yaml:"p90" json:"p90"`
+	P99  float64 `yaml:"p99" json:"p99"`
+	Max  float64 `yaml:"max" json:"max"`
+}
+
+// NewLoadLatency computes percentiles of latencies using nearest rank
+func NewLoadLatency(latencies []float64) LoadLatency {
+	res := LoadLatency{}
+	if len(latencies) == 0 {
+		return res
+	}
+	sorted := append([]float64(nil), latencies...)
+	sort.Float64s(sorted)
+	sum := 0.0
+	for _, l := range sorted {
+		sum += l
+	}
+	rank := func(p float64) float64 {
+		n := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+		if n < 0 {
+			n = 0
+		}
+		return sorted[n]
+	}
+	res.Min = sorted[0]
+	res.Max = sorted[len(sorted)-1]
+	res.Mean = sum / float64(len(sorted))
+	res.P50 = rank(50)
+	res.P90 = rank(90)
+	res.P99 = rank(99)
+	return res
+}
+
+// ScenarioLoadStats of a scenario in load test
+type ScenarioLoadStats struct {
+	Requests     int            `yaml:"requests" json:"requests"`
+	Errors       int            `yaml:"errors" json:"errors"`
+	ErrorRate    float64        `yaml:"error_rate" json:"error_rate"`
+	Throughput   float64        `yaml:"throughput" json:"throughput"`
+	Latency      LoadLatency    `yaml:"latency" json:"latency"`
+	StatusCounts map[string]int `yaml:"status_counts" json:"status_counts"`
+}
+
+// SLOResult of a threshold
+type SLOResult struct {
+	Name      string  `yaml:"name" json:"name"`
+	Threshold float64 `yaml:"threshold" json:"threshold"`
+	Actual    float64 `yaml:"actual" json:"actual"`
+	Passed    bool    `yaml:"passed" json:"passed"`
+}
+
+// LoadReport summarizes load test
+type LoadReport struct {
+	DurationSecs   float64                       `yaml:"duration_secs" json:"duration_secs"`
+	Requests       int                           `yaml:"requests" json:"requests"`
+	Errors         int                           `yaml:"errors" json:"errors"`
+	ErrorRate      float64                       `yaml:"error_rate" json:"error_rate"`
+	Throughput     float64                       `yaml:"throughput" json:"throughput"`
+	Latency        LoadLatency                   `yaml:"latency" json:"latency"`
+	StatusCounts   map[string]int                `yaml:"status_counts" json:"status_counts"`
+	ErrorsByStatus map[string]int                `yaml:"errors_by_status" json:"errors_by_status"`
+	Scenarios      map[string]*ScenarioLoadStats `yaml:"scenarios" json:"scenarios"`
+	SLO            []SLOResult                   `yaml:"slo" json:"slo,omitempty"`
+	Passed         bool                          `yaml:"passed" json:"passed"`
+}
+
+// EvaluateSLO checks thresholds against the report
+func (r *LoadReport) EvaluateSLO(thresholds LoadThresholds) {
+	r.SLO = nil
+	r.Passed = true
+	check := func(name string, threshold float64, actual float64, atMost bool) {
+		if threshold <= 0 {
+			return
+		}
+		passed := actual <= threshold
+		if !atMost {
+			passed = actual >= threshold
+		}
+		r.SLO = append(r.SLO, SLOResult{Name: name, Threshold: threshold, Actual: actual, Passed: passed})
+		r.Passed = r.Passed && passed
+	}
+	check("p50_millis", thresholds.P50Millis, r.Latency.P50, true)
+	check("p90_millis", thresholds.P90Millis, r.Latency.P90, true)
+	check("p99_millis", thresholds.P99Millis, r.Latency.P99, true)
+	check("max_error_rate", thresholds.MaxErrorRate, r.ErrorRate, true)
+	check("min_throughput", thresholds.MinThroughput, r.Throughput, false)
+}
+
+// PrometheusText exports report in Prometheus text exposition format
+func (r *LoadReport) PrometheusText() string {
+	var sb strings.Builder
+	gauge := func(name string, help string) {
+		sb.WriteString(fmt.Sprintf("# HELP %s %s\n# TYPE %s gauge\n", name, help, name))
+	}
+	gauge("load_requests_total", "Requests sent by load test")
+	sb.WriteString(fmt.Sprintf("load_requests_total %d\n", r.Requests))
+	gauge("load_errors_total", "Failed requests of load test")
+	sb.WriteString(fmt.Sprintf("load_errors_total %d\n", r.Errors))
+	gauge("load_error_rate", "Ratio of failed requests")
+	sb.WriteString(fmt.Sprintf("load_error_rate %g\n", r.ErrorRate))
+	gauge("load_throughput_rps", "Requests per second")
+	sb.WriteString(fmt.Sprintf("load_throughput_rps %g\n", r.Throughput))
+
+	gauge("load_latency_milliseconds", "Latency percentiles of requests")
+	writeLatency := func(labels string, l LoadLatency) {
+		for _, q := range []struct {
+			quantile string
+			value    float64
+		}{{"0.5", l.P50}, {"0.9", l.P90}, {"0.99", l.P99}} {
+			sb.WriteString(fmt.Sprintf("load_latency_milliseconds{%squantile=\"%s\"} %g\n", labels, q.quantile, q.value))
+		}
+	}
+	writeLatency("", r.Latency)
+	for _, name := range r.scenarioNames() {
+		writeLatency(fmt.Sprintf("scenario=\"%s\",", name), r.Scenarios[name].Latency)
+	}
+
+	gauge("load_responses_total", "Responses by scenario and status")
+	for _, name := range r.scenarioNames() {
+		stats := r.Scenarios[name]
+		for _, status := range sortedCountKeys(stats.StatusCounts) {
+			sb.WriteString(fmt.Sprintf("load_responses_total{scenario=\"%s\",status=\"%s\"} %d\n",
+				name, status, stats.StatusCounts[status]))
+		}
+	}
+	if len(r.SLO) > 0 {
+		gauge("load_slo_passed", "SLO threshold result, 1 if passed")
+		for _, slo := range r.SLO {
+			passed := 0
+			if slo.Passed {
+				passed = 1
+			}
+			sb.WriteString(fmt.Sprintf("load_slo_passed{slo=\"%s\"} %d\n", slo.Name, passed))
+		}
+	}
+	return sb.String()
+}
+
+func (r *LoadReport) scenarioNames() []string {
+	names := make([]string, 0, len(r.Scenarios))
+	for name := range r.Scenarios {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedCountKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldComputeLoadLatencyPercentiles(t *testing.T) {
+	// GIVEN latencies from 1 to 100 millis
+	var latencies []float64
+	for i := 100; i > 0; i-- {
+		latencies = append(latencies, float64(i))
+	}
+
+	// WHEN computing percentiles
+	latency := NewLoadLatency(latencies)
+
+	// THEN nearest rank should be used
+	require.Equal(t, LoadLatency{Min: 1, Mean: 50.5, P50: 50, P90: 90, P99: 99, Max: 100}, latency)
+	require.Equal(t, LoadLatency{}, NewLoadLatency(nil))
+}
+
+func Test_ShouldEvaluateLoadSLOAndExportPrometheus(t *testing.T) {
+	// GIVEN a load report
+	report := &LoadReport{
+		Requests:   100,
+		Errors:     2,
+		ErrorRate:  0.02,
+		Throughput: 50,
+		Latency:    LoadLatency{P50: 20, P90: 80, P99: 200},
+		Scenarios: map[string]*ScenarioLoadStats{
+			"get_items": {Requests: 100, Errors: 2, StatusCounts: map[string]int{"200": 98, "500": 2}},
+		},
+	}
+
+	// WHEN evaluating thresholds
+	report.EvaluateSLO(LoadThresholds{P90Millis: 100, P99Millis: 150, MinThroughput: 40})
+
+	// THEN only p99 should fail
+	require.False(t, report.Passed)
+	require.Len(t, report.SLO, 3)
+	require.Equal(t, SLOResult{Name: "p99_millis", Threshold: 150, Actual: 200}, report.SLO[1])
+	require.True(t, report.SLO[0].Passed)
+	require.True(t, report.SLO[2].Passed)
+
+	// AND report should be exported as Prometheus text
+	text := report.PrometheusText()
+	require.True(t, strings.Contains(text, "# TYPE load_requests_total gauge\nload_requests_total 100\n"))
+	require.Contains(t, text, `load_latency_milliseconds{quantile="0.99"} 200`)
+	require.Contains(t, text, `load_responses_total{scenario="get_items",status="500"} 2`)
+	require.Contains(t, text, `load_slo_passed{slo="p99_millis"} 0`)
+
+	// AND invalid configs should be rejected
+	require.Error(t, (&LoadConfig{}).Validate())
+	require.Error(t, (&LoadConfig{DurationSecs: 1, RampUpSecs: 2}).Validate())
+	require.NoError(t, (&LoadConfig{DurationSecs: 2, RampUpSecs: 1}).Validate())
+	require.Equal(t, 10, (&LoadConfig{}).Users())
+	require.Equal(t, 1, (&LoadConfig{Weights: map[string]int{"a": 0}}).Weight("b"))
+}
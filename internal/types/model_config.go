@@ -0,0 +1,65 @@
+package types
+
+// ModelConfig configures model mode of producer contracts that generates random valid sequences of API calls
+// from state machines of a group's scenarios
+type ModelConfig struct {
+	// Sequences to generate and execute (default 20)
+	Sequences int `yaml:"sequences" json:"sequences,omitempty"`
+	// MaxSteps of a sequence (default 10)
+	MaxSteps int `yaml:"max_steps" json:"max_steps,omitempty"`
+	// Seed of random sequences to reproduce a run, random if zero
+	Seed int64 `yaml:"seed" json:"seed,omitempty"`
+	// InitialState of sequences, empty for session without state
+	InitialState string `yaml:"initial_state" json:"initial_state,omitempty"`
+	// Invariants are assertions checked after each step in addition to invariants of group config
+	Invariants []string `yaml:"invariants" json:"invariants,omitempty"`
+}
+
+// SequenceCount returns number of sequences to generate
+func (mc *ModelConfig) SequenceCount() int {
+	if mc.Sequences <= 0 {
+		return 20
+	}
+	return mc.Sequences
+}
+
+// Steps returns max steps of a sequence
+func (mc *ModelConfig) Steps() int {
+	if mc.MaxSteps <= 0 {
+		return 10
+	}
+	return mc.MaxSteps
+}
+
+// ModelReport summarizes sequences executed in model mode
+type ModelReport struct {
+	// Seed to reproduce the sequences
+	Seed int64 `yaml:"seed" json:"seed"`
+	// Sequences executed, stops at the first failing sequence
+	Sequences int `yaml:"sequences" json:"sequences"`
+	// Steps executed across sequences
+	Steps int `yaml:"steps" json:"steps"`
+	// StatesVisited by executed steps
+	StatesVisited []string `yaml:"states_visited" json:"states_visited"`
+	// UnvisitedStates of the state machine that no sequence reached
+	UnvisitedStates []string `yaml:"unvisited_states" json:"unvisited_states"`
+	// Failure of the failing sequence if any
+	Failure *ModelFailure `yaml:"failure" json:"failure,omitempty"`
+}
+
+// Passed returns true if no sequence failed
+func (r *ModelReport) Passed() bool {
+	return r.Failure == nil
+}
+
+// ModelFailure describes a failing sequence and the minimal sequence that still fails
+type ModelFailure struct {
+	// Sequence of scenario names that failed
+	Sequence []string `yaml:"sequence" json:"sequence"`
+	// Minimal sequence of scenario names after shrinking
+	Minimal []string `yaml:"minimal" json:"minimal"`
+	// Error of the minimal sequence
+	Error string `yaml:"error" json:"error"`
+	// ShrinkAttempts executed to find the minimal sequence
+	ShrinkAttempts int `yaml:"shrink_attempts" json:"shrink_attempts"`
+}
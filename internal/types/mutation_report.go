@@ -0,0 +1,99 @@
+package types
+
+import "sort"
+
+// Outcomes of mutations
+const (
+	// MutationKilled when the producer responded with the expected status class
+	MutationKilled = "killed"
+	// MutationSurvived when the producer accepted or rejected the mutation against the expected status class
+	MutationSurvived = "survived"
+	// MutationCrashed when the producer failed with a 5xx or without a response
+	MutationCrashed = "crashed"
+)
+
+// MutationScore counts outcomes of mutations of an endpoint or of all endpoints
+type MutationScore struct {
+	// Endpoint such as POST /orders, empty for all endpoints
+	Endpoint string `yaml:"endpoint" json:"endpoint,omitempty"`
+	Total    int    `yaml:"total" json:"total"`
+	Killed   int    `yaml:"killed" json:"killed"`
+	Survived int    `yaml:"survived" json:"survived"`
+	Crashed  int    `yaml:"crashed" json:"crashed"`
+	// Score is percent of killed mutations
+	Score float64 `yaml:"score" json:"score"`
+}
+
+// Add counts outcome of a mutation and updates score
+func (s *MutationScore) Add(outcome string) {
+	s.Total++
+	switch outcome {
+	case MutationKilled:
+		s.Killed++
+	case MutationSurvived:
+		s.Survived++
+	case MutationCrashed:
+		s.Crashed++
+	}
+	s.Score = float64(s.Killed) / float64(s.Total) * 100
+}
+
+// MutationResult is the outcome of an executed mutation
+type MutationResult struct {
+	// Key of the execution in results or errors
+	Key      string `yaml:"key" json:"key"`
+	Endpoint string `yaml:"endpoint" json:"endpoint"`
+	Scenario string `yaml:"scenario" json:"scenario"`
+	// Kind of mutation such as null or above-maximum
+	Kind string `yaml:"kind" json:"kind"`
+	// Field of the mutation if any, combinatorial mutations join both fields such as "age+email"
+	Field string `yaml:"field" json:"field,omitempty"`
+	// Expected status class such as 4xx or not-5xx
+	Expected string `yaml:"expected" json:"expected"`
+	// Status of the response, 0 without a response
+	Status  int    `yaml:"status" json:"status"`
+	Outcome string `yaml:"outcome" json:"outcome"`
+	// Payload is the exact request body sent
+	Payload string `yaml:"payload" json:"payload"`
+}
+
+// MutationReport summarizes mutation score per endpoint with the mutations that survived or crashed
+type MutationReport struct {
+	MutationScore `yaml:",inline"`
+	// Endpoints sorted by endpoint
+	Endpoints []*MutationScore `yaml:"endpoints" json:"endpoints"`
+	// Survivors are mutations that the producer didn't handle as expected
+	Survivors []*MutationResult `yaml:"survivors" json:"survivors"`
+	// Crashes are mutations that failed with a 5xx or without a response
+	Crashes []*MutationResult `yaml:"crashes" json:"crashes"`
+}
+
+// NewMutationReport builds report from results of mutations
+func NewMutationReport(results []*MutationResult) *MutationReport {
+	report := &MutationReport{
+		Endpoints: make([]*MutationScore, 0),
+		Survivors: make([]*MutationResult, 0),
+		Crashes:   make([]*MutationResult, 0),
+	}
+	endpoints := make(map[string]*MutationScore)
+	for _, res := range results {
+		report.Add(res.Outcome)
+		score := endpoints[res.Endpoint]
+		if score == nil {
+			score = &MutationScore{Endpoint: res.Endpoint}
+			endpoints[res.Endpoint] = score
+			report.Endpoints = append(report.Endpoints, score)
+		}
+		score.Add(res.Outcome)
+		switch res.Outcome {
+		case MutationSurvived:
+			report.Survivors = append(report.Survivors, res)
+		case MutationCrashed:
+			report.Crashes = append(report.Crashes, res)
+		}
+	}
+	sort.Slice(report.Endpoints, func(i, j int) bool {
+		return report.Endpoints[i].Endpoint < report.Endpoints[j].Endpoint
+	})
+	return report
+}
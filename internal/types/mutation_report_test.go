@@ -0,0 +1,42 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldBuildMutationReportByEndpoint(t *testing.T) {
+	// GIVEN results of mutations of two endpoints
+	results := []*MutationResult{
+		{Key: "create-null-sku_0", Endpoint: "POST /orders", Outcome: MutationKilled},
+		{Key: "create-null-qty_1", Endpoint: "POST /orders", Outcome: MutationSurvived, Payload: `{"qty":null}`},
+		{Key: "create-sec-sqli_2", Endpoint: "POST /orders", Outcome: MutationCrashed},
+		{Key: "create-null-sku_3", Endpoint: "POST /orders", Outcome: MutationKilled},
+		{Key: "update-null-sku_0", Endpoint: "PUT /orders/{id}", Outcome: MutationKilled},
+	}
+	// WHEN building report
+	report := NewMutationReport(results)
+	// THEN score should be counted for all and each endpoint
+	require.Equal(t, 5, report.Total)
+	require.Equal(t, 3, report.Killed)
+	require.Equal(t, 1, report.Survived)
+	require.Equal(t, 1, report.Crashed)
+	require.Equal(t, 60.0, report.Score)
+	require.Equal(t, 2, len(report.Endpoints))
+	require.Equal(t, "POST /orders", report.Endpoints[0].Endpoint)
+	require.Equal(t, 50.0, report.Endpoints[0].Score)
+	require.Equal(t, 100.0, report.Endpoints[1].Score)
+	// AND survivors and crashes should be listed with payload
+	require.Equal(t, []*MutationResult{results[1]}, report.Survivors)
+	require.Equal(t, `{"qty":null}`, report.Survivors[0].Payload)
+	require.Equal(t, []*MutationResult{results[2]}, report.Crashes)
+}
+
+func Test_ShouldBuildEmptyMutationReport(t *testing.T) {
+	report := NewMutationReport(nil)
+	require.Equal(t, 0, report.Total)
+	require.Equal(t, 0.0, report.Score)
+	require.NotNil(t, report.Endpoints)
+	require.NotNil(t, report.Survivors)
+}
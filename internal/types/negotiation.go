@@ -0,0 +1,109 @@
+package types
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SupportedEncodings content codings for compressing responses in order of preference
+var SupportedEncodings = []string{"br", "gzip", "deflate"}
+
+// QualityValue is an element of Accept, Accept-Encoding or similar header with its q-value
+type QualityValue struct {
+	Value string
+	Q     float64
+}
+
+// ParseQualityValues parses header such as "text/html;q=0.8, application/json" ordered by descending q-value
+func ParseQualityValues(header string) (values []QualityValue) {
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		value := strings.ToLower(strings.TrimSpace(params[0]))
+		if value == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range params[1:] {
+			name, val, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.ToLower(strings.TrimSpace(name)) == "q" {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		values = append(values, QualityValue{Value: value, Q: q})
+	}
+	sort.SliceStable(values, func(i, j int) bool {
+		return values[i].Q > values[j].Q
+	})
+	return
+}
+
+// NegotiateContentType returns offered media type with highest q-value in accept header, preferring earlier offers on ties.
+// Empty accept header selects the first offer and false is returned when nothing is acceptable.
+func NegotiateContentType(accept string, offered []string) (string, bool) {
+	if len(offered) == 0 {
+		return "", false
+	}
+	if strings.TrimSpace(accept) == "" {
+		return offered[0], true
+	}
+	ranges := ParseQualityValues(accept)
+	best, bestQ := "", 0.0
+	for _, offer := range offered {
+		if q := mediaTypeQuality(ranges, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best, bestQ > 0
+}
+
+// mediaTypeQuality returns q-value of the most specific media range matching the media type
+func mediaTypeQuality(ranges []QualityValue, mediaType string) float64 {
+	mediaType = strings.ToLower(strings.TrimSpace(strings.Split(mediaType, ";")[0]))
+	mainType, _, _ := strings.Cut(mediaType, "/")
+	q, specificity := 0.0, -1
+	for _, r := range ranges {
+		next := -1
+		switch {
+		case r.Value == mediaType:
+			next = 2
+		case r.Value == mainType+"/*":
+			next = 1
+		case r.Value == "*/*" || r.Value == "*":
+			next = 0
+		}
+		if next > specificity {
+			q, specificity = r.Q, next
+		}
+	}
+	return q
+}
+
+// NegotiateEncoding returns content coding among supported codings with highest q-value in accept-encoding header.
+// Empty string means identity, i.e., no encoding.
+func NegotiateEncoding(acceptEncoding string) string {
+	codings := ParseQualityValues(acceptEncoding)
+	best, bestQ := "", 0.0
+	for _, coding := range codings {
+		if coding.Value == "identity" {
+			bestQ = coding.Q
+		}
+	}
+	for _, encoding := range SupportedEncodings {
+		q := 0.0
+		for _, coding := range codings {
+			if coding.Value == encoding {
+				q = coding.Q
+				break
+			} else if coding.Value == "*" {
+				q = coding.Q
+			}
+		}
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
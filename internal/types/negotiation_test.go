@@ -0,0 +1,64 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldParseQualityValues(t *testing.T) {
+	values := ParseQualityValues("text/csv;q=0.5, application/json, application/xml;q=0.9;charset=utf-8")
+	require.Equal(t, []QualityValue{
+		{Value: "application/json", Q: 1},
+		{Value: "application/xml", Q: 0.9},
+		{Value: "text/csv", Q: 0.5},
+	}, values)
+}
+
+func Test_ShouldNegotiateContentType(t *testing.T) {
+	offered := []string{"application/json", "application/xml", "text/csv"}
+	contentType, ok := NegotiateContentType("", offered)
+	require.True(t, ok)
+	require.Equal(t, "application/json", contentType)
+	contentType, ok = NegotiateContentType("text/csv, application/json;q=0.5", offered)
+	require.True(t, ok)
+	require.Equal(t, "text/csv", contentType)
+	contentType, ok = NegotiateContentType("text/*;q=0.8, */*;q=0.1", offered)
+	require.True(t, ok)
+	require.Equal(t, "text/csv", contentType)
+	contentType, ok = NegotiateContentType("*/*, application/json;q=0", offered)
+	require.True(t, ok)
+	require.Equal(t, "application/xml", contentType)
+	_, ok = NegotiateContentType("image/png", offered)
+	require.False(t, ok)
+}
+
+func Test_ShouldNegotiateEncoding(t *testing.T) {
+	require.Equal(t, "", NegotiateEncoding(""))
+	require.Equal(t, "gzip", NegotiateEncoding("gzip"))
+	require.Equal(t, "br", NegotiateEncoding("gzip, deflate, br"))
+	require.Equal(t, "gzip", NegotiateEncoding("br;q=0.5, gzip"))
+	require.Equal(t, "br", NegotiateEncoding("*"))
+	require.Equal(t, "", NegotiateEncoding("identity, gzip;q=0.5"))
+	require.Equal(t, "", NegotiateEncoding("gzip;q=0, zstd"))
+}
+
+func Test_ShouldNegotiateResponseVariant(t *testing.T) {
+	response := APIResponse{
+		Headers:  map[string][]string{ContentTypeHeader: {"application/json"}},
+		Contents: `{"id":1}`,
+		Variants: []ResponseVariant{
+			{ContentType: "application/xml", Contents: "<id>1</id>"},
+			{ContentType: "text/csv", Contents: "id\n1"},
+		},
+	}
+	require.Equal(t, []string{"application/json", "application/xml", "text/csv"}, response.ContentTypes())
+	variant, ok := response.NegotiateVariant("application/json")
+	require.True(t, ok)
+	require.Nil(t, variant)
+	variant, ok = response.NegotiateVariant("text/csv;q=0.9, application/xml;q=0.4")
+	require.True(t, ok)
+	require.Equal(t, "id\n1", variant.Contents)
+	_, ok = response.NegotiateVariant("application/pdf")
+	require.False(t, ok)
+}
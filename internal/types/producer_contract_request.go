@@ -16,6 +16,10 @@ type ProducerContractRequest struct {
 	RunMutations bool `yaml:"run_mutations" json:"run_mutations"`
 	// RecordResults determines if contract validation results should be stored
 	RecordResults bool `yaml:"record_results" json:"record_results"`
+	// RunLabel of recorded run such as git sha or release
+	RunLabel string `yaml:"run_label" json:"run_label,omitempty"`
+	// Environment of recorded run such as staging
+	Environment string `yaml:"environment" json:"environment,omitempty"`
 	// Verbose setting
 	Verbose bool `yaml:"verbose" json:"verbose"`
 	// SpecContent optional OpenAPI spec (YAML or JSON) used for response schema validation.
@@ -23,6 +27,18 @@ type ProducerContractRequest struct {
 	SpecContent string `yaml:"spec_content" json:"spec_content,omitempty"`
 	// DryRun lists the scenarios that would run without actually executing them.
 	DryRun bool `yaml:"dry_run" json:"dry_run"`
+	// Load runs scenarios of group as load test for a duration instead of execution times
+	Load *LoadConfig `yaml:"load" json:"load,omitempty"`
+	// Model executes random valid sequences of API calls from state machines of group scenarios
+	Model *ModelConfig `yaml:"model" json:"model,omitempty"`
+	// Concurrency of workers executing independent scenarios of a group (default 1 runs sequentially)
+	Concurrency int `yaml:"concurrency" json:"concurrency,omitempty"`
+	// MaxRPS limits requests per second sent to the producer across all workers when positive
+	MaxRPS float64 `yaml:"max_rps" json:"max_rps,omitempty"`
+	// Retry policy of executions that failed due to transient errors
+	Retry *RetryPolicy `yaml:"retry" json:"retry,omitempty"`
+	// HostOverrides maps hosts to IP, host or host:port that is dialed instead, keeping Host header and SNI
+	HostOverrides map[string]string `yaml:"host_overrides" json:"host_overrides,omitempty"`
 	// Headers overrides
 	Headers http.Header `yaml:"-" json:"-"`
 	// Params local overrides
@@ -54,6 +70,17 @@ func (req *ProducerContractRequest) Overrides() map[string]any {
 	return res
 }
 
+// Clone copies request with its own headers and params so that shared variables don't leak between workers
+func (req *ProducerContractRequest) Clone() *ProducerContractRequest {
+	clone := *req
+	clone.Headers = req.Headers.Clone()
+	clone.Params = make(map[string]any, len(req.Params))
+	for k, v := range req.Params {
+		clone.Params[k] = v
+	}
+	return &clone
+}
+
 func (req *ProducerContractRequest) String() string {
 	return "ProducerContractRequest(" + req.BaseURL + ")"
 }
@@ -1,5 +1,7 @@
 package types
 
+import "sync"
+
 // ProducerContractResponse for returning summary of producer based test results
 type ProducerContractResponse struct {
 	Results      map[string]any                       `yaml:"results" json:"results"`
@@ -7,10 +9,20 @@ type ProducerContractResponse struct {
 	ErrorDetails map[string]*ContractValidationDetail `json:"error_details,omitempty"`
 	Metrics      map[string]float64                   `yaml:"metrics" json:"metrics"`
 	URLs         map[string]int                       `yaml:"urls" json:"urls"`
+	Latencies    map[string]int64                     `yaml:"latencies" json:"latencies,omitempty"`
 	Succeeded    int                                  `yaml:"succeeded" json:"succeeded"`
 	Mismatched   int                                  `yaml:"mismatched" json:"mismatched"`
 	Failed       int                                  `yaml:"failed" json:"failed"`
+	Flaky        int                                  `yaml:"flaky" json:"flaky"`
+	FlakyErrors  map[string]string                    `yaml:"flaky_errors" json:"flaky_errors,omitempty"`
+	Attempts     map[string]int                       `yaml:"attempts" json:"attempts,omitempty"`
 	Coverage     *CoverageSummary                     `json:"coverage,omitempty"`
+	Load         *LoadReport                          `json:"load,omitempty"`
+	Model        *ModelReport                         `yaml:"model" json:"model,omitempty"`
+	Mutations    *MutationReport                      `yaml:"mutations" json:"mutations,omitempty"`
+	RunID        string                               `yaml:"run_id" json:"run_id,omitempty"`
+	Workflow     []*WorkflowStepResult                `yaml:"workflow" json:"workflow,omitempty"`
+	lock         sync.Mutex
 }
 
 // NewProducerContractResponse constructor
@@ -27,6 +39,8 @@ func NewProducerContractResponse() *ProducerContractResponse {
 
 // Add result or error
 func (cr *ProducerContractResponse) Add(key string, res any, err error) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
 	if err != nil {
 		cr.Errors[key] = err.Error()
 		cr.Failed++
@@ -40,8 +54,85 @@ func (cr *ProducerContractResponse) Add(key string, res any, err error) {
 
 // SetErrorDetail attaches field-level diagnostics for a failed scenario.
 func (cr *ProducerContractResponse) SetErrorDetail(key string, detail *ContractValidationDetail) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
 	if cr.ErrorDetails == nil {
 		cr.ErrorDetails = make(map[string]*ContractValidationDetail)
 	}
 	cr.ErrorDetails[key] = detail
 }
+
+// AddResult sets result without counting it as execution
+func (cr *ProducerContractResponse) AddResult(key string, res any) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+	if cr.Results == nil {
+		cr.Results = make(map[string]any)
+	}
+	cr.Results[key] = res
+}
+
+// AddURL counts invocation of the URL
+func (cr *ProducerContractResponse) AddURL(url string) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+	cr.URLs[url] = cr.URLs[url] + 1
+}
+
+// AddMismatched counts scenario that could not be looked up
+func (cr *ProducerContractResponse) AddMismatched() {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+	cr.Mismatched++
+}
+
+// AddLatency sets latency in millis of the execution
+func (cr *ProducerContractResponse) AddLatency(key string, millis int64) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+	if cr.Latencies == nil {
+		cr.Latencies = make(map[string]int64)
+	}
+	cr.Latencies[key] = millis
+}
+
+// AddFlaky sets result of execution that passed on retry after it failed with the error
+func (cr *ProducerContractResponse) AddFlaky(key string, res any, err error) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+	if res != nil {
+		cr.Results[key] = res
+	}
+	if cr.FlakyErrors == nil {
+		cr.FlakyErrors = make(map[string]string)
+	}
+	cr.FlakyErrors[key] = err.Error()
+	cr.Flaky++
+}
+
+// AddAttempts sets attempts of the execution
+func (cr *ProducerContractResponse) AddAttempts(key string, attempts int) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+	if cr.Attempts == nil {
+		cr.Attempts = make(map[string]int)
+	}
+	cr.Attempts[key] = attempts
+}
+
+// FlakyRate returns ratio of flaky executions to all executions
+func (cr *ProducerContractResponse) FlakyRate() float64 {
+	total := cr.Succeeded + cr.Failed + cr.Flaky
+	if total == 0 {
+		return 0
+	}
+	return float64(cr.Flaky) / float64(total)
+}
+
+// IsFlaky returns true if execution of the key passed on retry
+func (cr *ProducerContractResponse) IsFlaky(key string) bool {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+	_, ok := cr.FlakyErrors[key]
+	return ok
+}
@@ -0,0 +1,162 @@
+package types
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// RecordingSkipInclude is reason for skipping a recording that doesn't match any include rule
+const RecordingSkipInclude = "include"
+
+// RecordingSkipMaxBodySize is reason for skipping a recording whose body exceeds the max size
+const RecordingSkipMaxBodySize = "max_body_size"
+
+// RecordingFilterRule matches recorded traffic when all of its non-empty criteria match
+type RecordingFilterRule struct {
+	// Name of rule used for counting skipped requests
+	Name string `yaml:"name" mapstructure:"name" json:"name"`
+	// Methods to match such as GET, POST
+	Methods []string `yaml:"methods" mapstructure:"methods" json:"methods"`
+	// Paths to match with glob where * matches a path segment and ** matches any number of segments
+	Paths []string `yaml:"paths" mapstructure:"paths" json:"paths"`
+	// Hosts to match with glob such as *.example.com
+	Hosts []string `yaml:"hosts" mapstructure:"hosts" json:"hosts"`
+	// MinStatus of response status range (inclusive)
+	MinStatus int `yaml:"min_status" mapstructure:"min_status" json:"min_status"`
+	// MaxStatus of response status range (inclusive)
+	MaxStatus int `yaml:"max_status" mapstructure:"max_status" json:"max_status"`
+	// ContentTypes to match by prefix of response content type such as image/
+	ContentTypes []string `yaml:"content_types" mapstructure:"content_types" json:"content_types"`
+}
+
+// RecordingFilterConfig decides which proxied requests are recorded as scenarios
+type RecordingFilterConfig struct {
+	// Include rules; when defined only requests matching one of the rules are recorded
+	Include []*RecordingFilterRule `yaml:"include" mapstructure:"include" json:"include"`
+	// Exclude rules; requests matching any of the rules are not recorded
+	Exclude []*RecordingFilterRule `yaml:"exclude" mapstructure:"exclude" json:"exclude"`
+	// MaxBodySize of request or response body in bytes for recording (0 = unlimited)
+	MaxBodySize int `yaml:"max_body_size" mapstructure:"max_body_size" json:"max_body_size"`
+}
+
+// SkipReason returns name of the rule that prevents recording or empty string if request should be recorded
+func (fc *RecordingFilterConfig) SkipReason(
+	method string,
+	u *url.URL,
+	status int,
+	contentType string,
+	reqSize int,
+	resSize int,
+) string {
+	if fc == nil {
+		return ""
+	}
+	if fc.MaxBodySize > 0 && (reqSize > fc.MaxBodySize || resSize > fc.MaxBodySize) {
+		return RecordingSkipMaxBodySize
+	}
+	for i, rule := range fc.Exclude {
+		if rule.Matches(method, u, status, contentType) {
+			return rule.ruleName("exclude", i)
+		}
+	}
+	if len(fc.Include) == 0 {
+		return ""
+	}
+	for _, rule := range fc.Include {
+		if rule.Matches(method, u, status, contentType) {
+			return ""
+		}
+	}
+	return RecordingSkipInclude
+}
+
+// Matches checks if all defined criteria of the rule match the request and response
+func (r *RecordingFilterRule) Matches(method string, u *url.URL, status int, contentType string) bool {
+	if len(r.Methods) > 0 && !containsFold(r.Methods, method) {
+		return false
+	}
+	if len(r.Paths) > 0 && (u == nil || !matchesAnyGlob(r.Paths, u.Path)) {
+		return false
+	}
+	if len(r.Hosts) > 0 && (u == nil || !matchesAnyGlob(r.Hosts, u.Hostname())) {
+		return false
+	}
+	if r.MinStatus > 0 && status < r.MinStatus {
+		return false
+	}
+	if r.MaxStatus > 0 && status > r.MaxStatus {
+		return false
+	}
+	if len(r.ContentTypes) > 0 {
+		matched := false
+		for _, ct := range r.ContentTypes {
+			if strings.HasPrefix(strings.ToLower(contentType), strings.ToLower(ct)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *RecordingFilterRule) ruleName(kind string, i int) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return fmt.Sprintf("%s[%d]", kind, i)
+}
+
+func containsFold(arr []string, val string) bool {
+	for _, next := range arr {
+		if strings.EqualFold(next, val) {
+			return true
+		}
+	}
+	return false
+}
+
+// globRegexCache caches compiled glob patterns
+var globRegexCache sync.Map
+
+func matchesAnyGlob(globs []string, val string) bool {
+	for _, glob := range globs {
+		if globToRegex(glob).MatchString(val) {
+			return true
+		}
+	}
+	return false
+}
+
+// globToRegex converts glob to regex where ** matches anything, * matches anything except / and ? matches one character
+func globToRegex(glob string) *regexp.Regexp {
+	if re, ok := globRegexCache.Load(glob); ok {
+		return re.(*regexp.Regexp)
+	}
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch glob[i] {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				sb.WriteString(".*")
+				i++
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(glob[i])))
+		}
+	}
+	sb.WriteString("$")
+	re := regexp.MustCompile(sb.String())
+	globRegexCache.Store(glob, re)
+	return re
+}
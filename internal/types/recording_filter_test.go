@@ -0,0 +1,54 @@
+package types
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldSkipRecordingByExcludeRules(t *testing.T) {
+	// GIVEN recording filter with exclude rules
+	fc := &RecordingFilterConfig{
+		Exclude: []*RecordingFilterRule{
+			{Name: "assets", Paths: []string{"/assets/**", "/*.ico"}},
+			{Name: "health", Methods: []string{"get"}, Paths: []string{"/health"}},
+			{Name: "errors", MinStatus: 500, MaxStatus: 599},
+			{Name: "images", ContentTypes: []string{"image/"}},
+			{Hosts: []string{"*.internal.example.com"}},
+		},
+		MaxBodySize: 100,
+	}
+	u, _ := url.Parse("https://api.example.com/assets/js/app.js")
+	health, _ := url.Parse("https://api.example.com/health")
+	orders, _ := url.Parse("https://api.example.com/orders/1")
+	internal, _ := url.Parse("https://auth.internal.example.com/orders/1")
+	favicon, _ := url.Parse("https://api.example.com/favicon.ico")
+	// WHEN checking skip reason
+	// THEN it should return name of matching rule
+	require.Equal(t, "assets", fc.SkipReason("GET", u, 200, "text/javascript", 0, 10))
+	require.Equal(t, "assets", fc.SkipReason("GET", favicon, 200, "", 0, 10))
+	require.Equal(t, "health", fc.SkipReason("GET", health, 200, "application/json", 0, 10))
+	require.Equal(t, "", fc.SkipReason("POST", health, 200, "application/json", 0, 10))
+	require.Equal(t, "errors", fc.SkipReason("GET", orders, 503, "application/json", 0, 10))
+	require.Equal(t, "images", fc.SkipReason("GET", orders, 200, "Image/PNG", 0, 10))
+	require.Equal(t, "exclude[4]", fc.SkipReason("GET", internal, 200, "application/json", 0, 10))
+	require.Equal(t, RecordingSkipMaxBodySize, fc.SkipReason("GET", orders, 200, "application/json", 0, 101))
+	require.Equal(t, "", fc.SkipReason("GET", orders, 200, "application/json", 0, 100))
+}
+
+func Test_ShouldSkipRecordingWithoutMatchingIncludeRules(t *testing.T) {
+	// GIVEN recording filter with include rules
+	fc := &RecordingFilterConfig{
+		Include: []*RecordingFilterRule{
+			{Hosts: []string{"api.example.com"}, Paths: []string{"/v1/**"}},
+		},
+	}
+	matched, _ := url.Parse("https://api.example.com/v1/orders/1")
+	other, _ := url.Parse("https://cdn.example.com/v1/orders/1")
+	// WHEN checking skip reason
+	// THEN it should only record matching requests
+	require.Equal(t, "", fc.SkipReason("GET", matched, 200, "", 0, 0))
+	require.Equal(t, RecordingSkipInclude, fc.SkipReason("GET", other, 200, "", 0, 0))
+	require.Equal(t, "", (&RecordingFilterConfig{}).SkipReason("GET", other, 200, "", 0, 0))
+}
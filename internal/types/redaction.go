@@ -0,0 +1,389 @@
+package types
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bhatti/api-mock-service/internal/fuzz"
+	"github.com/bhatti/api-mock-service/internal/jsonpath"
+	log "github.com/sirupsen/logrus"
+)
+
+// RedactionAction defines how a matched secret is redacted
+type RedactionAction string
+
+const (
+	// RedactMask replaces matched value with a mask
+	RedactMask RedactionAction = "mask"
+	// RedactHash replaces matched value with a stable hash so that recordings can still be correlated
+	RedactHash RedactionAction = "hash"
+	// RedactFuzzType replaces matched value with a fuzz type tag so that playback generates random data
+	RedactFuzzType RedactionAction = "type"
+)
+
+// RedactionDetector defines built-in detectors for PII
+type RedactionDetector string
+
+const (
+	// CreditCardDetector detects card numbers of 13-19 digits with a known issuer prefix and Luhn checksum
+	CreditCardDetector RedactionDetector = "credit_card"
+	// SSNDetector detects social security numbers
+	SSNDetector RedactionDetector = "ssn"
+	// EmailDetector detects email addresses
+	EmailDetector RedactionDetector = "email"
+)
+
+// RedactedMask is used for masking values
+const RedactedMask = "****"
+
+// RedactedHashPrefix is prefix for hashed values
+const RedactedHashPrefix = "sha256:"
+
+var redactionDetectors = map[RedactionDetector]struct {
+	regex    *regexp.Regexp
+	valid    func(string) bool
+	bounded  func(text string, start int, end int) bool
+	typeTags string
+}{
+	CreditCardDetector: {
+		regex:    regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		valid:    isCreditCardNumber,
+		bounded:  isDigitRunBounded,
+		typeTags: fuzz.PrefixTypeString + `\d{16}`,
+	},
+	SSNDetector: {
+		regex:    regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+		valid:    fuzz.IsValidSSN,
+		bounded:  isDigitRunBounded,
+		typeTags: fuzz.PrefixTypeString + `\d{3}-\d{2}-\d{4}`,
+	},
+	EmailDetector: {
+		regex:    regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+		valid:    func(string) bool { return true },
+		bounded:  func(string, int, int) bool { return true },
+		typeTags: fuzz.PrefixTypeString + fuzz.EmailRegex3,
+	},
+}
+
+// RedactionRule defines secrets that are redacted before scenarios and history are persisted
+type RedactionRule struct {
+	// Name of rule for logging
+	Name string `yaml:"name" mapstructure:"name" json:"name"`
+	// Headers to redact by name (case-insensitive)
+	Headers []string `yaml:"headers" mapstructure:"headers" json:"headers"`
+	// JSONPaths of body fields to redact such as $.user.email or $.cards[*].number
+	JSONPaths []string `yaml:"json_paths" mapstructure:"json_paths" json:"json_paths"`
+	// Regex to redact in header values, query params and bodies
+	Regex string `yaml:"regex" mapstructure:"regex" json:"regex"`
+	// Detector for built-in detection of credit_card, ssn or email
+	Detector RedactionDetector `yaml:"detector" mapstructure:"detector" json:"detector"`
+	// Action to mask, hash or replace with fuzz type tag (default mask)
+	Action RedactionAction `yaml:"action" mapstructure:"action" json:"action"`
+}
+
+// RedactionConfig for redacting secrets and PII in recorded scenarios and history
+type RedactionConfig struct {
+	// Rules for redaction
+	Rules []*RedactionRule `yaml:"rules" mapstructure:"rules" json:"rules"`
+}
+
+// Enabled returns true if any rules are defined
+func (rc *RedactionConfig) Enabled() bool {
+	return rc != nil && len(rc.Rules) > 0
+}
+
+// RedactHeaders returns copy of headers after redacting headers by name, regex and detectors
+func (rc *RedactionConfig) RedactHeaders(headers http.Header) http.Header {
+	if headers == nil || !rc.Enabled() {
+		return headers
+	}
+	res := make(http.Header, len(headers))
+	for k, vals := range headers {
+		res[k] = make([]string, len(vals))
+		for i, val := range vals {
+			res[k][i] = rc.redactHeader(k, val)
+		}
+	}
+	return res
+}
+
+// RedactParams returns copy of query or post params after redacting values by regex and detectors
+func (rc *RedactionConfig) RedactParams(params map[string][]string) map[string][]string {
+	if params == nil || !rc.Enabled() {
+		return params
+	}
+	res := make(map[string][]string, len(params))
+	for k, vals := range params {
+		res[k] = make([]string, len(vals))
+		for i, val := range vals {
+			res[k][i] = rc.redactText(val)
+		}
+	}
+	return res
+}
+
+// RedactBody redacts JSON fields by path and all values by regex and detectors
+func (rc *RedactionConfig) RedactBody(body []byte) []byte {
+	if len(body) == 0 || !rc.Enabled() {
+		return body
+	}
+	trimmed := strings.TrimSpace(string(body))
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		// numbers are decoded as json.Number so that large integers keep their precision
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		var data any
+		if err := decoder.Decode(&data); err == nil {
+			changed := false
+			data = rc.redactJSON(data, &changed)
+			if !changed {
+				// body is kept as recorded unless a rule redacted something
+				return body
+			}
+			var buf bytes.Buffer
+			encoder := json.NewEncoder(&buf)
+			encoder.SetEscapeHTML(false)
+			if err := encoder.Encode(data); err == nil {
+				return bytes.TrimRight(buf.Bytes(), "\n")
+			}
+		}
+	}
+	return []byte(rc.redactText(string(body)))
+}
+
+// RedactScenario redacts request and response of the scenario
+func (rc *RedactionConfig) RedactScenario(scenario *APIScenario) {
+	if scenario == nil || !rc.Enabled() {
+		return
+	}
+	for k, v := range scenario.Request.Headers {
+		scenario.Request.Headers[k] = rc.redactHeader(k, v)
+	}
+	for k, v := range scenario.Request.QueryParams {
+		scenario.Request.QueryParams[k] = rc.redactText(v)
+	}
+	for k, v := range scenario.Request.PostParams {
+		scenario.Request.PostParams[k] = rc.redactText(v)
+	}
+	scenario.Request.Contents = rc.redactContents(scenario.Request.Contents)
+	scenario.Request.ExampleContents = rc.redactContents(scenario.Request.ExampleContents)
+	scenario.Response.Headers = rc.RedactHeaders(scenario.Response.Headers)
+	scenario.Response.Contents = rc.redactContents(scenario.Response.Contents)
+	scenario.Response.ExampleContents = rc.redactContents(scenario.Response.ExampleContents)
+}
+
+// redactionRegexCache caches compiled regex of rules by pattern
+var redactionRegexCache sync.Map
+
+func (rule *RedactionRule) compiledRegex() *regexp.Regexp {
+	if rule.Regex == "" {
+		return nil
+	}
+	if re, ok := redactionRegexCache.Load(rule.Regex); ok {
+		return re.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile(rule.Regex)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Component": "RedactionRule",
+			"Rule":      rule.Name,
+			"Regex":     rule.Regex,
+			"Error":     err,
+		}).Warnf("failed to compile redaction regex")
+		return nil
+	}
+	redactionRegexCache.Store(rule.Regex, re)
+	return re
+}
+
+func (rc *RedactionConfig) redactContents(contents string) string {
+	if contents == "" {
+		return contents
+	}
+	return string(rc.RedactBody([]byte(contents)))
+}
+
+func (rc *RedactionConfig) redactHeader(name string, val string) string {
+	for _, rule := range rc.Rules {
+		for _, header := range rule.Headers {
+			if strings.EqualFold(header, name) {
+				return rule.redactValue(val, "")
+			}
+		}
+	}
+	return rc.redactText(val)
+}
+
+// redactText applies regex and detector rules to all matching substrings
+func (rc *RedactionConfig) redactText(text string) string {
+	for _, rule := range rc.Rules {
+		if re := rule.compiledRegex(); re != nil {
+			text = re.ReplaceAllStringFunc(text, func(matched string) string {
+				return rule.redactValue(matched, "")
+			})
+		}
+		if detector, ok := redactionDetectors[rule.Detector]; ok {
+			var sb strings.Builder
+			last := 0
+			for _, loc := range detector.regex.FindAllStringIndex(text, -1) {
+				matched := text[loc[0]:loc[1]]
+				if !detector.bounded(text, loc[0], loc[1]) || !detector.valid(matched) {
+					continue
+				}
+				sb.WriteString(text[last:loc[0]])
+				sb.WriteString(rule.redactValue(matched, detector.typeTags))
+				last = loc[1]
+			}
+			if last > 0 {
+				sb.WriteString(text[last:])
+				text = sb.String()
+			}
+		}
+	}
+	return text
+}
+
+// isCreditCardNumber checks separators, issuer prefix and Luhn checksum of the card number
+func isCreditCardNumber(val string) bool {
+	if strings.Contains(val, " ") && strings.Contains(val, "-") {
+		return false
+	}
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(val)
+	if len(digits) < 13 || len(digits) > 19 || !hasCardIssuerPrefix(digits) {
+		return false
+	}
+	return fuzz.IsValidCreditCard(digits)
+}
+
+// hasCardIssuerPrefix checks issuer identification number of Visa, Mastercard, Amex, Discover,
+// Diners, JCB and UnionPay cards
+func hasCardIssuerPrefix(digits string) bool {
+	prefix := func(n int) int {
+		val, _ := strconv.Atoi(digits[0:n])
+		return val
+	}
+	switch {
+	case digits[0] == '4':
+		return true
+	case prefix(2) >= 51 && prefix(2) <= 55, prefix(4) >= 2221 && prefix(4) <= 2720:
+		return true
+	case prefix(2) == 34, prefix(2) == 37, prefix(2) == 36, prefix(2) == 38, prefix(2) == 39:
+		return true
+	case prefix(3) >= 300 && prefix(3) <= 305:
+		return true
+	case prefix(4) == 6011, prefix(2) == 65, prefix(3) >= 644 && prefix(3) <= 649, prefix(2) == 62:
+		return true
+	case prefix(4) >= 3528 && prefix(4) <= 3589:
+		return true
+	}
+	return false
+}
+
+// isDigitRunBounded rejects matches that are part of a longer run of digits, dashes or dots
+// such as IDs, timestamps and version numbers
+func isDigitRunBounded(text string, start int, end int) bool {
+	isDigit := func(b byte) bool { return b >= '0' && b <= '9' }
+	if start > 0 {
+		prev := text[start-1]
+		if isDigit(prev) || prev == '-' || prev == '.' {
+			return false
+		}
+	}
+	if end < len(text) {
+		next := text[end]
+		if isDigit(next) || next == '-' {
+			return false
+		}
+		if next == '.' && end+1 < len(text) && isDigit(text[end+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// redactJSON redacts json paths and values and sets changed if any value was redacted
+func (rc *RedactionConfig) redactJSON(data any, changed *bool) any {
+	for _, rule := range rc.Rules {
+		for _, path := range rule.JSONPaths {
+			for _, leaf := range jsonpath.Expand(data, jsonpath.Parse(path)) {
+				if len(leaf) == 0 {
+					continue
+				}
+				if redacted, ok := rule.redactJSONLeaf(jsonpath.ValueAt(data, leaf), changed); ok {
+					jsonpath.SetValueAt(data, leaf, redacted)
+				}
+			}
+		}
+	}
+	return rc.redactJSONValues(data, changed)
+}
+
+func (rc *RedactionConfig) redactJSONValues(data any, changed *bool) any {
+	switch val := data.(type) {
+	case map[string]any:
+		for k, v := range val {
+			val[k] = rc.redactJSONValues(v, changed)
+		}
+	case []any:
+		for i, v := range val {
+			val[i] = rc.redactJSONValues(v, changed)
+		}
+	case string:
+		redacted := rc.redactText(val)
+		if redacted != val {
+			*changed = true
+		}
+		return redacted
+	}
+	return data
+}
+
+// redactJSONLeaf returns redacted value of a scalar and false if it's not changed
+func (rule *RedactionRule) redactJSONLeaf(data any, changed *bool) (any, bool) {
+	switch data.(type) {
+	case nil, map[string]any, []any:
+		return data, false
+	}
+	val := fmt.Sprintf("%v", data)
+	redacted := rule.redactValue(val, "")
+	if redacted == val {
+		return data, false
+	}
+	*changed = true
+	return redacted, true
+}
+
+// redactValue redacts the value based on the action of the rule
+func (rule *RedactionRule) redactValue(val string, typeTags string) string {
+	if val == "" || isRedacted(val) {
+		return val
+	}
+	switch rule.Action {
+	case RedactHash:
+		sum := sha256.Sum256([]byte(val))
+		return RedactedHashPrefix + hex.EncodeToString(sum[:])[0:16]
+	case RedactFuzzType:
+		if typeTags != "" {
+			return typeTags
+		}
+		return fuzz.PrefixTypeStringToRegEx(val, fuzz.NewDataTemplateRequest(false, 1, 1))
+	default:
+		return RedactedMask
+	}
+}
+
+// isRedacted checks if value was already redacted so that redaction is idempotent
+func isRedacted(val string) bool {
+	val = strings.TrimPrefix(val, fuzz.PrefixTypeExample)
+	return val == RedactedMask ||
+		strings.HasPrefix(val, RedactedHashPrefix) ||
+		strings.HasPrefix(val, fuzz.PrefixTypeString)
+}
@@ -0,0 +1,147 @@
+package types
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bhatti/api-mock-service/internal/fuzz"
+	"github.com/stretchr/testify/require"
+)
+
+func buildTestRedactionConfig() *RedactionConfig {
+	return &RedactionConfig{
+		Rules: []*RedactionRule{
+			{Name: "auth", Headers: []string{"Authorization"}},
+			{Name: "token", JSONPaths: []string{"$.token", "$.cards[*].cvv"}, Action: RedactHash},
+			{Name: "apikey", Regex: `sk_live_[A-Za-z0-9]+`},
+			{Name: "card", Detector: CreditCardDetector, Action: RedactFuzzType},
+			{Name: "ssn", Detector: SSNDetector},
+			{Name: "email", Detector: EmailDetector, Action: RedactFuzzType},
+		},
+	}
+}
+
+func Test_ShouldRedactHeaders(t *testing.T) {
+	// GIVEN redaction config
+	rc := buildTestRedactionConfig()
+	headers := http.Header{
+		"Authorization": []string{"Bearer abc"},
+		"X-Key":         []string{"key sk_live_abc123"},
+		"Accept":        []string{"application/json"},
+	}
+	// WHEN redacting headers
+	res := rc.RedactHeaders(headers)
+	// THEN it should redact by name and regex without changing original headers
+	require.Equal(t, RedactedMask, res.Get("Authorization"))
+	require.Equal(t, "key "+RedactedMask, res.Get("X-Key"))
+	require.Equal(t, "application/json", res.Get("Accept"))
+	require.Equal(t, "Bearer abc", headers.Get("Authorization"))
+}
+
+func Test_ShouldRedactJSONBody(t *testing.T) {
+	// GIVEN redaction config
+	rc := buildTestRedactionConfig()
+	body := []byte(`{"token":"secret","email":"jane@example.com","ssn":"123-45-6789",` +
+		`"cards":[{"number":"4111111111111111","cvv":123}],"name":"jane"}`)
+	// WHEN redacting body
+	res := string(rc.RedactBody(body))
+	// THEN it should redact json paths and detected values
+	require.NotContains(t, res, "secret")
+	require.NotContains(t, res, "jane@example.com")
+	require.NotContains(t, res, "123-45-6789")
+	require.NotContains(t, res, "4111111111111111")
+	require.NotContains(t, res, `"cvv":123`)
+	require.Contains(t, res, `"token":"`+RedactedHashPrefix)
+	require.Contains(t, res, `"ssn":"`+RedactedMask+`"`)
+	require.Contains(t, res, fuzz.PrefixTypeString)
+	require.Contains(t, res, `"name":"jane"`)
+	// AND redaction should be idempotent
+	require.Equal(t, res, string(rc.RedactBody([]byte(res))))
+}
+
+func Test_ShouldRedactTextBodyAndParams(t *testing.T) {
+	// GIVEN redaction config
+	rc := buildTestRedactionConfig()
+	// WHEN redacting text body and params
+	body := string(rc.RedactBody([]byte("card=4111 1111 1111 1111&id=42")))
+	params := rc.RedactParams(map[string][]string{"key": {"sk_live_xyz"}, "id": {"42"}})
+	// THEN it should redact detected values
+	require.False(t, strings.Contains(body, "4111"))
+	require.Contains(t, body, "id=42")
+	require.Equal(t, RedactedMask, params["key"][0])
+	require.Equal(t, "42", params["id"][0])
+}
+
+func Test_ShouldRedactScenario(t *testing.T) {
+	// GIVEN a scenario with secrets
+	rc := buildTestRedactionConfig()
+	scenario := &APIScenario{
+		Request: APIRequest{
+			Headers:     map[string]string{"Authorization": "Bearer abc"},
+			QueryParams: map[string]string{"email": "jane@example.com"},
+			Contents:    `{"token":"secret"}`,
+		},
+		Response: APIResponse{
+			Headers:  map[string][]string{"Set-Cookie": {"k=sk_live_abc"}},
+			Contents: `{"ssn":"123-45-6789"}`,
+		},
+	}
+	// WHEN redacting scenario
+	rc.RedactScenario(scenario)
+	// THEN it should redact request and response
+	require.Equal(t, RedactedMask, scenario.Request.Headers["Authorization"])
+	require.Equal(t, fuzz.PrefixTypeString+fuzz.EmailRegex3, scenario.Request.QueryParams["email"])
+	require.NotContains(t, scenario.Request.Contents, "secret")
+	require.Equal(t, "k="+RedactedMask, scenario.Response.Headers["Set-Cookie"][0])
+	require.NotContains(t, scenario.Response.Contents, "123-45-6789")
+}
+
+func Test_ShouldNotRedactWithoutRules(t *testing.T) {
+	// GIVEN empty redaction config
+	rc := &RedactionConfig{}
+	body := []byte(`{"email":"jane@example.com"}`)
+	// WHEN redacting body
+	// THEN it should not change body
+	require.Equal(t, body, rc.RedactBody(body))
+}
+
+func Test_ShouldNotRewriteJSONBodyWithoutRedaction(t *testing.T) {
+	// GIVEN redaction config and a body with large integer, html characters and key order
+	rc := buildTestRedactionConfig()
+	body := []byte(`{"z": 12345678901234567890, "a": "<b>&</b>"}`)
+	// WHEN redacting body that has no secrets
+	// THEN it should be kept as is
+	require.Equal(t, string(body), string(rc.RedactBody(body)))
+}
+
+func Test_ShouldKeepLargeIntegersAndHTMLWhenRedactingJSONBody(t *testing.T) {
+	// GIVEN redaction config and a body with a secret, large integer and html characters
+	rc := buildTestRedactionConfig()
+	body := []byte(`{"id": 12345678901234567890, "html": "<b>a & b</b>", "token": "secret"}`)
+	// WHEN redacting body
+	res := string(rc.RedactBody(body))
+	// THEN it should redact token without losing precision or escaping html
+	require.NotContains(t, res, "secret")
+	require.Contains(t, res, `"id":12345678901234567890`)
+	require.Contains(t, res, `"html":"<b>a & b</b>"`)
+}
+
+func Test_ShouldNotRedactNumbersThatAreNotCardNumbers(t *testing.T) {
+	// GIVEN card detector
+	rc := &RedactionConfig{Rules: []*RedactionRule{{Name: "card", Detector: CreditCardDetector}}}
+	// WHEN redacting IDs and timestamps that pass Luhn but are not card numbers
+	for _, text := range []string{
+		"order 1000000000009",                // no issuer prefix
+		"id=41111111111111112222",            // part of a longer run of digits
+		"trace 2024-4111111111111111",        // part of a dashed identifier
+		"version 1.4111111111111111",         // part of a dotted number
+		"card 4111-1111 1111-1111 for order", // mixed separators
+	} {
+		// THEN text should be kept
+		require.Equal(t, text, string(rc.RedactBody([]byte(text))))
+	}
+	// AND card numbers should still be redacted
+	require.Equal(t, "card "+RedactedMask+".", string(rc.RedactBody([]byte("card 5555 5555 5555 4444."))))
+	require.Equal(t, "amex="+RedactedMask, string(rc.RedactBody([]byte("amex=378282246310005"))))
+}
@@ -0,0 +1,36 @@
+package types
+
+// ResponseVariant is a representation of the response for a media type
+type ResponseVariant struct {
+	// ContentType of the variant such as application/xml or text/csv
+	ContentType string `yaml:"content_type" json:"content_type"`
+	// Contents of the variant
+	Contents string `yaml:"contents,omitempty" json:"contents,omitempty"`
+	// ContentsFile of the variant
+	ContentsFile string `yaml:"contents_file,omitempty" json:"contents_file,omitempty"`
+}
+
+// ContentTypes returns media types of the response followed by its variants
+func (r APIResponse) ContentTypes() (contentTypes []string) {
+	contentTypes = append(contentTypes, r.ContentType("application/json"))
+	for _, variant := range r.Variants {
+		contentTypes = append(contentTypes, variant.ContentType)
+	}
+	return
+}
+
+// NegotiateVariant selects representation by q-values of accept header. It returns nil variant when
+// default contents of the response is selected and false when nothing is acceptable.
+func (r APIResponse) NegotiateVariant(accept string) (*ResponseVariant, bool) {
+	contentTypes := r.ContentTypes()
+	contentType, ok := NegotiateContentType(accept, contentTypes)
+	if !ok {
+		return nil, false
+	}
+	for i := 1; i < len(contentTypes); i++ {
+		if contentTypes[i] == contentType && contentTypes[0] != contentType {
+			return &r.Variants[i-1], true
+		}
+	}
+	return nil, true
+}
@@ -0,0 +1,83 @@
+package types
+
+import (
+	"strings"
+	"time"
+)
+
+// DefaultRetryStatusCodes are retried when retry policy doesn't specify status codes
+var DefaultRetryStatusCodes = []int{502, 503, 504}
+
+// RetryPolicy of producer contract executions
+type RetryPolicy struct {
+	// MaxAttempts of an execution including the first attempt
+	MaxAttempts int `yaml:"max_attempts" json:"max_attempts"`
+	// BackoffMillis before the first retry
+	BackoffMillis int `yaml:"backoff_millis" json:"backoff_millis,omitempty"`
+	// BackoffMultiplier grows backoff between retries (default 2)
+	BackoffMultiplier float64 `yaml:"backoff_multiplier" json:"backoff_multiplier,omitempty"`
+	// MaxBackoffMillis caps backoff between retries when positive
+	MaxBackoffMillis int `yaml:"max_backoff_millis" json:"max_backoff_millis,omitempty"`
+	// RetryOnStatus are status codes of responses that are retried (default 502, 503, 504)
+	RetryOnStatus []int `yaml:"retry_on_status" json:"retry_on_status,omitempty"`
+	// RetryOnErrors are substrings of error messages that are retried; if empty, requests that failed without
+	// a response such as timeouts and refused connections are retried
+	RetryOnErrors []string `yaml:"retry_on_errors" json:"retry_on_errors,omitempty"`
+}
+
+// Attempts returns max attempts of an execution
+func (p *RetryPolicy) Attempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// Backoff returns wait before the retry after given attempt starting from 1
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	if p == nil || p.BackoffMillis <= 0 {
+		return 0
+	}
+	multiplier := p.BackoffMultiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	millis := float64(p.BackoffMillis)
+	for i := 1; i < attempt; i++ {
+		millis *= multiplier
+		if p.MaxBackoffMillis > 0 && millis >= float64(p.MaxBackoffMillis) {
+			break
+		}
+	}
+	if p.MaxBackoffMillis > 0 && millis > float64(p.MaxBackoffMillis) {
+		millis = float64(p.MaxBackoffMillis)
+	}
+	return time.Duration(millis) * time.Millisecond
+}
+
+// Retryable returns true if execution that failed with status (0 without response) and error is retried
+func (p *RetryPolicy) Retryable(status int, transportFailed bool, err error) bool {
+	if p == nil || err == nil {
+		return false
+	}
+	if status > 0 {
+		codes := p.RetryOnStatus
+		if len(codes) == 0 {
+			codes = DefaultRetryStatusCodes
+		}
+		for _, code := range codes {
+			if code == status {
+				return true
+			}
+		}
+	}
+	if len(p.RetryOnErrors) == 0 {
+		return transportFailed
+	}
+	for _, substr := range p.RetryOnErrors {
+		if substr != "" && strings.Contains(err.Error(), substr) {
+			return true
+		}
+	}
+	return false
+}
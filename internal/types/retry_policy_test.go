@@ -0,0 +1,45 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldComputeRetryAttemptsAndBackoff(t *testing.T) {
+	// GIVEN no retry policy
+	var policy *RetryPolicy
+	// THEN execution should be attempted once without backoff
+	require.Equal(t, 1, policy.Attempts())
+	require.Equal(t, time.Duration(0), policy.Backoff(1))
+
+	// GIVEN a retry policy with exponential backoff
+	policy = &RetryPolicy{MaxAttempts: 4, BackoffMillis: 100, MaxBackoffMillis: 300}
+	// THEN backoff should double up to max backoff
+	require.Equal(t, 4, policy.Attempts())
+	require.Equal(t, 100*time.Millisecond, policy.Backoff(1))
+	require.Equal(t, 200*time.Millisecond, policy.Backoff(2))
+	require.Equal(t, 300*time.Millisecond, policy.Backoff(3))
+}
+
+func Test_ShouldMatchRetryableStatusAndErrors(t *testing.T) {
+	// GIVEN a retry policy with default status codes
+	policy := &RetryPolicy{MaxAttempts: 2}
+	err := fmt.Errorf("failed")
+	// THEN gateway errors and transport failures should be retried
+	require.True(t, policy.Retryable(502, false, err))
+	require.True(t, policy.Retryable(0, true, err))
+	require.False(t, policy.Retryable(500, false, err))
+	require.False(t, policy.Retryable(0, false, err))
+	require.False(t, policy.Retryable(502, false, nil))
+
+	// GIVEN a retry policy with status codes and error messages
+	policy = &RetryPolicy{MaxAttempts: 2, RetryOnStatus: []int{429}, RetryOnErrors: []string{"timeout"}}
+	// THEN only matching status codes and errors should be retried
+	require.True(t, policy.Retryable(429, false, err))
+	require.False(t, policy.Retryable(502, false, err))
+	require.False(t, policy.Retryable(0, true, err))
+	require.True(t, policy.Retryable(0, true, fmt.Errorf("i/o timeout")))
+}
@@ -0,0 +1,487 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bhatti/api-mock-service/internal/jsonpath"
+	log "github.com/sirupsen/logrus"
+)
+
+// RewritePhase defines whether rewrite rule applies to request or response
+type RewritePhase string
+
+const (
+	// RewriteRequestPhase rewrites request before it's forwarded, recorded or matched for playback
+	RewriteRequestPhase RewritePhase = "request"
+	// RewriteResponsePhase rewrites response before it's recorded or returned
+	RewriteResponsePhase RewritePhase = "response"
+)
+
+// JSONPatchOperation of RFC 6902 supporting add, remove, replace, move, copy and test operations
+type JSONPatchOperation struct {
+	Op    string `yaml:"op" mapstructure:"op" json:"op"`
+	Path  string `yaml:"path" mapstructure:"path" json:"path"`
+	From  string `yaml:"from,omitempty" mapstructure:"from" json:"from,omitempty"`
+	Value any    `yaml:"value" mapstructure:"value" json:"value"`
+}
+
+// jsonPatchOps are operations of RFC 6902
+var jsonPatchOps = map[string]bool{"add": true, "remove": true, "replace": true, "move": true, "copy": true, "test": true}
+
+// Validate checks op and paths of the operation
+func (op *JSONPatchOperation) Validate() error {
+	if !jsonPatchOps[op.Op] {
+		return fmt.Errorf("unsupported json patch op '%s' for path %s", op.Op, op.Path)
+	}
+	if op.Path != "" && !strings.HasPrefix(op.Path, "/") {
+		return fmt.Errorf("json patch path %s is not a json pointer", op.Path)
+	}
+	if op.Op == "move" || op.Op == "copy" {
+		if op.From != "" && !strings.HasPrefix(op.From, "/") {
+			return fmt.Errorf("json patch from %s is not a json pointer", op.From)
+		}
+		if op.Op == "move" && strings.HasPrefix(op.Path, op.From+"/") {
+			return fmt.Errorf("json patch cannot move %s into its child %s", op.From, op.Path)
+		}
+	}
+	return nil
+}
+
+// RewriteRule changes traffic in flight; actions are applied in the order of headers, url, query and body
+type RewriteRule struct {
+	// Name of rule for logging
+	Name string `yaml:"name" mapstructure:"name" json:"name"`
+	// Phase of request or response (default request)
+	Phase RewritePhase `yaml:"phase" mapstructure:"phase" json:"phase"`
+	// Match regex of request url to restrict the rule
+	Match string `yaml:"match" mapstructure:"match" json:"match"`
+	// SetHeaders adds or replaces headers
+	SetHeaders map[string]string `yaml:"set_headers" mapstructure:"set_headers" json:"set_headers"`
+	// RemoveHeaders deletes headers by name
+	RemoveHeaders []string `yaml:"remove_headers" mapstructure:"remove_headers" json:"remove_headers"`
+	// URLPattern regex of request url that is replaced with URLReplacement such as https://staging.$1 (request only)
+	URLPattern string `yaml:"url_pattern" mapstructure:"url_pattern" json:"url_pattern"`
+	// URLReplacement for URLPattern with regex captures
+	URLReplacement string `yaml:"url_replacement" mapstructure:"url_replacement" json:"url_replacement"`
+	// SetQuery adds or replaces query params (request only)
+	SetQuery map[string]string `yaml:"set_query" mapstructure:"set_query" json:"set_query"`
+	// RemoveQuery deletes query params by name or glob such as utm_* (request only)
+	RemoveQuery []string `yaml:"remove_query" mapstructure:"remove_query" json:"remove_query"`
+	// SetJSONPaths sets fields of JSON body such as $.user.role or $.items[0].price in sorted order of paths
+	// so that parent paths such as $.user are set before nested paths such as $.user.role
+	SetJSONPaths map[string]any `yaml:"set_json_paths" mapstructure:"set_json_paths" json:"set_json_paths"`
+	// JSONPatch operations applied to JSON body
+	JSONPatch []*JSONPatchOperation `yaml:"json_patch" mapstructure:"json_patch" json:"json_patch"`
+}
+
+// Validate checks json patch operations of the rule
+func (rule *RewriteRule) Validate() error {
+	for _, op := range rule.JSONPatch {
+		if err := op.Validate(); err != nil {
+			return fmt.Errorf("invalid rewrite rule %s: %w", rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// RequestGroup returns group of request from X-Mock-Group header or its path
+func RequestGroup(req *http.Request) string {
+	if group := req.Header.Get(MockGroup); group != "" {
+		return group
+	}
+	return NormalizeGroup("", req.URL.Path)
+}
+
+// RewriteRequest applies request rules of the group in order and returns rewritten body; callers replace
+// the request body with the returned body using a seekable reader so that it can be re-read for recording
+func (gc *GroupConfig) RewriteRequest(req *http.Request, body []byte) []byte {
+	if gc == nil || len(gc.RewriteRules) == 0 {
+		return body
+	}
+	changed := false
+	for _, rule := range gc.RewriteRules {
+		if rule.Phase == RewriteResponsePhase || !rule.matches(req.URL) {
+			continue
+		}
+		rule.rewriteHeaders(req.Header)
+		rule.rewriteURL(req)
+		rule.rewriteQuery(req.URL)
+		if b, ok := rule.rewriteBody(body); ok {
+			body = b
+			changed = true
+		}
+	}
+	if changed {
+		req.ContentLength = int64(len(body))
+		if req.Header.Get(ContentLengthHeader) != "" {
+			req.Header.Set(ContentLengthHeader, strconv.Itoa(len(body)))
+		}
+	}
+	return body
+}
+
+// RewriteResponse applies response rules of the group in order and returns rewritten body
+func (gc *GroupConfig) RewriteResponse(u *url.URL, headers http.Header, body []byte) []byte {
+	if gc == nil || len(gc.RewriteRules) == 0 {
+		return body
+	}
+	changed := false
+	for _, rule := range gc.RewriteRules {
+		if rule.Phase != RewriteResponsePhase || !rule.matches(u) {
+			continue
+		}
+		rule.rewriteHeaders(headers)
+		if b, ok := rule.rewriteBody(body); ok {
+			body = b
+			changed = true
+		}
+	}
+	if changed && headers.Get(ContentLengthHeader) != "" {
+		headers.Set(ContentLengthHeader, strconv.Itoa(len(body)))
+	}
+	return body
+}
+
+func (rule *RewriteRule) matches(u *url.URL) bool {
+	if rule.Match == "" {
+		return true
+	}
+	re := rule.compile(rule.Match)
+	return re != nil && u != nil && re.MatchString(u.String())
+}
+
+func (rule *RewriteRule) rewriteHeaders(headers http.Header) {
+	if headers == nil {
+		return
+	}
+	for _, name := range rule.RemoveHeaders {
+		headers.Del(name)
+	}
+	for name, val := range rule.SetHeaders {
+		headers.Set(name, val)
+	}
+}
+
+func (rule *RewriteRule) rewriteURL(req *http.Request) {
+	if rule.URLPattern == "" {
+		return
+	}
+	re := rule.compile(rule.URLPattern)
+	if re == nil || !re.MatchString(req.URL.String()) {
+		return
+	}
+	u, err := url.Parse(re.ReplaceAllString(req.URL.String(), rule.URLReplacement))
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Component": "RewriteRule",
+			"Rule":      rule.Name,
+			"URL":       req.URL,
+			"Error":     err,
+		}).Warnf("failed to rewrite url")
+		return
+	}
+	req.URL = u
+	req.Host = u.Host
+}
+
+func (rule *RewriteRule) rewriteQuery(u *url.URL) {
+	if len(rule.SetQuery) == 0 && len(rule.RemoveQuery) == 0 {
+		return
+	}
+	query := u.Query()
+	for name := range query {
+		if matchesAnyGlob(rule.RemoveQuery, name) {
+			query.Del(name)
+		}
+	}
+	for name, val := range rule.SetQuery {
+		query.Set(name, val)
+	}
+	u.RawQuery = query.Encode()
+}
+
+// rewriteBody sets JSON paths and applies JSON patch, returning false if body is not JSON or unchanged
+func (rule *RewriteRule) rewriteBody(body []byte) ([]byte, bool) {
+	if len(rule.SetJSONPaths) == 0 && len(rule.JSONPatch) == 0 {
+		return body, false
+	}
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return body, false
+	}
+	paths := make([]string, 0, len(rule.SetJSONPaths))
+	for path := range rule.SetJSONPaths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		// copy value so that later nested paths don't change the configured value
+		data = jsonpath.Put(data, jsonpath.Parse(path), copyJSONValue(rule.SetJSONPaths[path]))
+	}
+	// json patch is atomic so a failed operation such as test leaves the body as it was before the patch
+	if len(rule.JSONPatch) > 0 {
+		patched, failed := copyJSONValue(data), false
+		for _, op := range rule.JSONPatch {
+			var err error
+			if patched, err = op.apply(patched); err != nil {
+				log.WithFields(log.Fields{
+					"Component": "RewriteRule",
+					"Rule":      rule.Name,
+					"Op":        op.Op,
+					"Path":      op.Path,
+					"Error":     err,
+				}).Warnf("failed to apply json patch")
+				failed = true
+				break
+			}
+		}
+		if !failed {
+			data = patched
+		}
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return body, false
+	}
+	return b, true
+}
+
+// rewriteRegexCache caches compiled regex of rules by pattern
+var rewriteRegexCache sync.Map
+
+func (rule *RewriteRule) compile(pattern string) *regexp.Regexp {
+	if re, ok := rewriteRegexCache.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Component": "RewriteRule",
+			"Rule":      rule.Name,
+			"Regex":     pattern,
+			"Error":     err,
+		}).Warnf("failed to compile rewrite regex")
+		return nil
+	}
+	rewriteRegexCache.Store(pattern, re)
+	return re
+}
+
+// copyJSONValue deep copies objects and arrays of the value
+func copyJSONValue(val any) any {
+	switch v := val.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(v))
+		for k, item := range v {
+			res[k] = copyJSONValue(item)
+		}
+		return res
+	case []any:
+		res := make([]any, len(v))
+		for i, item := range v {
+			res[i] = copyJSONValue(item)
+		}
+		return res
+	}
+	return val
+}
+
+// apply JSON patch operation with JSON pointer paths such as /items/0/price
+func (op *JSONPatchOperation) apply(data any) (any, error) {
+	if err := op.Validate(); err != nil {
+		return data, err
+	}
+	path := parseJSONPointer(op.Path)
+	switch op.Op {
+	case "add":
+		return addJSONPointer(data, path, copyJSONValue(op.Value))
+	case "remove":
+		res, _, err := removeJSONPointer(data, path)
+		return res, err
+	case "replace":
+		res, _, err := removeJSONPointer(data, path)
+		if err != nil {
+			return data, err
+		}
+		return addJSONPointer(res, path, copyJSONValue(op.Value))
+	case "move":
+		res, val, err := removeJSONPointer(data, parseJSONPointer(op.From))
+		if err != nil {
+			return data, err
+		}
+		return addJSONPointer(res, path, val)
+	case "copy":
+		val, err := getJSONPointer(data, parseJSONPointer(op.From))
+		if err != nil {
+			return data, err
+		}
+		return addJSONPointer(data, path, copyJSONValue(val))
+	default: // test
+		val, err := getJSONPointer(data, path)
+		if err != nil {
+			return data, err
+		}
+		if !equalJSONValues(val, op.Value) {
+			return data, fmt.Errorf("test failed for path %s: %v != %v", op.Path, val, op.Value)
+		}
+		return data, nil
+	}
+}
+
+// parseJSONPointer returns unescaped tokens of JSON pointer such as /items/0/price
+func parseJSONPointer(pointer string) []string {
+	pointer = strings.TrimPrefix(pointer, "/")
+	if pointer == "" {
+		return nil
+	}
+	tokens := strings.Split(pointer, "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens
+}
+
+// jsonPointerIndex parses array index of token where "-" refers to the end of array if allowed
+func jsonPointerIndex(token string, arr []any, allowEnd bool) (int, error) {
+	if token == "-" && allowEnd {
+		return len(arr), nil
+	}
+	i, err := strconv.Atoi(token)
+	max := len(arr) - 1
+	if allowEnd {
+		max = len(arr)
+	}
+	if err != nil || i < 0 || i > max {
+		return 0, fmt.Errorf("invalid array index %s", token)
+	}
+	return i, nil
+}
+
+func getJSONPointer(node any, tokens []string) (any, error) {
+	for _, token := range tokens {
+		switch val := node.(type) {
+		case map[string]any:
+			child, exists := val[token]
+			if !exists {
+				return nil, fmt.Errorf("path /%s not found", strings.Join(tokens, "/"))
+			}
+			node = child
+		case []any:
+			i, err := jsonPointerIndex(token, val, false)
+			if err != nil {
+				return nil, err
+			}
+			node = val[i]
+		default:
+			return nil, fmt.Errorf("path /%s not found", strings.Join(tokens, "/"))
+		}
+	}
+	return node, nil
+}
+
+// addJSONPointer adds or replaces object member or inserts array element and returns the updated node
+func addJSONPointer(node any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	token := tokens[0]
+	switch val := node.(type) {
+	case map[string]any:
+		if len(tokens) == 1 {
+			val[token] = value
+			return val, nil
+		}
+		child, exists := val[token]
+		if !exists {
+			return node, fmt.Errorf("path /%s not found", strings.Join(tokens, "/"))
+		}
+		updated, err := addJSONPointer(child, tokens[1:], value)
+		if err != nil {
+			return node, err
+		}
+		val[token] = updated
+		return val, nil
+	case []any:
+		i, err := jsonPointerIndex(token, val, len(tokens) == 1)
+		if err != nil {
+			return node, err
+		}
+		if len(tokens) == 1 {
+			return append(val[:i], append([]any{value}, val[i:]...)...), nil
+		}
+		updated, err := addJSONPointer(val[i], tokens[1:], value)
+		if err != nil {
+			return node, err
+		}
+		val[i] = updated
+		return val, nil
+	}
+	return node, fmt.Errorf("path /%s not found", strings.Join(tokens, "/"))
+}
+
+// removeJSONPointer removes object member or array element and returns the updated node and removed value
+func removeJSONPointer(node any, tokens []string) (any, any, error) {
+	if len(tokens) == 0 {
+		return nil, node, nil
+	}
+	token := tokens[0]
+	switch val := node.(type) {
+	case map[string]any:
+		child, exists := val[token]
+		if !exists {
+			return node, nil, fmt.Errorf("path /%s not found", strings.Join(tokens, "/"))
+		}
+		if len(tokens) == 1 {
+			delete(val, token)
+			return val, child, nil
+		}
+		updated, removed, err := removeJSONPointer(child, tokens[1:])
+		if err != nil {
+			return node, nil, err
+		}
+		val[token] = updated
+		return val, removed, nil
+	case []any:
+		i, err := jsonPointerIndex(token, val, false)
+		if err != nil {
+			return node, nil, err
+		}
+		if len(tokens) == 1 {
+			removed := val[i]
+			return append(val[:i], val[i+1:]...), removed, nil
+		}
+		updated, removed, err := removeJSONPointer(val[i], tokens[1:])
+		if err != nil {
+			return node, nil, err
+		}
+		val[i] = updated
+		return val, removed, nil
+	}
+	return node, nil, fmt.Errorf("path /%s not found", strings.Join(tokens, "/"))
+}
+
+// equalJSONValues compares values after normalizing numbers and maps through JSON encoding
+func equalJSONValues(a any, b any) bool {
+	ab, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	var an, bn any
+	if json.Unmarshal(ab, &an) != nil || json.Unmarshal(bb, &bn) != nil {
+		return false
+	}
+	return reflect.DeepEqual(an, bn)
+}
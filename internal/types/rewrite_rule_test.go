@@ -0,0 +1,147 @@
+package types
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldRewriteRequest(t *testing.T) {
+	// GIVEN group config with ordered request rules
+	gc := &GroupConfig{
+		RewriteRules: []*RewriteRule{
+			{Name: "auth", SetHeaders: map[string]string{"Authorization": "Bearer staging"}, RemoveHeaders: []string{"Cookie"}},
+			{Name: "host", URLPattern: `^https://api\.example\.com/(.*)$`, URLReplacement: "https://staging.example.com/$1"},
+			{Name: "tracking", RemoveQuery: []string{"utm_*"}, SetQuery: map[string]string{"env": "staging"}},
+			{Name: "body", SetJSONPaths: map[string]any{"$.user.role": "admin", "$.items[*].qty": 1},
+				JSONPatch: []*JSONPatchOperation{{Op: "remove", Path: "/debug"}, {Op: "add", Path: "/items/-", Value: "x"}}},
+			{Name: "other", Match: `other\.com`, SetHeaders: map[string]string{"X-Other": "true"}},
+			{Name: "response", Phase: RewriteResponsePhase, SetHeaders: map[string]string{"X-Response": "true"}},
+		},
+	}
+	u, err := url.Parse("https://api.example.com/v1/users?utm_source=mail&id=1")
+	require.NoError(t, err)
+	req := &http.Request{Method: "POST", URL: u, Host: u.Host, Header: http.Header{
+		"Authorization": {"Bearer prod"},
+		"Cookie":        {"session=1"},
+	}}
+	body := []byte(`{"user":{"name":"jane"},"items":[{"qty":5},{"qty":7}],"debug":true}`)
+
+	// WHEN rewriting request
+	res := gc.RewriteRequest(req, body)
+
+	// THEN headers, url, query and body should be rewritten
+	require.Equal(t, "Bearer staging", req.Header.Get("Authorization"))
+	require.Equal(t, "", req.Header.Get("Cookie"))
+	require.Equal(t, "", req.Header.Get("X-Other"))
+	require.Equal(t, "", req.Header.Get("X-Response"))
+	require.Equal(t, "staging.example.com", req.Host)
+	require.Equal(t, "/v1/users", req.URL.Path)
+	require.Equal(t, "env=staging&id=1", req.URL.RawQuery)
+	require.JSONEq(t, `{"user":{"name":"jane","role":"admin"},"items":[{"qty":1},{"qty":1},"x"]}`, string(res))
+	// AND content length should match rewritten body
+	require.Equal(t, int64(len(res)), req.ContentLength)
+}
+
+func Test_ShouldSetOverlappingJSONPathsInOrder(t *testing.T) {
+	// GIVEN a rule with a parent path and a nested path
+	rule := &RewriteRule{Name: "user", SetJSONPaths: map[string]any{
+		"$.user.role": "admin",
+		"$.user":      map[string]any{"name": "guest"},
+	}}
+	for i := 0; i < 20; i++ {
+		// WHEN rewriting body
+		res, changed := rule.rewriteBody([]byte(`{"user":{"name":"jane"}}`))
+		// THEN parent path should be set before nested path
+		require.True(t, changed)
+		require.JSONEq(t, `{"user":{"name":"guest","role":"admin"}}`, string(res))
+	}
+	// AND configured value should not be changed
+	require.Equal(t, map[string]any{"name": "guest"}, rule.SetJSONPaths["$.user"])
+}
+
+func Test_ShouldRewriteResponse(t *testing.T) {
+	// GIVEN group config with response rules
+	gc := &GroupConfig{
+		RewriteRules: []*RewriteRule{
+			{Name: "request", SetHeaders: map[string]string{"X-Request": "true"}},
+			{Name: "response", Phase: RewriteResponsePhase, RemoveHeaders: []string{"Set-Cookie"},
+				JSONPatch: []*JSONPatchOperation{{Op: "replace", Path: "/data/0/status", Value: "active"}}},
+		},
+	}
+	u, err := url.Parse("https://api.example.com/v1/users")
+	require.NoError(t, err)
+	headers := http.Header{"Set-Cookie": {"session=1"}, ContentLengthHeader: {"10"}}
+
+	// WHEN rewriting response
+	res := gc.RewriteResponse(u, headers, []byte(`{"data":[{"status":"pending"}]}`))
+
+	// THEN only response rules should apply
+	require.JSONEq(t, `{"data":[{"status":"active"}]}`, string(res))
+	require.Equal(t, "", headers.Get("Set-Cookie"))
+	require.Equal(t, "", headers.Get("X-Request"))
+	require.Equal(t, "30", headers.Get(ContentLengthHeader))
+	// AND non-JSON body should be left alone
+	require.Equal(t, []byte("plain"), gc.RewriteResponse(u, headers, []byte("plain")))
+	// AND nil config should be no-op
+	var nilConfig *GroupConfig
+	require.True(t, bytes.Equal([]byte("x"), nilConfig.RewriteResponse(u, headers, []byte("x"))))
+}
+
+func Test_ShouldReturnRequestGroup(t *testing.T) {
+	u, err := url.Parse("https://api.example.com/v1/users")
+	require.NoError(t, err)
+	require.Equal(t, "v1_users", RequestGroup(&http.Request{URL: u, Header: http.Header{}}))
+	require.Equal(t, "users", RequestGroup(&http.Request{URL: u, Header: http.Header{MockGroup: {"users"}}}))
+}
+
+func Test_ShouldApplyJSONPatchOperations(t *testing.T) {
+	// GIVEN a rule with move, copy and test operations
+	rule := &RewriteRule{Name: "patch", JSONPatch: []*JSONPatchOperation{
+		{Op: "test", Path: "/user/name", Value: "jane"},
+		{Op: "move", From: "/user/name", Path: "/user/login"},
+		{Op: "copy", From: "/items/0", Path: "/items/-"},
+	}}
+	// WHEN rewriting body
+	res, changed := rule.rewriteBody([]byte(`{"user":{"name":"jane"},"items":[{"qty":5}]}`))
+	// THEN all operations should be applied
+	require.True(t, changed)
+	require.JSONEq(t, `{"user":{"login":"jane"},"items":[{"qty":5},{"qty":5}]}`, string(res))
+
+	// WHEN test operation fails
+	res, _ = rule.rewriteBody([]byte(`{"user":{"name":"john"},"items":[{"qty":5}]}`))
+	// THEN body should be left as it was
+	require.JSONEq(t, `{"user":{"name":"john"},"items":[{"qty":5}]}`, string(res))
+}
+
+func Test_ShouldNotPartiallyApplyFailedJSONPatch(t *testing.T) {
+	// GIVEN a rule whose second operation removes a missing field
+	rule := &RewriteRule{Name: "patch", JSONPatch: []*JSONPatchOperation{
+		{Op: "add", Path: "/status", Value: "active"},
+		{Op: "remove", Path: "/missing"},
+	}}
+	// WHEN rewriting body
+	res, _ := rule.rewriteBody([]byte(`{"id":1}`))
+	// THEN first operation should not be applied either
+	require.JSONEq(t, `{"id":1}`, string(res))
+}
+
+func Test_ShouldRejectUnsupportedJSONPatchOperations(t *testing.T) {
+	// GIVEN group config with unsupported op
+	gc := &GroupConfig{RewriteRules: []*RewriteRule{{Name: "bad", JSONPatch: []*JSONPatchOperation{{Op: "delete", Path: "/id"}}}}}
+	// WHEN validating
+	// THEN it should fail
+	require.Error(t, gc.Validate())
+	// AND paths must be json pointers
+	gc.RewriteRules[0].JSONPatch[0] = &JSONPatchOperation{Op: "remove", Path: "id"}
+	require.Error(t, gc.Validate())
+	// AND a value cannot be moved into its child
+	gc.RewriteRules[0].JSONPatch[0] = &JSONPatchOperation{Op: "move", From: "/a", Path: "/a/b"}
+	require.Error(t, gc.Validate())
+	// AND supported ops should pass
+	gc.RewriteRules[0].JSONPatch[0] = &JSONPatchOperation{Op: "copy", From: "/a", Path: "/b"}
+	require.NoError(t, gc.Validate())
+}
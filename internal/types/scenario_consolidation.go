@@ -0,0 +1,598 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bhatti/api-mock-service/internal/fuzz"
+)
+
+// ScenarioCluster defines scenarios with the same method, status and path shape that are merged into a template
+type ScenarioCluster struct {
+	// Method of scenarios
+	Method MethodType `yaml:"method" json:"method"`
+	// Path of template with path params such as /users/:id
+	Path string `yaml:"path" json:"path"`
+	// StatusCode of scenarios
+	StatusCode int `yaml:"status_code" json:"status_code"`
+	// PathParams with regex of inferred path params
+	PathParams map[string]string `yaml:"path_params" json:"path_params"`
+	// Sources names of scenarios that are merged
+	Sources []string `yaml:"sources" json:"sources"`
+	// GeneratedFields that vary across scenarios and are generated in template
+	GeneratedFields []string `yaml:"generated_fields" json:"generated_fields"`
+	// AssertedFields that are constant across scenarios and are asserted
+	AssertedFields []string `yaml:"asserted_fields" json:"asserted_fields"`
+	// Scenario template
+	Scenario *APIScenario `yaml:"scenario" json:"scenario"`
+	sources  []*APIScenario
+}
+
+// SourceScenarios returns scenarios that are merged
+func (c *ScenarioCluster) SourceScenarios() []*APIScenario {
+	return c.sources
+}
+
+// ConsolidationReport defines result of consolidating recorded scenarios into templates
+type ConsolidationReport struct {
+	// Group of scenarios
+	Group string `yaml:"group" json:"group"`
+	// Scanned number of scenarios
+	Scanned int `yaml:"scanned" json:"scanned"`
+	// Applied is true when templates are saved and source scenarios are removed
+	Applied bool `yaml:"applied" json:"applied"`
+	// Clusters of merged scenarios
+	Clusters []*ScenarioCluster `yaml:"clusters" json:"clusters"`
+}
+
+// DefaultConsolidationMinSize minimum number of scenarios for building a template
+const DefaultConsolidationMinSize = 2
+
+type pathSegmentKind int
+
+const (
+	literalSegment pathSegmentKind = iota
+	numericSegment
+	uuidSegment
+	slugSegment
+)
+
+var numericSegmentRegex = regexp.MustCompile(`^\d+$`)
+var uuidSegmentRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+var slugSegmentRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)+$`)
+
+var pathParamRegex = map[pathSegmentKind]string{
+	numericSegment: `\d+`,
+	uuidSegment:    `[0-9a-fA-F-]{36}`,
+	slugSegment:    `[a-z0-9-]+`,
+}
+
+func segmentKind(segment string) pathSegmentKind {
+	if numericSegmentRegex.MatchString(segment) {
+		return numericSegment
+	} else if uuidSegmentRegex.MatchString(segment) {
+		return uuidSegment
+	} else if slugSegmentRegex.MatchString(segment) {
+		return slugSegment
+	}
+	return literalSegment
+}
+
+// segmentKinds returns kinds of path segments where a slug is only a param candidate when its neighbours are
+// literal, so that hyphenated resource names such as /v1/user-profiles/1 are not mistaken for params
+func segmentKinds(segments []string) []pathSegmentKind {
+	kinds := make([]pathSegmentKind, len(segments))
+	for i, segment := range segments {
+		kinds[i] = segmentKind(segment)
+	}
+	res := make([]pathSegmentKind, len(segments))
+	for i, kind := range kinds {
+		if kind == slugSegment && (i > 0 && kinds[i-1] != literalSegment ||
+			i < len(kinds)-1 && kinds[i+1] != literalSegment) {
+			kind = literalSegment
+		}
+		res[i] = kind
+	}
+	return res
+}
+
+type shapedScenario struct {
+	scenario *APIScenario
+	segments []string
+	kinds    []pathSegmentKind
+}
+
+// ConsolidateScenarios clusters scenarios by method, status and path shape, infers path params and merges
+// bodies into a single type-tagged template for each cluster with at least minSize scenarios.
+func ConsolidateScenarios(scenarios []*APIScenario, minSize int) []*ScenarioCluster {
+	if minSize < DefaultConsolidationMinSize {
+		minSize = DefaultConsolidationMinSize
+	}
+	// group by shape where numeric, uuid and slug segments are placeholders
+	byShape := make(map[string][]*shapedScenario)
+	for _, scenario := range scenarios {
+		if scenario == nil || strings.ContainsAny(scenario.Path, ":{") {
+			continue // already a template
+		}
+		shaped := &shapedScenario{scenario: scenario, segments: strings.Split(strings.Trim(scenario.Path, "/"), "/")}
+		shaped.kinds = segmentKinds(shaped.segments)
+		shape := make([]string, len(shaped.segments))
+		for i, segment := range shaped.segments {
+			if kind := shaped.kinds[i]; kind == literalSegment {
+				shape[i] = segment
+			} else {
+				shape[i] = fmt.Sprintf("{%d}", shaped.kinds[i])
+			}
+		}
+		key := fmt.Sprintf("%s|%s|%d|%s", scenario.Group, scenario.Method, scenario.Response.StatusCode, strings.Join(shape, "/"))
+		byShape[key] = append(byShape[key], shaped)
+	}
+
+	clusters := make([]*ScenarioCluster, 0)
+	for _, members := range byShape {
+		// segments become params only when their values vary across scenarios of the same shape
+		params := make(map[int]bool)
+		for i, kind := range members[0].kinds {
+			if kind == literalSegment {
+				continue
+			}
+			for _, member := range members[1:] {
+				if member.segments[i] != members[0].segments[i] {
+					params[i] = true
+					break
+				}
+			}
+		}
+		byTemplate := make(map[string][]*shapedScenario)
+		for _, member := range members {
+			parts := make([]string, len(member.segments))
+			for i, segment := range member.segments {
+				if params[i] {
+					parts[i] = "*"
+				} else {
+					parts[i] = segment
+				}
+			}
+			key := strings.Join(parts, "/")
+			byTemplate[key] = append(byTemplate[key], member)
+		}
+		for _, group := range byTemplate {
+			if len(group) < minSize {
+				continue
+			}
+			clusters = append(clusters, buildScenarioCluster(group, params))
+		}
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Path == clusters[j].Path {
+			if clusters[i].Method == clusters[j].Method {
+				return clusters[i].StatusCode < clusters[j].StatusCode
+			}
+			return clusters[i].Method < clusters[j].Method
+		}
+		return clusters[i].Path < clusters[j].Path
+	})
+	return clusters
+}
+
+func buildScenarioCluster(members []*shapedScenario, params map[int]bool) *ScenarioCluster {
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].scenario.Path == members[j].scenario.Path {
+			return members[i].scenario.Name < members[j].scenario.Name
+		}
+		return members[i].scenario.Path < members[j].scenario.Path
+	})
+	first := members[0]
+	names := pathParamNames(first.segments, params)
+	pathParams := make(map[string]string)
+	segments := make([]string, len(first.segments))
+	for i, segment := range first.segments {
+		if params[i] {
+			segments[i] = ":" + names[i]
+			pathParams[names[i]] = pathParamRegex[first.kinds[i]]
+		} else {
+			segments[i] = segment
+		}
+	}
+	sources := make([]*APIScenario, len(members))
+	paramValues := make([]map[string]string, len(members))
+	for j, member := range members {
+		sources[j] = member.scenario
+		paramValues[j] = make(map[string]string)
+		for i := range member.segments {
+			if params[i] {
+				paramValues[j][names[i]] = member.segments[i]
+			}
+		}
+	}
+
+	cluster := &ScenarioCluster{
+		Method:          first.scenario.Method,
+		Path:            "/" + strings.Join(segments, "/"),
+		StatusCode:      first.scenario.Response.StatusCode,
+		PathParams:      pathParams,
+		Sources:         make([]string, len(members)),
+		GeneratedFields: make([]string, 0),
+		AssertedFields:  make([]string, 0),
+		sources:         sources,
+	}
+	for i, source := range sources {
+		cluster.Sources[i] = source.Name
+	}
+	cluster.Scenario = mergeClusterScenario(cluster, paramValues)
+	return cluster
+}
+
+// pathParamNames names params as id if there is a single param otherwise after the preceding segment, e.g. user_id
+func pathParamNames(segments []string, params map[int]bool) map[int]string {
+	names := make(map[int]string)
+	used := make(map[string]bool)
+	for i := range segments {
+		if !params[i] {
+			continue
+		}
+		name := "id"
+		if len(params) > 1 {
+			name = fmt.Sprintf("param%d", len(names)+1)
+			if i > 0 && !params[i-1] {
+				name = SanitizeNonAlphabet(strings.TrimSuffix(segments[i-1], "s"), "_") + "_id"
+			}
+		}
+		for used[name] {
+			name = fmt.Sprintf("%s%d", name, len(names)+1)
+		}
+		used[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+func mergeClusterScenario(cluster *ScenarioCluster, paramValues []map[string]string) *APIScenario {
+	first := cluster.sources[0]
+	merged := &APIScenario{
+		Method:         first.Method,
+		Path:           cluster.Path,
+		Description:    fmt.Sprintf("consolidated from %d recorded scenarios", len(cluster.sources)),
+		Group:          first.Group,
+		Tags:           first.Tags,
+		Predicate:      first.Predicate,
+		BaseURL:        first.BaseURL,
+		Authentication: first.Authentication,
+		Request: APIRequest{
+			PathParams:               cluster.PathParams,
+			QueryParams:              make(map[string]string),
+			PostParams:               make(map[string]string),
+			Headers:                  first.Request.Headers,
+			HTTPVersion:              first.Request.HTTPVersion,
+			AssertQueryParamsPattern: make(map[string]string),
+			AssertPostParamsPattern:  first.Request.AssertPostParamsPattern,
+			AssertHeadersPattern:     first.Request.AssertHeadersPattern,
+			Assertions:               first.Request.Assertions,
+			Variables:                first.Request.Variables,
+			ExampleContents:          first.Request.ExampleContents,
+		},
+		Response: APIResponse{
+			Headers:              first.Response.Headers,
+			StatusCode:           first.Response.StatusCode,
+			HTTPVersion:          first.Response.HTTPVersion,
+			AssertHeadersPattern: first.Response.AssertHeadersPattern,
+			Assertions:           append([]string{}, first.Response.Assertions...),
+			AddSharedVariables:   first.Response.AddSharedVariables,
+			ExampleContents:      first.Response.ExampleContents,
+		},
+	}
+	cluster.mergeParams("query", func(s *APIScenario) map[string]string { return s.Request.QueryParams },
+		merged.Request.QueryParams)
+	cluster.mergeParams("post", func(s *APIScenario) map[string]string { return s.Request.PostParams },
+		merged.Request.PostParams)
+	for k, v := range first.Request.AssertQueryParamsPattern {
+		if cluster.generated("query." + k) {
+			merged.Request.AssertQueryParamsPattern[k] = fuzz.ValueToRegEx(v, fuzz.NewDataTemplateRequest(false, 1, 1))
+		} else {
+			merged.Request.AssertQueryParamsPattern[k] = v
+		}
+	}
+
+	reqMerger := newBodyMerger(cluster, "request", nil)
+	merged.Request.Contents, merged.Request.AssertContentsPattern = reqMerger.mergeBodies(
+		func(s *APIScenario) string { return s.Request.Contents })
+	resMerger := newBodyMerger(cluster, "response", paramValues)
+	merged.Response.Contents, merged.Response.AssertContentsPattern = resMerger.mergeBodies(
+		func(s *APIScenario) string { return s.Response.Contents })
+	for _, assertion := range resMerger.assertions {
+		merged.Response.Assertions = AddAssertion(merged.Response.Assertions, assertion)
+	}
+	sort.Strings(cluster.GeneratedFields)
+	sort.Strings(cluster.AssertedFields)
+	merged.SetName("Consolidated" + merged.Group)
+	return merged
+}
+
+func (c *ScenarioCluster) generated(field string) bool {
+	for _, next := range c.GeneratedFields {
+		if next == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ScenarioCluster) mergeParams(
+	prefix string,
+	get func(*APIScenario) map[string]string,
+	res map[string]string) {
+	present := make(map[string]int)
+	for _, source := range c.sources {
+		for k, v := range get(source) {
+			present[k]++
+			if old, ok := res[k]; !ok {
+				res[k] = v
+			} else if old != v && !c.generated(prefix+"."+k) {
+				c.GeneratedFields = append(c.GeneratedFields, prefix+"."+k)
+			}
+		}
+	}
+	// params that are missing in some scenarios are optional so they are generated instead of asserted
+	for k, n := range present {
+		if n < len(c.sources) && !c.generated(prefix+"."+k) {
+			c.GeneratedFields = append(c.GeneratedFields, prefix+"."+k)
+		}
+	}
+	for k, v := range res {
+		if !c.generated(prefix + "." + k) {
+			c.AssertedFields = append(c.AssertedFields, prefix+"."+k)
+		} else {
+			res[k] = fuzz.PrefixTypeStringToRegEx(fuzz.StripTypeTags(v), fuzz.NewDataTemplateRequest(false, 1, 1))
+		}
+	}
+}
+
+// bodyMerger merges JSON bodies of scenarios where constant values are kept and varying values are generated
+type bodyMerger struct {
+	cluster      *ScenarioCluster
+	prefix       string
+	paramValues  []map[string]string
+	templates    map[string]string
+	assertions   []string
+	dataTemplate fuzz.DataTemplateRequest
+}
+
+type bodySample struct {
+	val     any
+	src     int
+	inArray bool
+	// partial is set when the field is missing in some of the samples of its parent
+	partial bool
+}
+
+func newBodyMerger(cluster *ScenarioCluster, prefix string, paramValues []map[string]string) *bodyMerger {
+	return &bodyMerger{
+		cluster:      cluster,
+		prefix:       prefix,
+		paramValues:  paramValues,
+		templates:    make(map[string]string),
+		assertions:   make([]string, 0),
+		dataTemplate: fuzz.NewDataTemplateRequest(false, 1, 1),
+	}
+}
+
+// mergeBodies returns merged contents and flat regex map for asserting contents
+func (m *bodyMerger) mergeBodies(get func(*APIScenario) string) (contents string, assertPattern string) {
+	samples := make([]bodySample, 0)
+	for i, source := range m.cluster.sources {
+		body := strings.TrimSpace(get(source))
+		if body == "" {
+			continue
+		}
+		var val any
+		if err := json.Unmarshal([]byte(body), &val); err != nil {
+			return get(m.cluster.sources[0]), "" // not json so keep first body as is
+		}
+		samples = append(samples, bodySample{val: val, src: i})
+	}
+	if len(samples) == 0 {
+		return "", ""
+	}
+	merged, typed := m.merge("", samples)
+	b, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return get(m.cluster.sources[0]), ""
+	}
+	contents = string(b)
+	for placeholder, expr := range m.templates {
+		contents = strings.ReplaceAll(contents, `"`+placeholder+`"`, expr)
+	}
+	if b, err = json.MarshalIndent(fuzz.FlatRegexMap(typed), "", "  "); err == nil {
+		assertPattern = string(b)
+	}
+	return
+}
+
+func (m *bodyMerger) merge(key string, samples []bodySample) (merged any, typed any) {
+	if len(samples) == 0 {
+		return nil, nil
+	}
+	switch first := samples[0].val.(type) {
+	case map[string]any:
+		keys := make(map[string]bool)
+		for _, sample := range samples {
+			if hm, ok := sample.val.(map[string]any); ok {
+				for k := range hm {
+					keys[k] = true
+				}
+			}
+		}
+		mergedMap := make(map[string]any)
+		typedMap := make(map[string]any)
+		for k := range keys {
+			children := make([]bodySample, 0, len(samples))
+			for _, sample := range samples {
+				if hm, ok := sample.val.(map[string]any); ok {
+					if v, exists := hm[k]; exists {
+						children = append(children, bodySample{val: v, src: sample.src, inArray: sample.inArray,
+							partial: sample.partial})
+					}
+				}
+			}
+			if len(children) < len(samples) {
+				for i := range children {
+					children[i].partial = true
+				}
+			}
+			mergedMap[k], typedMap[k] = m.merge(joinFieldKey(key, k), children)
+		}
+		return mergedMap, typedMap
+	case []any:
+		elements := make([]bodySample, 0)
+		for _, sample := range samples {
+			if arr, ok := sample.val.([]any); ok {
+				for _, v := range arr {
+					elements = append(elements, bodySample{val: v, src: sample.src, inArray: true, partial: sample.partial})
+				}
+			}
+		}
+		mergedElement, typedElement := m.merge(key, elements)
+		mergedArr := make([]any, len(first))
+		for i := range first {
+			mergedArr[i] = mergedElement
+		}
+		if len(elements) == 0 {
+			return mergedArr, []any{}
+		}
+		return mergedArr, []any{typedElement}
+	}
+	return m.mergeScalar(key, samples)
+}
+
+func (m *bodyMerger) mergeScalar(key string, samples []bodySample) (merged any, typed any) {
+	field := m.prefix + "." + key
+	first := samples[0].val
+	// a field that is missing in some scenarios is not asserted
+	constant := !samples[0].partial
+	for _, sample := range samples[1:] {
+		if !reflect.DeepEqual(sample.val, first) {
+			constant = false
+			break
+		}
+	}
+	if first == nil {
+		return nil, nil
+	}
+	if constant {
+		m.cluster.AssertedFields = append(m.cluster.AssertedFields, field)
+		if !samples[0].inArray && m.paramValues != nil {
+			m.addAssertion(key, first)
+		}
+		if str, ok := first.(string); ok {
+			return first, fuzz.PrefixTypeString + "^" + regexp.QuoteMeta(str) + "$"
+		}
+		return first, fuzz.ExtractTypes(first, m.dataTemplate)
+	}
+	m.cluster.GeneratedFields = append(m.cluster.GeneratedFields, field)
+	typed = fuzz.ExtractTypes(first, m.dataTemplate)
+	if m.paramValues == nil {
+		return typed, typed // request contents use type tags that are populated by producer
+	}
+	expr := m.generatorTemplate(samples)
+	if expr == "" {
+		return first, typed
+	}
+	placeholder := fmt.Sprintf("__consolidated_%d__", len(m.templates))
+	m.templates[placeholder] = expr
+	return placeholder, typed
+}
+
+// generatorTemplate builds template expression for generating varying value of response
+func (m *bodyMerger) generatorTemplate(samples []bodySample) string {
+	if name := m.pathParamEcho(samples); name != "" {
+		if _, ok := samples[0].val.(string); ok {
+			return `"{{.` + name + `}}"`
+		}
+		return `{{.` + name + `}}`
+	}
+	switch first := samples[0].val.(type) {
+	case float64:
+		min, max, integral := first, first, true
+		for _, sample := range samples {
+			f, ok := sample.val.(float64)
+			if !ok {
+				return ""
+			}
+			if f < min {
+				min = f
+			}
+			if f > max {
+				max = f
+			}
+			integral = integral && f == float64(int64(f))
+		}
+		if integral {
+			return fmt.Sprintf("{{RandIntMinMax %d %d}}", int64(min), int64(max))
+		}
+		return fmt.Sprintf("{{RandFloatMinMax %s %s}}",
+			strconv.FormatFloat(min, 'f', -1, 64), strconv.FormatFloat(max, 'f', -1, 64))
+	case bool:
+		return "{{RandBool}}"
+	case string:
+		uuids := true
+		for _, sample := range samples {
+			str, ok := sample.val.(string)
+			if !ok {
+				return ""
+			}
+			uuids = uuids && uuidSegmentRegex.MatchString(str)
+		}
+		if uuids {
+			return `"{{UUID}}"`
+		}
+		re := fuzz.ValueToRegEx(first, m.dataTemplate)
+		if re == "" || strings.Contains(re, "`") {
+			return ""
+		}
+		return "\"{{RandRegex `" + re + "`}}\""
+	}
+	return ""
+}
+
+// pathParamEcho returns name of path param whose value matches the value in every sample
+func (m *bodyMerger) pathParamEcho(samples []bodySample) string {
+	for name := range m.cluster.PathParams {
+		matched := true
+		for _, sample := range samples {
+			if fmt.Sprintf("%v", sample.val) != m.paramValues[sample.src][name] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return name
+		}
+	}
+	return ""
+}
+
+func (m *bodyMerger) addAssertion(key string, val any) {
+	switch v := val.(type) {
+	case float64:
+		m.assertions = append(m.assertions, fmt.Sprintf("NumPropertyEQ contents.%s %s",
+			key, strconv.FormatFloat(v, 'f', -1, 64)))
+	case bool:
+		m.assertions = append(m.assertions, fmt.Sprintf("PropertyEquals contents.%s %v", key, v))
+	case string:
+		if v != "" && !strings.ContainsAny(v, " \t\n\"") {
+			m.assertions = append(m.assertions, fmt.Sprintf("PropertyEquals contents.%s %s", key, v))
+		}
+	}
+}
+
+func joinFieldKey(prefix string, k string) string {
+	if prefix == "" {
+		return k
+	}
+	return prefix + "." + k
+}
@@ -0,0 +1,147 @@
+package types
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func buildRecordedScenario(t *testing.T, method string, rawURL string, resBody string) *APIScenario {
+	u, err := url.Parse(rawURL)
+	require.NoError(t, err)
+	scenario, err := BuildScenarioFromHTTP(
+		BuildTestConfig(),
+		"Recorded",
+		u,
+		method,
+		"users",
+		"",
+		"",
+		nil,
+		[]byte(resBody),
+		u.Query(),
+		nil,
+		http.Header{},
+		"",
+		http.Header{ContentTypeHeader: []string{"application/json"}},
+		"",
+		200,
+		time.Now(),
+		time.Now())
+	require.NoError(t, err)
+	return scenario
+}
+
+func Test_ShouldConsolidateRecordedScenarios(t *testing.T) {
+	// GIVEN recorded scenarios for same api with different ids
+	scenarios := make([]*APIScenario, 0)
+	for i := 1; i <= 3; i++ {
+		scenarios = append(scenarios, buildRecordedScenario(t, "GET",
+			fmt.Sprintf("https://api.example.com/users/%d", i),
+			fmt.Sprintf(`{"id":%d,"name":"user%d","status":"active","score":%d.5,"tags":["a","b"]}`, i, i, i)))
+	}
+	scenarios = append(scenarios, buildRecordedScenario(t, "GET", "https://api.example.com/users/me", `{}`))
+
+	// WHEN consolidating scenarios
+	clusters := ConsolidateScenarios(scenarios, 2)
+
+	// THEN it should build a single parameterized template
+	require.Len(t, clusters, 1)
+	cluster := clusters[0]
+	require.Equal(t, "/users/:id", cluster.Path)
+	require.Equal(t, `\d+`, cluster.PathParams["id"])
+	require.Len(t, cluster.Sources, 3)
+	require.Equal(t, []string{"response.id", "response.name", "response.score", "response.tags"}, cluster.GeneratedFields)
+	require.Equal(t, []string{"response.status"}, cluster.AssertedFields)
+	require.Equal(t, "/users/:id", cluster.Scenario.Path)
+	require.Equal(t, `\d+`, cluster.Scenario.Request.PathParams["id"])
+	require.Contains(t, cluster.Scenario.Response.Contents, `"id": {{.id}}`)
+	require.Contains(t, cluster.Scenario.Response.Contents, "RandRegex")
+	require.Contains(t, cluster.Scenario.Response.Contents, "{{RandFloatMinMax 1.5 3.5}}")
+	require.Contains(t, cluster.Scenario.Response.Contents, `"status": "active"`)
+	require.Contains(t, cluster.Scenario.Response.Assertions, "PropertyEquals contents.status active")
+	require.Contains(t, cluster.Scenario.Response.AssertContentsPattern, `^active$`)
+	require.NoError(t, cluster.Scenario.Validate())
+	require.True(t, strings.HasPrefix(cluster.Scenario.Name, "Consolidated"))
+}
+
+func Test_ShouldInferSlugAndUUIDPathParams(t *testing.T) {
+	// GIVEN recorded scenarios with uuid and slug segments
+	scenarios := []*APIScenario{
+		buildRecordedScenario(t, "GET", "https://api.example.com/orgs/1f0c7a4e-8d2b-4c1a-9e3f-2b6d8c9a0e11/posts/first-post",
+			`{"slug":"first-post"}`),
+		buildRecordedScenario(t, "GET", "https://api.example.com/orgs/6a2b9c1d-3e4f-4a5b-8c7d-9e0f1a2b3c4d/posts/second-post",
+			`{"slug":"second-post"}`),
+		buildRecordedScenario(t, "GET", "https://api.example.com/accounts/cash-balance", `{}`),
+	}
+
+	// WHEN consolidating scenarios
+	clusters := ConsolidateScenarios(scenarios, 2)
+
+	// THEN it should name params after preceding segments
+	require.Len(t, clusters, 1)
+	require.Equal(t, "/orgs/:org_id/posts/:post_id", clusters[0].Path)
+	require.Equal(t, `[a-z0-9-]+`, clusters[0].PathParams["post_id"])
+	require.Contains(t, clusters[0].Scenario.Response.Contents, `"slug": "{{.post_id}}"`)
+}
+
+func Test_ShouldNotConsolidateSingleScenarios(t *testing.T) {
+	// GIVEN recorded scenarios with different shapes
+	scenarios := []*APIScenario{
+		buildRecordedScenario(t, "GET", "https://api.example.com/users/1", `{}`),
+		buildRecordedScenario(t, "POST", "https://api.example.com/users/2", `{}`),
+	}
+	// WHEN consolidating scenarios
+	// THEN it should not build any template
+	require.Len(t, ConsolidateScenarios(scenarios, 2), 0)
+}
+
+func Test_ShouldNotConsolidateHyphenatedResourceNames(t *testing.T) {
+	// GIVEN recorded scenarios of different resources with hyphenated names and ids
+	scenarios := []*APIScenario{
+		buildRecordedScenario(t, "GET", "https://api.example.com/v1/user-profiles/1", `{"id":1}`),
+		buildRecordedScenario(t, "GET", "https://api.example.com/v1/order-items/2", `{"id":2}`),
+	}
+	// WHEN consolidating scenarios
+	// THEN resource names should not become path params
+	require.Len(t, ConsolidateScenarios(scenarios, 2), 0)
+
+	// AND WHEN each resource has multiple recordings
+	scenarios = append(scenarios,
+		buildRecordedScenario(t, "GET", "https://api.example.com/v1/user-profiles/3", `{"id":3}`),
+		buildRecordedScenario(t, "GET", "https://api.example.com/v1/order-items/4", `{"id":4}`))
+	clusters := ConsolidateScenarios(scenarios, 2)
+
+	// THEN each resource should get its own template
+	require.Len(t, clusters, 2)
+	require.Equal(t, "/v1/order-items/:id", clusters[0].Path)
+	require.Equal(t, "/v1/user-profiles/:id", clusters[1].Path)
+}
+
+func Test_ShouldGenerateFieldsMissingInSomeScenarios(t *testing.T) {
+	// GIVEN recorded scenarios where optional fields and params are not always present
+	scenarios := []*APIScenario{
+		buildRecordedScenario(t, "GET", "https://api.example.com/users/1?verbose=true",
+			`{"id":1,"status":"active","nickname":"bob","address":{"city":"Seattle"}}`),
+		buildRecordedScenario(t, "GET", "https://api.example.com/users/2",
+			`{"id":2,"status":"active"}`),
+		buildRecordedScenario(t, "GET", "https://api.example.com/users/3?verbose=true",
+			`{"id":3,"status":"active","nickname":"bob","address":{"city":"Seattle"}}`),
+	}
+
+	// WHEN consolidating scenarios
+	clusters := ConsolidateScenarios(scenarios, 2)
+
+	// THEN partially present fields should be generated rather than asserted
+	require.Len(t, clusters, 1)
+	require.Equal(t, []string{"response.status"}, clusters[0].AssertedFields)
+	require.Contains(t, clusters[0].GeneratedFields, "response.nickname")
+	require.Contains(t, clusters[0].GeneratedFields, "response.address.city")
+	require.Contains(t, clusters[0].GeneratedFields, "query.verbose")
+	require.NotContains(t, clusters[0].Scenario.Response.Assertions, "PropertyEquals contents.nickname bob")
+}
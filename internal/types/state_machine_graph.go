@@ -0,0 +1,239 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AnyState is used as the source node for transitions without a from state, which fire in any state.
+const AnyState = "*"
+
+// StateMachineEdge defines a transition between two states along with the API that triggers it
+type StateMachineEdge struct {
+	// From state of the transition
+	From string `yaml:"from" json:"from"`
+	// To state of the transition
+	To string `yaml:"to" json:"to"`
+	// Method of the API that triggers the transition
+	Method string `yaml:"method" json:"method"`
+	// Path of the API that triggers the transition
+	Path string `yaml:"path" json:"path"`
+	// Status of the response that triggers the transition (0 = any)
+	Status int `yaml:"status" json:"status"`
+	// Scenario name that defines the transition
+	Scenario string `yaml:"scenario" json:"scenario"`
+}
+
+// Label returns label of the edge
+func (e StateMachineEdge) Label() string {
+	if e.Status > 0 {
+		return fmt.Sprintf("%s %s %d", e.Method, e.Path, e.Status)
+	}
+	return fmt.Sprintf("%s %s", e.Method, e.Path)
+}
+
+// StateMachineGraph defines the workflow of a group built from state machines of its scenarios
+type StateMachineGraph struct {
+	// Group of scenarios
+	Group string `yaml:"group" json:"group"`
+	// States defined by all transitions
+	States []string `yaml:"states" json:"states"`
+	// InitialStates of scenarios
+	InitialStates []string `yaml:"initial_states" json:"initial_states"`
+	// Edges for transitions
+	Edges []StateMachineEdge `yaml:"edges" json:"edges"`
+	// UnreachableStates that cannot be reached from any initial state
+	UnreachableStates []string `yaml:"unreachable_states" json:"unreachable_states"`
+	// DeadEndStates that have no outgoing transitions
+	DeadEndStates []string `yaml:"dead_end_states" json:"dead_end_states"`
+}
+
+// BuildStateMachineGraph builds state machine graph from transitions of the scenarios
+func BuildStateMachineGraph(group string, scenarios []*APIScenario) *StateMachineGraph {
+	graph := &StateMachineGraph{
+		Group:             group,
+		States:            make([]string, 0),
+		InitialStates:     make([]string, 0),
+		Edges:             make([]StateMachineEdge, 0),
+		UnreachableStates: make([]string, 0),
+		DeadEndStates:     make([]string, 0),
+	}
+	states := make(map[string]bool)
+	initial := make(map[string]bool)
+	for _, scenario := range scenarios {
+		if scenario == nil || scenario.StateMachine == nil {
+			continue
+		}
+		if scenario.StateMachine.InitialState != "" {
+			initial[scenario.StateMachine.InitialState] = true
+			states[scenario.StateMachine.InitialState] = true
+		}
+		// the first transition matching the method, status and current state fires, like consumer executor
+		firedFrom := make(map[string]bool)
+		for _, t := range scenario.StateMachine.Transitions {
+			if !transitionFires(scenario, t) || firedFrom[t.From] || firedFrom[""] {
+				continue
+			}
+			firedFrom[t.From] = true
+			if t.To == "" {
+				// transition without to state resets the session rather than moving to a state
+				continue
+			}
+			edge := StateMachineEdge{
+				From:     t.From,
+				To:       t.To,
+				Method:   strings.ToUpper(t.OnMethod),
+				Path:     scenario.Path,
+				Status:   t.OnStatus,
+				Scenario: scenario.Name,
+			}
+			if edge.From == "" {
+				edge.From = AnyState
+			} else {
+				states[edge.From] = true
+			}
+			if edge.Method == "" {
+				edge.Method = string(scenario.Method)
+			}
+			if edge.Status == 0 {
+				edge.Status = scenario.Response.StatusCode
+			}
+			states[edge.To] = true
+			graph.Edges = append(graph.Edges, edge)
+		}
+	}
+	graph.States = sortedKeys(states)
+	graph.InitialStates = sortedKeys(initial)
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].From == graph.Edges[j].From {
+			if graph.Edges[i].To == graph.Edges[j].To {
+				return graph.Edges[i].Label() < graph.Edges[j].Label()
+			}
+			return graph.Edges[i].To < graph.Edges[j].To
+		}
+		return graph.Edges[i].From < graph.Edges[j].From
+	})
+
+	reachable := graph.reachableStates()
+	outgoing := make(map[string]bool)
+	for _, edge := range graph.Edges {
+		outgoing[edge.From] = true
+	}
+	for _, state := range graph.States {
+		if !reachable[state] {
+			graph.UnreachableStates = append(graph.UnreachableStates, state)
+		}
+		if !outgoing[state] && !outgoing[AnyState] {
+			graph.DeadEndStates = append(graph.DeadEndStates, state)
+		}
+	}
+	return graph
+}
+
+// transitionFires checks method and status of transition against the scenario that defines it
+func transitionFires(scenario *APIScenario, t StateTransition) bool {
+	methodMatch := t.OnMethod == "" || strings.EqualFold(t.OnMethod, string(scenario.Method))
+	statusMatch := t.OnStatus == 0 || t.OnStatus == scenario.Response.StatusCode
+	return methodMatch && statusMatch
+}
+
+// reachableStates walks transitions from initial states
+func (g *StateMachineGraph) reachableStates() map[string]bool {
+	reachable := make(map[string]bool)
+	queue := make([]string, 0, len(g.InitialStates))
+	for _, state := range g.InitialStates {
+		reachable[state] = true
+		queue = append(queue, state)
+	}
+	// transitions without from state fire in any state once the workflow has started
+	if len(queue) > 0 {
+		for _, edge := range g.Edges {
+			if edge.From == AnyState && edge.To != "" && !reachable[edge.To] {
+				reachable[edge.To] = true
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, edge := range g.Edges {
+			if edge.From == current && edge.To != "" && !reachable[edge.To] {
+				reachable[edge.To] = true
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+	return reachable
+}
+
+// ToMermaid renders state machine graph as mermaid state diagram
+func (g *StateMachineGraph) ToMermaid() string {
+	var sb strings.Builder
+	sb.WriteString("stateDiagram-v2\n")
+	for _, state := range g.InitialStates {
+		sb.WriteString(fmt.Sprintf("    [*] --> %s\n", mermaidID(state)))
+	}
+	for _, edge := range g.Edges {
+		from := mermaidID(edge.From)
+		if edge.From == AnyState {
+			from = "any_state"
+		}
+		sb.WriteString(fmt.Sprintf("    %s --> %s : %s\n", from, mermaidID(edge.To), edge.Label()))
+	}
+	for _, state := range g.UnreachableStates {
+		sb.WriteString(fmt.Sprintf("    note right of %s : unreachable\n", mermaidID(state)))
+	}
+	for _, state := range g.DeadEndStates {
+		sb.WriteString(fmt.Sprintf("    %s --> [*]\n", mermaidID(state)))
+	}
+	return sb.String()
+}
+
+// ToDot renders state machine graph in graphviz dot format
+func (g *StateMachineGraph) ToDot() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("digraph %q {\n", g.Group))
+	sb.WriteString("    rankdir=LR;\n")
+	sb.WriteString("    __start [shape=point];\n")
+	unreachable := make(map[string]bool)
+	for _, state := range g.UnreachableStates {
+		unreachable[state] = true
+	}
+	deadEnd := make(map[string]bool)
+	for _, state := range g.DeadEndStates {
+		deadEnd[state] = true
+	}
+	for _, state := range g.States {
+		attrs := "shape=ellipse"
+		if deadEnd[state] {
+			attrs = "shape=doublecircle"
+		}
+		if unreachable[state] {
+			attrs += ", style=dashed, color=red"
+		}
+		sb.WriteString(fmt.Sprintf("    %q [%s];\n", state, attrs))
+	}
+	for _, state := range g.InitialStates {
+		sb.WriteString(fmt.Sprintf("    __start -> %q;\n", state))
+	}
+	for _, edge := range g.Edges {
+		sb.WriteString(fmt.Sprintf("    %q -> %q [label=%q];\n", edge.From, edge.To, edge.Label()))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func mermaidID(state string) string {
+	return SanitizeNonAlphabet(state, "_")
+}
+
+func sortedKeys(m map[string]bool) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,104 @@
+package types
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func buildStateMachineScenario(name string, method MethodType, path string, status int,
+	initial string, transitions ...StateTransition) *APIScenario {
+	return &APIScenario{
+		Method:   method,
+		Name:     name,
+		Path:     path,
+		Group:    "orders",
+		Response: APIResponse{StatusCode: status},
+		StateMachine: &ScenarioStateMachine{
+			InitialState: initial,
+			Transitions:  transitions,
+		},
+	}
+}
+
+func Test_ShouldBuildStateMachineGraph(t *testing.T) {
+	// GIVEN scenarios with state machines
+	scenarios := []*APIScenario{
+		buildStateMachineScenario("create", Post, "/orders", 201, "new",
+			StateTransition{From: "new", To: "created"}),
+		buildStateMachineScenario("pay", Put, "/orders/:id/pay", 200, "",
+			StateTransition{From: "created", To: "paid", OnMethod: "put", OnStatus: 200}),
+		buildStateMachineScenario("refund", Post, "/orders/:id/refund", 200, "",
+			StateTransition{From: "disputed", To: "refunded"}),
+		{Method: Get, Name: "list", Path: "/orders"},
+	}
+	// WHEN building graph
+	graph := BuildStateMachineGraph("orders", scenarios)
+	// THEN it should find states, edges and unreachable states
+	require.Equal(t, []string{"created", "disputed", "new", "paid", "refunded"}, graph.States)
+	require.Equal(t, []string{"new"}, graph.InitialStates)
+	require.Len(t, graph.Edges, 3)
+	require.Equal(t, "POST /orders 201", graph.Edges[2].Label())
+	require.Equal(t, []string{"disputed", "refunded"}, graph.UnreachableStates)
+	require.Equal(t, []string{"paid", "refunded"}, graph.DeadEndStates)
+}
+
+func Test_ShouldReachStatesFromAnyStateTransitions(t *testing.T) {
+	// GIVEN scenarios with transition without from state
+	scenarios := []*APIScenario{
+		buildStateMachineScenario("create", Post, "/orders", 201, "new",
+			StateTransition{From: "new", To: "created"}),
+		buildStateMachineScenario("cancel", Delete, "/orders/:id", 200, "",
+			StateTransition{To: "cancelled"}),
+	}
+	// WHEN building graph
+	graph := BuildStateMachineGraph("orders", scenarios)
+	// THEN cancelled state should be reachable and no state should be dead-end
+	require.Len(t, graph.UnreachableStates, 0)
+	require.Len(t, graph.DeadEndStates, 0)
+	require.Equal(t, AnyState, graph.Edges[0].From)
+}
+
+func Test_ShouldRenderStateMachineGraph(t *testing.T) {
+	// GIVEN a state machine graph
+	graph := BuildStateMachineGraph("orders", []*APIScenario{
+		buildStateMachineScenario("create", Post, "/orders", 201, "new",
+			StateTransition{From: "new", To: "created"}),
+		buildStateMachineScenario("refund", Post, "/orders/:id/refund", 200, "",
+			StateTransition{From: "disputed", To: "refunded"}),
+	})
+	// WHEN rendering as mermaid
+	mermaid := graph.ToMermaid()
+	// THEN it should include initial state, edges and unreachable notes
+	require.True(t, strings.HasPrefix(mermaid, "stateDiagram-v2"))
+	require.Contains(t, mermaid, "[*] --> new")
+	require.Contains(t, mermaid, "new --> created : POST /orders 201")
+	require.Contains(t, mermaid, "note right of disputed : unreachable")
+
+	// WHEN rendering as dot
+	dot := graph.ToDot()
+	// THEN it should include edges and mark unreachable states
+	require.Contains(t, dot, `digraph "orders"`)
+	require.Contains(t, dot, `"new" -> "created" [label="POST /orders 201"];`)
+	require.Contains(t, dot, `"disputed" [shape=ellipse, style=dashed, color=red];`)
+}
+
+func Test_ShouldSkipStateMachineTransitionsThatNeverFire(t *testing.T) {
+	// GIVEN transitions with method or status of other scenarios, shadowed transitions and empty to state
+	scenarios := []*APIScenario{
+		buildStateMachineScenario("create", Post, "/orders", 201, "new",
+			StateTransition{From: "new", To: "created", OnMethod: "put"},
+			StateTransition{From: "new", To: "failed", OnStatus: 500},
+			StateTransition{From: "new", To: "pending"},
+			StateTransition{From: "new", To: "shadowed"}),
+		buildStateMachineScenario("reset", Delete, "/orders/:id", 200, "",
+			StateTransition{From: "pending"}),
+	}
+	// WHEN building graph
+	graph := BuildStateMachineGraph("orders", scenarios)
+	// THEN only transitions that fire at runtime should be included
+	require.Equal(t, []string{"new", "pending"}, graph.States)
+	require.Len(t, graph.Edges, 1)
+	require.Equal(t, "pending", graph.Edges[0].To)
+	require.NotContains(t, graph.ToMermaid(), "-->  :")
+}
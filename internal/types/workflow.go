@@ -0,0 +1,138 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// Phases of workflow steps
+const (
+	WorkflowSetup    = "setup"
+	WorkflowStep     = "step"
+	WorkflowTeardown = "teardown"
+)
+
+// Status of workflow steps in addition to passed, failed and flaky outcomes
+const (
+	OutcomeSkipped = "skipped"
+)
+
+// Workflow defines steps of a group that are executed as a dependency graph
+type Workflow struct {
+	// Name of the workflow
+	Name string `yaml:"name" json:"name"`
+	// Group of scenarios referenced by steps
+	Group string `yaml:"group" json:"group"`
+	// Variables available to all steps
+	Variables map[string]any `yaml:"variables" json:"variables,omitempty"`
+	// Setup steps run in order before other steps
+	Setup []*WorkflowStepDefinition `yaml:"setup" json:"setup,omitempty"`
+	// Steps run when their dependencies passed, independent branches in parallel
+	Steps []*WorkflowStepDefinition `yaml:"steps" json:"steps"`
+	// Teardown steps always run in order after other steps
+	Teardown []*WorkflowStepDefinition `yaml:"teardown" json:"teardown,omitempty"`
+}
+
+// WorkflowStepDefinition executes a scenario of the workflow group
+type WorkflowStepDefinition struct {
+	// Name of the step, unique within workflow
+	Name string `yaml:"name" json:"name"`
+	// Scenario name executed by the step
+	Scenario string `yaml:"scenario" json:"scenario"`
+	// DependsOn names of steps that must pass before the step
+	DependsOn []string `yaml:"depends_on" json:"depends_on,omitempty"`
+	// Variables of the step added to workflow variables
+	Variables map[string]any `yaml:"variables" json:"variables,omitempty"`
+	// Extract maps variable names to JSONPath of response such as $.id
+	Extract map[string]string `yaml:"extract" json:"extract,omitempty"`
+	// Assertions added to response assertions of the scenario
+	Assertions []string `yaml:"assertions" json:"assertions,omitempty"`
+}
+
+// WorkflowStepResult is the outcome of a workflow step
+type WorkflowStepResult struct {
+	Name          string         `yaml:"name" json:"name"`
+	Scenario      string         `yaml:"scenario" json:"scenario"`
+	Phase         string         `yaml:"phase" json:"phase"`
+	Status        string         `yaml:"status" json:"status"`
+	Error         string         `yaml:"error" json:"error,omitempty"`
+	StartedAt     time.Time      `yaml:"started_at" json:"started_at"`
+	LatencyMillis int64          `yaml:"latency_millis" json:"latency_millis"`
+	Extracted     map[string]any `yaml:"extracted" json:"extracted,omitempty"`
+}
+
+// Passed returns true if step passed or passed on retry
+func (r *WorkflowStepResult) Passed() bool {
+	return r.Status == OutcomePassed || r.Status == OutcomeFlaky
+}
+
+// AllSteps returns setup, steps and teardown
+func (w *Workflow) AllSteps() []*WorkflowStepDefinition {
+	all := make([]*WorkflowStepDefinition, 0, len(w.Setup)+len(w.Steps)+len(w.Teardown))
+	all = append(all, w.Setup...)
+	all = append(all, w.Steps...)
+	return append(all, w.Teardown...)
+}
+
+// Validate checks that step names are unique, scenarios are set and dependencies form an acyclic graph of steps
+func (w *Workflow) Validate() error {
+	if len(w.Steps) == 0 {
+		return fmt.Errorf("workflow %s has no steps", w.Name)
+	}
+	names := make(map[string]bool)
+	for _, step := range w.AllSteps() {
+		if step.Name == "" {
+			return fmt.Errorf("workflow step name is not specified")
+		}
+		if step.Scenario == "" {
+			return fmt.Errorf("scenario of workflow step %s is not specified", step.Name)
+		}
+		if names[step.Name] {
+			return fmt.Errorf("workflow step %s is duplicated", step.Name)
+		}
+		names[step.Name] = true
+	}
+	for _, step := range append(append([]*WorkflowStepDefinition{}, w.Setup...), w.Teardown...) {
+		if len(step.DependsOn) > 0 {
+			return fmt.Errorf("setup or teardown step %s cannot depend on other steps", step.Name)
+		}
+	}
+	steps := make(map[string]*WorkflowStepDefinition)
+	for _, step := range w.Steps {
+		steps[step.Name] = step
+	}
+	for _, step := range w.Steps {
+		for _, dep := range step.DependsOn {
+			if steps[dep] == nil {
+				return fmt.Errorf("workflow step %s depends on unknown step %s", step.Name, dep)
+			}
+		}
+	}
+	// depth first search for cycles
+	visiting := make(map[string]bool)
+	visited := make(map[string]bool)
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		if visited[name] {
+			return nil
+		}
+		if visiting[name] {
+			return fmt.Errorf("workflow has a cycle %v", append(path, name))
+		}
+		visiting[name] = true
+		for _, dep := range steps[name].DependsOn {
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		visiting[name] = false
+		visited[name] = true
+		return nil
+	}
+	for _, step := range w.Steps {
+		if err := visit(step.Name, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
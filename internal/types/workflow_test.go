@@ -0,0 +1,43 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldValidateWorkflow(t *testing.T) {
+	// GIVEN a workflow with dependencies
+	workflow := &Workflow{
+		Name:     "orders",
+		Setup:    []*WorkflowStepDefinition{{Name: "login", Scenario: "login"}},
+		Steps:    []*WorkflowStepDefinition{{Name: "create", Scenario: "create"}, {Name: "get", Scenario: "get", DependsOn: []string{"create"}}},
+		Teardown: []*WorkflowStepDefinition{{Name: "delete", Scenario: "delete"}},
+	}
+	// WHEN validating THEN it should succeed
+	require.NoError(t, workflow.Validate())
+	require.Len(t, workflow.AllSteps(), 4)
+
+	// AND it should fail for unknown dependency
+	workflow.Steps[1].DependsOn = []string{"update"}
+	require.ErrorContains(t, workflow.Validate(), "unknown step update")
+
+	// AND it should fail for dependency on setup step
+	workflow.Steps[1].DependsOn = []string{"login"}
+	require.ErrorContains(t, workflow.Validate(), "unknown step login")
+
+	// AND it should fail for cycle
+	workflow.Steps[0].DependsOn = []string{"get"}
+	workflow.Steps[1].DependsOn = []string{"create"}
+	require.ErrorContains(t, workflow.Validate(), "cycle")
+
+	// AND it should fail for duplicate step
+	workflow.Steps[0].DependsOn = nil
+	workflow.Teardown[0].Name = "create"
+	require.ErrorContains(t, workflow.Validate(), "duplicated")
+
+	// AND it should fail for teardown with dependencies
+	workflow.Teardown[0].Name = "delete"
+	workflow.Teardown[0].DependsOn = []string{"create"}
+	require.ErrorContains(t, workflow.Validate(), "cannot depend")
+}
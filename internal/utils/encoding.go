@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// NewEncodingWriter returns writer that compresses with the content coding
+func NewEncodingWriter(encoding string, writer io.Writer) (io.WriteCloser, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "gzip", "x-gzip":
+		return gzip.NewWriter(writer), nil
+	case "deflate":
+		return zlib.NewWriter(writer), nil
+	case "br":
+		return brotli.NewWriter(writer), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding '%s'", encoding)
+	}
+}
+
+// EncodeBody compresses body with the content coding
+func EncodeBody(encoding string, body []byte) ([]byte, error) {
+	if encoding == "" || strings.EqualFold(encoding, "identity") {
+		return body, nil
+	}
+	var buf bytes.Buffer
+	writer, err := NewEncodingWriter(encoding, &buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = writer.Write(body); err != nil {
+		return nil, err
+	}
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// NewEncodingReader returns reader of contents compressed with the content coding while reading.
+// The reader must be closed to release the compressing goroutine.
+func NewEncodingReader(encoding string, reader io.Reader) (io.ReadCloser, error) {
+	pr, pw := io.Pipe()
+	writer, err := NewEncodingWriter(encoding, pw)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		_, err := io.Copy(writer, reader)
+		if err == nil {
+			err = writer.Close()
+		}
+		_ = pw.CloseWithError(err)
+	}()
+	return pr, nil
+}
+
+// DecodeBody decompresses body with comma separated content codings of Content-Encoding header
+func DecodeBody(contentEncoding string, body []byte) ([]byte, error) {
+	encodings := strings.Split(contentEncoding, ",")
+	// codings are listed in the order they were applied
+	for i := len(encodings) - 1; i >= 0; i-- {
+		var reader io.Reader
+		var err error
+		switch strings.ToLower(strings.TrimSpace(encodings[i])) {
+		case "", "identity":
+			continue
+		case "gzip", "x-gzip":
+			reader, err = gzip.NewReader(bytes.NewReader(body))
+		case "deflate":
+			// deflate is zlib wrapped though some servers send raw deflate
+			if reader, err = zlib.NewReader(bytes.NewReader(body)); err != nil {
+				reader, err = flate.NewReader(bytes.NewReader(body)), nil
+			}
+		case "br":
+			reader = brotli.NewReader(bytes.NewReader(body))
+		default:
+			return nil, fmt.Errorf("unsupported content encoding '%s'", encodings[i])
+		}
+		if err != nil {
+			return nil, err
+		}
+		if body, err = io.ReadAll(reader); err != nil {
+			return nil, err
+		}
+	}
+	return body, nil
+}
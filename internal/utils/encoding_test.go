@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldEncodeAndDecodeBody(t *testing.T) {
+	body := []byte(`{"id":1,"name":"jane","tags":["a","b","c","a","b","c"]}`)
+	for _, encoding := range []string{"gzip", "deflate", "br", "identity", ""} {
+		encoded, err := EncodeBody(encoding, body)
+		require.NoError(t, err)
+		decoded, err := DecodeBody(encoding, encoded)
+		require.NoError(t, err)
+		require.Equal(t, body, decoded, encoding)
+	}
+	// multiple codings are decoded in reverse order
+	gzipped, err := EncodeBody("gzip", body)
+	require.NoError(t, err)
+	encoded, err := EncodeBody("br", gzipped)
+	require.NoError(t, err)
+	decoded, err := DecodeBody("gzip, br", encoded)
+	require.NoError(t, err)
+	require.Equal(t, body, decoded)
+
+	_, err = EncodeBody("zstd", body)
+	require.Error(t, err)
+	_, err = DecodeBody("gzip", body)
+	require.Error(t, err)
+}
+
+func Test_ShouldEncodeWhileReading(t *testing.T) {
+	body := bytes.Repeat([]byte("streamed contents "), 1000)
+	reader, err := NewEncodingReader("gzip", bytes.NewReader(body))
+	require.NoError(t, err)
+	encoded, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	require.Less(t, len(encoded), len(body))
+	decoded, err := DecodeBody("gzip", encoded)
+	require.NoError(t, err)
+	require.Equal(t, body, decoded)
+	_, err = NewEncodingReader("zstd", bytes.NewReader(body))
+	require.Error(t, err)
+}
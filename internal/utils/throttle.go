@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"io"
+	"time"
+)
+
+// throttleTicksPerSecond number of chunks written per second by throttled reader
+const throttleTicksPerSecond = 10
+
+type throttledReader struct {
+	reader         io.Reader
+	bytesPerSecond int64
+	flush          func()
+	started        time.Time
+	read           int64
+}
+
+// NewThrottledReader returns reader limited to bytesPerSecond that calls flush, if any, before waiting
+func NewThrottledReader(reader io.Reader, bytesPerSecond int64, flush func()) io.Reader {
+	if bytesPerSecond <= 0 {
+		return reader
+	}
+	return &throttledReader{reader: reader, bytesPerSecond: bytesPerSecond, flush: flush}
+}
+
+// Read bytes
+func (t *throttledReader) Read(p []byte) (n int, err error) {
+	if t.started.IsZero() {
+		t.started = time.Now()
+	}
+	chunk := t.bytesPerSecond / throttleTicksPerSecond
+	if chunk < 1 {
+		chunk = 1
+	}
+	if int64(len(p)) > chunk {
+		p = p[:chunk]
+	}
+	n, err = t.reader.Read(p)
+	t.read += int64(n)
+	expected := time.Duration(float64(t.read) / float64(t.bytesPerSecond) * float64(time.Second))
+	if wait := expected - time.Since(t.started); wait > 0 {
+		if t.flush != nil {
+			t.flush()
+		}
+		time.Sleep(wait)
+	}
+	return n, err
+}
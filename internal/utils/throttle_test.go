@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldThrottleReader(t *testing.T) {
+	// GIVEN 300 bytes throttled to 1000 bytes/sec
+	flushed := 0
+	reader := NewThrottledReader(bytes.NewReader(make([]byte, 300)), 1000, func() { flushed++ })
+	started := time.Now()
+
+	// WHEN reading all bytes
+	b, err := io.ReadAll(reader)
+
+	// THEN it should take about 300ms and flush between chunks
+	require.NoError(t, err)
+	require.Len(t, b, 300)
+	require.True(t, time.Since(started) >= 250*time.Millisecond)
+	require.True(t, flushed >= 2)
+	// AND reader without limit should not be wrapped
+	plain := bytes.NewReader(nil)
+	require.Equal(t, io.Reader(plain), NewThrottledReader(plain, 0, nil))
+}
@@ -0,0 +1,107 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+)
+
+type hostOverridesKey struct{}
+
+// WithHostOverrides returns context whose requests dial overridden hosts, e.g. for a producer contract run
+func WithHostOverrides(ctx context.Context, overrides map[string]string) context.Context {
+	if len(overrides) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, hostOverridesKey{}, overrides)
+}
+
+// HostOverridesFromContext returns host overrides added by WithHostOverrides
+func HostOverridesFromContext(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+	overrides, _ := ctx.Value(hostOverridesKey{}).(map[string]string)
+	return overrides
+}
+
+// ResolveHostOverride returns dial address for host:port, where override may be an IP, a host or a host:port
+func ResolveHostOverride(overrides map[string]string, addr string) string {
+	if len(overrides) == 0 {
+		return addr
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	target := ""
+	for k, v := range overrides {
+		if strings.EqualFold(k, host) {
+			target = strings.TrimSpace(v)
+			break
+		}
+	}
+	if target == "" {
+		return addr
+	}
+	if _, _, err = net.SplitHostPort(target); err == nil {
+		return target
+	}
+	return net.JoinHostPort(strings.Trim(target, "[]"), port)
+}
+
+// CheckHostOverridesWithUpstreamProxy returns error when an overridden host would be sent through upstream proxy
+// because the proxy resolves the CONNECT target itself, so overrides only apply to bypassed hosts
+func CheckHostOverridesWithUpstreamProxy(config *types.Configuration, overrides map[string]string) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+	proxyURL, err := UpstreamProxyURL(config)
+	if err != nil || proxyURL == nil {
+		return err
+	}
+	hosts := make([]string, 0)
+	for host := range overrides {
+		if !BypassUpstreamProxy(config.UpstreamProxy.Bypass, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	if len(hosts) == 0 {
+		return nil
+	}
+	sort.Strings(hosts)
+	return fmt.Errorf("host overrides for %s cannot be applied through upstream proxy %s, add them to upstream_proxy.bypass",
+		strings.Join(hosts, ", "), proxyURL.Redacted())
+}
+
+// ConfigureHostOverrides dials overridden hosts without changing Host header or TLS server name of requests
+func ConfigureHostOverrides(overrides map[string]string, transport *http.Transport) bool {
+	if len(overrides) == 0 {
+		return false
+	}
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	transport.DialContext = func(ctx context.Context, network string, addr string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, ResolveHostOverride(overrides, addr))
+	}
+	return true
+}
+
+func mergeHostOverrides(defaults map[string]string, overrides map[string]string) map[string]string {
+	if len(overrides) == 0 {
+		return defaults
+	}
+	res := make(map[string]string, len(defaults)+len(overrides))
+	for k, v := range defaults {
+		res[strings.ToLower(k)] = v
+	}
+	for k, v := range overrides {
+		res[strings.ToLower(k)] = v
+	}
+	return res
+}
@@ -0,0 +1,59 @@
+package web
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldResolveHostOverride(t *testing.T) {
+	overrides := map[string]string{"api.example.com": "10.0.3.7", "Pay.Example.com": "127.0.0.1:8443", "v6.example.com": "::1"}
+	require.Equal(t, "10.0.3.7:443", ResolveHostOverride(overrides, "api.example.com:443"))
+	require.Equal(t, "127.0.0.1:8443", ResolveHostOverride(overrides, "pay.example.com:443"))
+	require.Equal(t, "[::1]:80", ResolveHostOverride(overrides, "v6.example.com:80"))
+	require.Equal(t, "other.example.com:443", ResolveHostOverride(overrides, "other.example.com:443"))
+	require.Equal(t, "api.example.com:443", ResolveHostOverride(nil, "api.example.com:443"))
+}
+
+func Test_ShouldDialHostOverridesKeepingHostHeader(t *testing.T) {
+	// GIVEN a local server
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	hosts := make(chan string, 2)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hosts <- r.Host
+		_, _ = w.Write([]byte("ok"))
+	})}
+	go func() { _ = server.Serve(listener) }()
+	defer func() { _ = server.Close() }()
+	// AND config that overrides api.example.com with address of local server
+	config := &types.Configuration{HostOverrides: map[string]string{"api.example.com": listener.Addr().String()}}
+	client := NewHTTPClient(config, NewAuthAdapter(config))
+
+	// WHEN calling overridden host
+	status, _, body, _, err := client.Handle(context.Background(), "http://api.example.com/todos",
+		"GET", http.Header{}, nil, nil)
+
+	// THEN local server should receive request with original Host header
+	require.NoError(t, err)
+	require.Equal(t, 200, status)
+	b, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.Equal(t, "ok", string(b))
+	require.Equal(t, "api.example.com", <-hosts)
+
+	// WHEN overriding host only in context of request
+	config.HostOverrides = nil
+	ctx := WithHostOverrides(context.Background(), map[string]string{"preview.example.com": listener.Addr().String()})
+	status, _, _, _, err = client.Handle(ctx, "http://preview.example.com/todos", "GET", http.Header{}, nil, nil)
+
+	// THEN context overrides should be used
+	require.NoError(t, err)
+	require.Equal(t, 200, status)
+	require.Equal(t, "preview.example.com", <-hosts)
+}
@@ -2,6 +2,8 @@ package web
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"github.com/bhatti/api-mock-service/internal/types"
 	"github.com/bhatti/api-mock-service/internal/utils"
@@ -11,7 +13,9 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -127,7 +131,10 @@ func (w *DefaultHTTPClient) execute(
 
 	authd, info, authErr := w.authAdapter.HandleAuth(req)
 	headers = req.Header
-	client := httpClient(w.config)
+	client, err := httpClient(w.config, HostOverridesFromContext(req.Context()))
+	if err != nil {
+		return 500, "", nil, make(http.Header), err
+	}
 	resp, err := client.Do(req)
 
 	if err != nil {
@@ -224,37 +231,101 @@ func getProxyEnv() map[string]string {
 	return proxies
 }
 
-func httpClient(config *types.Configuration) *http.Client {
-	if config.ProxyURL == "" {
-		return &http.Client{}
+// maxCachedTransports bounds transports kept for distinct upstream proxy and host overrides, e.g. of contract runs
+const maxCachedTransports = 32
+
+// transportCache keeps transports by upstream proxy and host overrides so that connections are kept alive across
+// requests and evicts the oldest transport when full
+type transportCache struct {
+	transports map[string]*http.Transport
+	keys       []string
+	lock       sync.Mutex
+}
+
+var transports = &transportCache{transports: make(map[string]*http.Transport)}
+
+// load returns cached transport, where a nil transport means default client
+func (tc *transportCache) load(key string) (*http.Transport, bool) {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+	transport, ok := tc.transports[key]
+	return transport, ok
+}
+
+// loadOrStore returns existing transport of key or stores the given one
+func (tc *transportCache) loadOrStore(key string, transport *http.Transport) *http.Transport {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+	if existing, ok := tc.transports[key]; ok {
+		return existing
 	}
-	proxyURL, err := url.Parse(config.ProxyURL)
+	if len(tc.keys) >= maxCachedTransports {
+		oldest := tc.keys[0]
+		tc.keys = tc.keys[1:]
+		if evicted := tc.transports[oldest]; evicted != nil {
+			evicted.CloseIdleConnections()
+		}
+		delete(tc.transports, oldest)
+	}
+	tc.transports[key] = transport
+	tc.keys = append(tc.keys, key)
+	return transport
+}
+
+func httpClient(config *types.Configuration, hostOverrides map[string]string) (*http.Client, error) {
+	hostOverrides = mergeHostOverrides(config.HostOverrides, hostOverrides)
+	key := transportKey(config, hostOverrides)
+	if transport, ok := transports.load(key); ok {
+		if transport == nil {
+			return &http.Client{}, nil
+		}
+		return &http.Client{Transport: transport}, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	proxied, err := ConfigureUpstreamProxy(config, transport)
 	if err != nil {
 		log.WithFields(log.Fields{
 			"Component": "DefaultHTTPClient",
-			"IP":        getRemoteIPAddressFromURL(config.ProxyURL),
-			"Error":     err,
-			"Proxy":     config.ProxyURL}).Warn("Failed to parse proxy header")
-		return &http.Client{}
+			"Error":     err}).Warn("Failed to configure upstream proxy")
+		return &http.Client{}, nil
 	}
-
-	headers := make(http.Header, 0)
-	headers.Set("User-Agent", config.UserAgent)
-
-	//adding the proxy settings to the Transport object
-	transport := &http.Transport{
-		Proxy:              http.ProxyURL(proxyURL),
-		ProxyConnectHeader: headers,
+	if err = CheckHostOverridesWithUpstreamProxy(config, hostOverrides); err != nil {
+		return nil, err
+	}
+	overridden := ConfigureHostOverrides(hostOverrides, transport)
+	if !proxied && !overridden {
+		transports.loadOrStore(key, nil)
+		return &http.Client{}, nil
 	}
 
 	log.WithFields(log.Fields{
 		"Component": "DefaultHTTPClient",
 		//"LocalIP":   getLocalIPAddresses(),
-		"EnvProxy": getProxyEnv(),
-		"Proxy":    proxyURL}).Info("Http client using proxy")
+		"EnvProxy":      getProxyEnv(),
+		"UpstreamProxy": proxied,
+		"Bypass":        config.UpstreamProxy.Bypass,
+		"HostOverrides": hostOverrides}).Debug("Http client using upstream proxy or host overrides")
 	return &http.Client{
-		Transport: transport,
+		Transport: transports.loadOrStore(key, transport),
+	}, nil
+}
+
+// transportKey identifies transport by upstream proxy settings and host overrides, hashing proxy credentials
+// so that they aren't kept in memory in plain text
+func transportKey(config *types.Configuration, hostOverrides map[string]string) string {
+	var sb strings.Builder
+	credentials := sha256.Sum256([]byte(config.UpstreamProxy.Username + "\x00" + config.UpstreamProxy.Password))
+	sb.WriteString(config.UpstreamProxy.URL + "|" + config.ProxyURL + "|" + hex.EncodeToString(credentials[:]) + "|" +
+		strings.Join(config.UpstreamProxy.Bypass, ",") + "|")
+	hosts := make([]string, 0, len(hostOverrides))
+	for host := range hostOverrides {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		sb.WriteString(host + "=" + hostOverrides[host] + ",")
 	}
+	return sb.String()
 }
 
 func isInternalParamKeys(k string) bool {
@@ -3,8 +3,13 @@ package web
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"github.com/stretchr/testify/require"
 	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 
 	"github.com/bhatti/api-mock-service/internal/types"
@@ -160,15 +165,101 @@ func Test_ShouldNotExecuteHttpClientWithNilRequest(t *testing.T) {
 }
 
 func Test_ShouldGetHttpClientWithProxy(t *testing.T) {
-	config := &types.Configuration{ProxyURL: "xyz"}
-	require.NotNil(t, httpClient(config))
-	config = &types.Configuration{ProxyURL: "ftp://localhost:8000"}
-	require.NotNil(t, httpClient(config))
-	config = &types.Configuration{ProxyURL: "http://localhost:8000"}
-	require.NotNil(t, httpClient(config))
+	for _, proxyURL := range []string{"xyz", "ftp://localhost:8000", "http://localhost:8000"} {
+		client, err := httpClient(&types.Configuration{ProxyURL: proxyURL}, nil)
+		require.NoError(t, err)
+		require.NotNil(t, client)
+	}
+}
+
+func Test_ShouldRejectHostOverridesThroughUpstreamProxy(t *testing.T) {
+	// GIVEN upstream proxy that bypasses internal hosts
+	config := &types.Configuration{}
+	config.UpstreamProxy.URL = "http://proxy.example.com:3128"
+	config.UpstreamProxy.Username = "user"
+	config.UpstreamProxy.Password = "secret"
+	config.UpstreamProxy.Bypass = []string{"*.internal"}
+
+	// WHEN overriding a host that is sent through the proxy
+	_, err := httpClient(config, map[string]string{"api.example.com": "10.0.0.1"})
+
+	// THEN it should fail instead of ignoring the override
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "api.example.com")
+	require.NotContains(t, err.Error(), "secret")
+
+	// WHEN overriding a bypassed host
+	client, err := httpClient(config, map[string]string{"api.internal": "10.0.0.1"})
+
+	// THEN it should dial the override directly
+	require.NoError(t, err)
+	require.NotNil(t, client.Transport)
+	// AND transport key should not contain proxy password
+	require.NotContains(t, transportKey(config, nil), "secret")
+}
+
+func Test_ShouldEvictOldestCachedTransport(t *testing.T) {
+	// GIVEN a full transport cache
+	cache := &transportCache{transports: make(map[string]*http.Transport)}
+	first := &http.Transport{}
+	require.Same(t, first, cache.loadOrStore("first", first))
+	for i := 1; i < maxCachedTransports; i++ {
+		cache.loadOrStore(fmt.Sprintf("key%d", i), &http.Transport{})
+	}
+	_, ok := cache.load("first")
+	require.True(t, ok)
+
+	// WHEN storing another transport
+	cache.loadOrStore("last", &http.Transport{})
+
+	// THEN oldest transport should be evicted
+	_, ok = cache.load("first")
+	require.False(t, ok)
+	_, ok = cache.load("last")
+	require.True(t, ok)
+	require.Equal(t, maxCachedTransports, len(cache.transports))
 }
 
 func newTestNewHTTPClient() *DefaultHTTPClient {
 	c := types.Configuration{}
 	return NewHTTPClient(&c, NewAuthAdapter(&c))
 }
+
+func Test_ShouldReuseTransportForSameProxyAndHostOverrides(t *testing.T) {
+	// GIVEN a server that counts new connections
+	var newConns int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&newConns, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+	config := &types.Configuration{}
+	overrides := map[string]string{"reuse.example.com": server.Listener.Addr().String()}
+
+	// WHEN sending multiple requests with the same host overrides
+	for i := 0; i < 3; i++ {
+		client, err := httpClient(config, overrides)
+		require.NoError(t, err)
+		res, err := client.Get("http://reuse.example.com/")
+		require.NoError(t, err)
+		_, _ = io.ReadAll(res.Body)
+		_ = res.Body.Close()
+	}
+
+	// THEN connection should be kept alive and transports should be shared per override set
+	require.Equal(t, int32(1), atomic.LoadInt32(&newConns))
+	client, err := httpClient(config, overrides)
+	require.NoError(t, err)
+	same, err := httpClient(config, overrides)
+	require.NoError(t, err)
+	other, err := httpClient(config, map[string]string{"other.example.com": "127.0.0.1:1"})
+	require.NoError(t, err)
+	require.Same(t, client.Transport, same.Transport)
+	require.NotSame(t, client.Transport, other.Transport)
+	require.False(t, client.Transport.(*http.Transport).DisableKeepAlives)
+}
@@ -1,6 +1,7 @@
 package web
 
 import (
+	"crypto/tls"
 	"embed"
 	"fmt"
 	"github.com/labstack/echo/v4"
@@ -89,6 +90,9 @@ func (w *stubWebServer) TRACE(_ string, _ HandlerFunc, _ ...echo.MiddlewareFunc)
 	return &echo.Route{}
 }
 
+func (w *stubWebServer) EnableTLS(*tls.Config) {
+}
+
 func (w *stubWebServer) Start(string) {
 }
 
@@ -0,0 +1,95 @@
+package web
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/labstack/echo/v4"
+)
+
+// CertificateIssuer issues server certificate for the hosts when cert and key files are not configured
+type CertificateIssuer func(hosts []string) (*tls.Certificate, error)
+
+// DefaultTLSHosts returns names of issued server certificate when hosts are not configured
+func DefaultTLSHosts() []string {
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		hosts = append(hosts, hostname)
+	}
+	return hosts
+}
+
+// BuildServerTLSConfig builds TLS config of mock port with HTTP/2 and optional verification of client certificates
+func BuildServerTLSConfig(config *types.TLSConfig, issuer CertificateIssuer) (*tls.Config, error) {
+	var cert tls.Certificate
+	if config.CertFile != "" || config.KeyFile != "" {
+		var err error
+		if cert, err = tls.LoadX509KeyPair(config.CertFile, config.KeyFile); err != nil {
+			return nil, fmt.Errorf("failed to load tls certificate '%s' due to %w", config.CertFile, err)
+		}
+	} else {
+		hosts := config.Hosts
+		if len(hosts) == 0 {
+			hosts = DefaultTLSHosts()
+		}
+		issued, err := issuer(hosts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to issue tls certificate for %v due to %w", hosts, err)
+		}
+		cert = *issued
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+	if config.ClientCAFile != "" {
+		pem, err := os.ReadFile(config.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA '%s' due to %w", config.ClientCAFile, err)
+		}
+		tlsConfig.ClientCAs = x509.NewCertPool()
+		if !tlsConfig.ClientCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in client CA '%s'", config.ClientCAFile)
+		}
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	switch strings.ToLower(config.ClientAuth) {
+	case "":
+	case "none":
+		tlsConfig.ClientAuth = tls.NoClientCert
+	case "request":
+		tlsConfig.ClientAuth = tls.RequestClientCert
+	case "verify_if_given":
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	case "require":
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("unsupported client_auth '%s'", config.ClientAuth)
+	}
+	if tlsConfig.ClientCAs == nil && (tlsConfig.ClientAuth == tls.VerifyClientCertIfGiven ||
+		tlsConfig.ClientAuth == tls.RequireAndVerifyClientCert) {
+		return nil, fmt.Errorf("client_auth '%s' requires client_ca_file", config.ClientAuth)
+	}
+	return tlsConfig, nil
+}
+
+// ClientCertificateMiddleware exposes subject of client certificate as request headers for matchers and templates
+// after removing the same headers sent by the client.
+func ClientCertificateMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		req.Header.Del(types.MockClientCertSubject)
+		req.Header.Del(types.MockClientCertCommonName)
+		if req.TLS != nil && len(req.TLS.PeerCertificates) > 0 {
+			subject := req.TLS.PeerCertificates[0].Subject
+			req.Header.Set(types.MockClientCertSubject, subject.String())
+			req.Header.Set(types.MockClientCertCommonName, subject.CommonName)
+		}
+		return next(c)
+	}
+}
@@ -0,0 +1,165 @@
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldBuildServerTLSConfig(t *testing.T) {
+	ca, caKey := newTestCertificate(t, "test-ca", true, nil, nil)
+	var issuedHosts []string
+	issuer := func(hosts []string) (*tls.Certificate, error) {
+		issuedHosts = hosts
+		return newTestTLSCertificate(t, "mock.local", ca, caKey, x509.ExtKeyUsageServerAuth), nil
+	}
+	// GIVEN tls config without cert files
+	config := &types.TLSConfig{Enabled: true, Hosts: []string{"mock.local"}}
+
+	// WHEN building tls config
+	tlsConfig, err := BuildServerTLSConfig(config, issuer)
+
+	// THEN certificate should be issued with HTTP/2 enabled
+	require.NoError(t, err)
+	require.Equal(t, []string{"mock.local"}, issuedHosts)
+	require.Len(t, tlsConfig.Certificates, 1)
+	require.Equal(t, []string{"h2", "http/1.1"}, tlsConfig.NextProtos)
+	require.Equal(t, tls.NoClientCert, tlsConfig.ClientAuth)
+
+	// WHEN client CA is configured
+	config.ClientCAFile = writeTestPEM(t, ca.Raw)
+	tlsConfig, err = BuildServerTLSConfig(config, issuer)
+	// THEN client certificates should be required
+	require.NoError(t, err)
+	require.Equal(t, tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth)
+	config.ClientAuth = "verify_if_given"
+	tlsConfig, err = BuildServerTLSConfig(config, issuer)
+	require.NoError(t, err)
+	require.Equal(t, tls.VerifyClientCertIfGiven, tlsConfig.ClientAuth)
+
+	// WHEN verification is configured without client CA or with unknown mode
+	_, err = BuildServerTLSConfig(&types.TLSConfig{ClientAuth: "require"}, issuer)
+	// THEN it should fail
+	require.Error(t, err)
+	_, err = BuildServerTLSConfig(&types.TLSConfig{ClientAuth: "sometimes"}, issuer)
+	require.Error(t, err)
+	_, err = BuildServerTLSConfig(&types.TLSConfig{CertFile: "missing.pem", KeyFile: "missing.key"}, issuer)
+	require.Error(t, err)
+}
+
+func Test_ShouldServeHTTP2WithClientCertificate(t *testing.T) {
+	// GIVEN a mock port with mTLS
+	ca, caKey := newTestCertificate(t, "test-ca", true, nil, nil)
+	tlsConfig, err := BuildServerTLSConfig(&types.TLSConfig{
+		Enabled:      true,
+		ClientCAFile: writeTestPEM(t, ca.Raw),
+	}, func(hosts []string) (*tls.Certificate, error) {
+		return newTestTLSCertificate(t, "127.0.0.1", ca, caKey, x509.ExtKeyUsageServerAuth), nil
+	})
+	require.NoError(t, err)
+	e := echo.New()
+	e.Use(ClientCertificateMiddleware)
+	e.GET("/whoami", func(c echo.Context) error {
+		return c.String(http.StatusOK, c.Request().Header.Get(types.MockClientCertCommonName))
+	})
+	server := httptest.NewUnstartedServer(e)
+	server.TLS = tlsConfig
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	clientCert := newTestTLSCertificate(t, "billing-service", ca, caKey, x509.ExtKeyUsageClientAuth)
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: roots, Certificates: []tls.Certificate{*clientCert}},
+		ForceAttemptHTTP2: true,
+	}}
+
+	// WHEN calling with client certificate and spoofed header
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/whoami", nil)
+	require.NoError(t, err)
+	req.Header.Set(types.MockClientCertCommonName, "admin")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	// THEN common name of the certificate should be exposed over HTTP/2
+	require.Equal(t, 2, resp.ProtoMajor)
+	require.Equal(t, "billing-service", string(body))
+
+	// WHEN calling without client certificate
+	client = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+	_, err = client.Get(server.URL + "/whoami")
+	// THEN handshake should fail
+	require.Error(t, err)
+}
+
+func newTestCertificate(
+	t *testing.T,
+	commonName string,
+	isCA bool,
+	parent *x509.Certificate,
+	parentKey *ecdsa.PrivateKey,
+	usages ...x509.ExtKeyUsage) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName, Organization: []string{"Test"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           usages,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if ip := net.ParseIP(commonName); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(crand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func newTestTLSCertificate(
+	t *testing.T,
+	commonName string,
+	ca *x509.Certificate,
+	caKey *ecdsa.PrivateKey,
+	usage x509.ExtKeyUsage) *tls.Certificate {
+	cert, key := newTestCertificate(t, commonName, false, ca, caKey, usage)
+	return &tls.Certificate{Certificate: [][]byte{cert.Raw}, PrivateKey: key, Leaf: cert}
+}
+
+func writeTestPEM(t *testing.T, der []byte) string {
+	fileName := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(fileName, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644))
+	return fileName
+}
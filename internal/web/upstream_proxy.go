@@ -0,0 +1,103 @@
+package web
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bhatti/api-mock-service/internal/types"
+)
+
+var upstreamProxySchemes = map[string]bool{"http": true, "https": true, "socks5": true, "socks5h": true}
+
+// UpstreamProxyURL returns url of upstream proxy with credentials or nil when no proxy is configured
+func UpstreamProxyURL(config *types.Configuration) (*url.URL, error) {
+	rawURL := config.UpstreamProxy.URL
+	if rawURL == "" {
+		rawURL = config.ProxyURL
+	}
+	if rawURL == "" {
+		return nil, nil
+	}
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse upstream proxy %s due to %w", rawURL, err)
+	}
+	if !upstreamProxySchemes[strings.ToLower(proxyURL.Scheme)] || proxyURL.Host == "" {
+		return nil, fmt.Errorf("unsupported upstream proxy %s, expected http, https, socks5 or socks5h url", rawURL)
+	}
+	if config.UpstreamProxy.Username != "" {
+		proxyURL.User = url.UserPassword(config.UpstreamProxy.Username, config.UpstreamProxy.Password)
+	}
+	return proxyURL, nil
+}
+
+// UpstreamProxy returns proxy function for http.Transport that calls bypassed hosts directly
+func UpstreamProxy(config *types.Configuration) (func(*http.Request) (*url.URL, error), error) {
+	proxyURL, err := UpstreamProxyURL(config)
+	if err != nil || proxyURL == nil {
+		return nil, err
+	}
+	bypass := config.UpstreamProxy.Bypass
+	return func(req *http.Request) (*url.URL, error) {
+		if BypassUpstreamProxy(bypass, req.URL.Hostname()) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}, nil
+}
+
+// ConfigureUpstreamProxy routes transport through upstream proxy and returns false when no proxy is configured
+func ConfigureUpstreamProxy(config *types.Configuration, transport *http.Transport) (bool, error) {
+	proxyFunc, err := UpstreamProxy(config)
+	if err != nil || proxyFunc == nil {
+		return false, err
+	}
+	headers := make(http.Header)
+	headers.Set("User-Agent", config.UserAgent)
+	transport.Proxy = proxyFunc
+	transport.ProxyConnectHeader = headers
+	return true, nil
+}
+
+// BypassUpstreamProxy checks host against bypass list of hosts, domains, IPs and CIDRs
+func BypassUpstreamProxy(bypass []string, host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	ip := net.ParseIP(host)
+	for _, next := range bypass {
+		pattern := strings.ToLower(strings.TrimSpace(next))
+		if pattern == "" {
+			continue
+		}
+		if pattern == "*" {
+			return true
+		}
+		if _, cidr, err := net.ParseCIDR(pattern); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if h, _, err := net.SplitHostPort(pattern); err == nil {
+			pattern = h
+		}
+		if ip != nil {
+			if patternIP := net.ParseIP(pattern); patternIP != nil && patternIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		if strings.HasPrefix(pattern, "*.") || strings.HasPrefix(pattern, ".") {
+			if strings.HasSuffix(host, "."+strings.TrimLeft(pattern, "*.")) {
+				return true
+			}
+			continue
+		}
+		if host == pattern || strings.HasSuffix(host, "."+pattern) {
+			return true
+		}
+	}
+	return false
+}
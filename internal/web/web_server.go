@@ -1,6 +1,7 @@
 package web
 
 import (
+	"crypto/tls"
 	"embed"
 	"net/http"
 	"os"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/net/http2"
 )
 
 // HandlerFunc defines a function to serve HTTP requests.
@@ -34,6 +36,7 @@ type Server interface {
 	PATCH(path string, h HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
 	TRACE(path string, h HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
 	AddMiddleware(m echo.MiddlewareFunc)
+	EnableTLS(tlsConfig *tls.Config)
 	Start(address string)
 	Static(path string, dir string)
 	Embed(content embed.FS, path string, dir string)
@@ -42,8 +45,9 @@ type Server interface {
 
 // DefaultWebServer defines default web server
 type DefaultWebServer struct {
-	config *types.Configuration
-	e      *echo.Echo
+	config    *types.Configuration
+	e         *echo.Echo
+	tlsConfig *tls.Config
 }
 
 // NewDefaultWebServer creates new instance of web server
@@ -59,6 +63,7 @@ func NewDefaultWebServer(config *types.Configuration) Server {
 	}
 	ws.e.Use(middleware.LoggerWithConfig(defaultLoggerConfig))
 	ws.e.Use(middleware.Recover())
+	ws.e.Use(ClientCertificateMiddleware)
 
 	ws.e.HTTPErrorHandler = func(err error, c echo.Context) {
 		ws.e.DefaultHTTPErrorHandler(err, c)
@@ -162,9 +167,22 @@ func (w *DefaultWebServer) Embed(content embed.FS, path string, dir string) {
 	w.e.GET(path, contentHandler)
 }
 
+// EnableTLS serves HTTPS with HTTP/2 on start
+func (w *DefaultWebServer) EnableTLS(tlsConfig *tls.Config) {
+	w.tlsConfig = tlsConfig
+}
+
 // Start - starts web server
 func (w *DefaultWebServer) Start(address string) {
-	w.e.Logger.Fatal(w.e.Start(address))
+	if w.tlsConfig != nil {
+		w.e.TLSServer.Addr = address
+		w.e.TLSServer.TLSConfig = w.tlsConfig
+		w.e.Logger.Fatal(w.e.StartServer(w.e.TLSServer))
+	} else if w.config.TLS.H2C {
+		w.e.Logger.Fatal(w.e.StartH2CServer(address, &http2.Server{}))
+	} else {
+		w.e.Logger.Fatal(w.e.Start(address))
+	}
 }
 
 // Stop - stops web server
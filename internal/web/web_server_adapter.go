@@ -3,6 +3,7 @@ package web
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"embed"
 	"fmt"
 	"github.com/bhatti/api-mock-service/internal/types"
@@ -125,6 +126,10 @@ func (a *ServerAdapter) appendHandler(mph MethodPathHandler) {
 	})
 }
 
+// EnableTLS is not applicable to adapter
+func (a *ServerAdapter) EnableTLS(*tls.Config) {
+}
+
 // Start server
 func (a *ServerAdapter) Start(string) {
 }